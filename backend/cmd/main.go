@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,11 +12,18 @@ import (
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
+	"story2video-backend/internal/auth"
 	"story2video-backend/internal/conf"
 	"story2video-backend/internal/data"
+	"story2video-backend/internal/health"
 	"story2video-backend/internal/router"
+	"story2video-backend/internal/rpc/apipb"
+	"story2video-backend/internal/rpc/apiserver"
+	"story2video-backend/internal/rpc/interceptor"
 	"story2video-backend/internal/service"
+	"story2video-backend/internal/tracing"
 	pkgLogger "story2video-backend/pkg/logger"
 )
 
@@ -40,6 +48,16 @@ func main() {
 		_ = log.Sync()
 	}(log)
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, "story2video-api")
+	if err != nil {
+		panic(fmt.Errorf("init tracing: %w", err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warn("shutdown tracing", zap.Error(err))
+		}
+	}()
+
 	dataLayer, cleanup, err := data.NewData(ctx, cfg, log)
 	if err != nil {
 		panic(fmt.Errorf("init data: %w", err))
@@ -49,16 +67,59 @@ func main() {
 	homeService := service.NewHomeService(cfg, dataLayer, log)
 	storyService := service.NewStoryService(cfg, dataLayer, log)
 	shotService := service.NewShotService(cfg, dataLayer, log)
+	operationService := service.NewOperationService(cfg, dataLayer, log)
+	batchService := service.NewBatchService(cfg, dataLayer, log)
+	exportService := service.NewExportService(cfg, dataLayer, log)
+	importService := service.NewImportService(cfg, dataLayer, log)
+	forkService := service.NewForkService(cfg, dataLayer, log)
+	moderationService := service.NewModerationService(cfg, dataLayer, log)
 	defer func() {
 		if err := homeService.Close(); err != nil {
 			log.Warn("close home service", zap.Error(err))
 		}
+		if err := operationService.Close(); err != nil {
+			log.Warn("close operation service", zap.Error(err))
+		}
+		if err := batchService.Close(); err != nil {
+			log.Warn("close batch service", zap.Error(err))
+		}
 		if err := shotService.Close(); err != nil {
 			log.Warn("close shot service", zap.Error(err))
 		}
+		if err := exportService.Close(); err != nil {
+			log.Warn("close export service", zap.Error(err))
+		}
+		if err := importService.Close(); err != nil {
+			log.Warn("close import service", zap.Error(err))
+		}
+		if err := forkService.Close(); err != nil {
+			log.Warn("close fork service", zap.Error(err))
+		}
+		if err := moderationService.Close(); err != nil {
+			log.Warn("close moderation service", zap.Error(err))
+		}
 	}()
 
-	engine := router.NewRouter(cfg, log, dataLayer, homeService, storyService, shotService)
+	if cfg.Scheduler.Enabled {
+		scheduler := service.NewStoryScheduler(cfg, dataLayer, log)
+		schedulerCtx, stopScheduler := context.WithCancel(ctx)
+		schedulerDone := make(chan struct{})
+		go func() {
+			defer close(schedulerDone)
+			scheduler.Run(schedulerCtx)
+		}()
+		defer func() {
+			stopScheduler()
+			<-schedulerDone
+			if err := scheduler.Close(); err != nil {
+				log.Warn("close story scheduler", zap.Error(err))
+			}
+		}()
+	}
+
+	authn := auth.NewAuthenticator(cfg.Moderation.AdminUserIDs)
+	checker := health.NewChecker(time.Duration(cfg.Health.TimeoutSeconds)*time.Second, health.Dependencies(cfg, dataLayer)...)
+	engine := router.NewRouter(cfg, log, authn, checker, homeService, storyService, shotService, operationService, batchService, exportService, importService, forkService, moderationService)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
@@ -73,6 +134,33 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort > 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+		if err != nil {
+			panic(fmt.Errorf("listen grpc: %w", err))
+		}
+		grpcServer = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				interceptor.TracingInterceptor(),
+				interceptor.MetricsInterceptor(),
+				interceptor.LoggingInterceptor(log),
+				interceptor.RecoveryInterceptor(log),
+				interceptor.AuthInterceptor(authn),
+			),
+		)
+		api := apiserver.NewServer(cfg, homeService, storyService, shotService, operationService, batchService, log)
+		apipb.RegisterStoryServiceServer(grpcServer, api)
+		apipb.RegisterOperationServiceServer(grpcServer, api)
+		apipb.RegisterBatchServiceServer(grpcServer, api)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatal("grpc serve failed", zap.Error(err))
+			}
+		}()
+		log.Info("grpc api server started", zap.Int("port", cfg.Server.GRPCPort))
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -82,5 +170,8 @@ func main() {
 	if err := srv.Shutdown(ctxShutDown); err != nil {
 		log.Error("server shutdown", zap.Error(err))
 	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
 	log.Info("server exited")
 }
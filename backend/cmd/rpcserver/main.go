@@ -1,20 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"story2video-backend/internal/conf"
+	"story2video-backend/internal/health"
+	"story2video-backend/internal/metrics"
 	"story2video-backend/internal/rpc/interceptor"
 	"story2video-backend/internal/rpc/modelpb"
 	"story2video-backend/internal/rpc/modelserver"
+	"story2video-backend/internal/tracing"
 	pkgLogger "story2video-backend/pkg/logger"
 )
 
@@ -36,6 +45,16 @@ func main() {
 		_ = log.Sync()
 	}(log)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "story2video-rpcserver")
+	if err != nil {
+		panic(fmt.Errorf("init tracing: %w", err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warn("shutdown tracing", zap.Error(err))
+		}
+	}()
+
 	server, err := modelserver.NewServer(cfg.ModelService, log)
 	if err != nil {
 		panic(fmt.Errorf("init model server: %w", err))
@@ -54,12 +73,27 @@ func main() {
 	limiter := interceptor.NewRateLimiter(rateLimit)
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			interceptor.TracingInterceptor(),
+			interceptor.MetricsInterceptor(),
 			interceptor.RateLimitInterceptor(limiter),
 			interceptor.LoggingInterceptor(log),
 			interceptor.RecoveryInterceptor(log),
 		),
 	)
 	modelpb.RegisterStoryboardServiceServer(grpcServer, server)
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	probeTimeout := time.Duration(cfg.Health.TimeoutSeconds) * time.Second
+	probeInterval := time.Duration(cfg.Health.ProbeIntervalSeconds) * time.Second
+	if probeInterval <= 0 {
+		probeInterval = 15 * time.Second
+	}
+	modelProbeURL := strings.TrimRight(cfg.ModelService.BaseURL, "/") + "/" + strings.TrimLeft(cfg.Health.ModelProbePath, "/")
+	checker := health.NewChecker(probeTimeout, health.HTTP("model_service", modelProbeURL, &http.Client{Timeout: probeTimeout}))
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go health.Watch(watchCtx, healthServer, checker, probeInterval, log, "", modelpb.StoryboardService_ServiceDesc.ServiceName)
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
@@ -68,10 +102,17 @@ func main() {
 	}()
 	log.Info("grpc model server started", zap.String("addr", cfg.GRPC.Addr), zap.String("model_base_url", cfg.ModelService.BaseURL))
 
+	if cfg.Metrics.Enabled && cfg.Metrics.RPCServerAddr != "" {
+		stopMetrics := metrics.Serve(cfg.Metrics.RPCServerAddr, log)
+		defer stopMetrics()
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
 
+	stopWatch()
+	healthServer.Shutdown()
 	grpcServer.GracefulStop()
 	log.Info("grpc model server stopped")
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/service"
+)
+
+type lane struct {
+	name    string
+	reader  *kafka.Reader
+	offsets *offsetTracker
+	sched   *fairScheduler
+	slots   chan struct{}
+}
+
+func newLanes(cfg *conf.Config, defaultConcurrency int, logger *zap.Logger) []*lane {
+	var lanes []*lane
+	for _, jobLane := range service.JobLanes(cfg.Kafka) {
+		if jobLane.Topic == "" {
+			continue
+		}
+		concurrency := jobLane.Concurrency
+		if concurrency <= 0 {
+			concurrency = defaultConcurrency
+		}
+		buffer := cfg.Fairness.BufferSize
+		if buffer < concurrency {
+			buffer = concurrency
+		}
+		reader := newKafkaReader(cfg, jobLane.Topic)
+		lanes = append(lanes, &lane{
+			name:    jobLane.Name,
+			reader:  reader,
+			offsets: newOffsetTracker(reader, logger.With(zap.String("lane", jobLane.Name))),
+			sched:   newFairScheduler(cfg.Fairness, buffer),
+			slots:   make(chan struct{}, concurrency),
+		})
+	}
+	if len(lanes) == 0 {
+		panic("kafka topic config missing")
+	}
+	return lanes
+}
+
+func (l *lane) next(ctx context.Context) (scheduledJob, bool) {
+	for {
+		if job, ok := l.sched.pop(); ok {
+			return job, true
+		}
+		select {
+		case <-l.sched.ready:
+		case <-ctx.Done():
+			return scheduledJob{}, false
+		}
+	}
+}
+
+func laneCapacity(lanes []*lane) int {
+	total := 0
+	for _, l := range lanes {
+		total += cap(l.slots)
+	}
+	return total
+}
@@ -5,36 +5,68 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/panjf2000/ants/v2"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gorm.io/gorm"
 
 	"story2video-backend/internal/conf"
 	"story2video-backend/internal/data"
 	"story2video-backend/internal/global"
+	"story2video-backend/internal/health"
+	"story2video-backend/internal/metrics"
 	"story2video-backend/internal/model"
 	"story2video-backend/internal/rpc/modelclient"
 	"story2video-backend/internal/rpc/modelpb"
 	"story2video-backend/internal/service"
+	"story2video-backend/internal/tracing"
 	pkgLogger "story2video-backend/pkg/logger"
 )
 
+var modelHTTPStatusPattern = regexp.MustCompile(`status=(\d{3})`)
+
+const (
+	jobActionCreate     = "create_story"
+	jobActionUnknown    = "unknown"
+	consumerLagInterval = 15 * time.Second
+	defaultDrainTimeout = time.Minute
+	interruptGrace      = 10 * time.Second
+	commitTimeout       = 10 * time.Second
+	submitRetryDelay    = time.Second
+)
+
+var errJobInterrupted = errors.New("job interrupted by shutdown")
+
 type worker struct {
 	data       *data.Data
 	client     modelpb.StoryboardServiceClient
 	logger     *zap.Logger
-	reader     *kafka.Reader
+	lanes      []*lane
 	pool       *ants.Pool
 	rpcTimeout time.Duration
 	workerName string
+	exporter   *service.StoryExporter
+	moderator  service.Moderator
+	batches    *service.BatchTracker
+	pipeline   *service.Pipeline
+	inflight   sync.WaitGroup
 }
 
 func main() {
@@ -52,6 +84,16 @@ func main() {
 	}
 	defer func() { _ = log.Sync() }()
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, "story2video-worker")
+	if err != nil {
+		panic(fmt.Errorf("init tracing: %w", err))
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warn("shutdown tracing", zap.Error(err))
+		}
+	}()
+
 	dataLayer, cleanup, err := data.NewDataWithOptions(ctx, cfg, log, data.DataOptions{
 		SkipMigration: true,
 	})
@@ -69,22 +111,26 @@ func main() {
 		panic("init grpc client: empty GRPC addr or connection unavailable")
 	}
 
-	reader := newKafkaReader(cfg)
-	defer reader.Close()
-
 	poolSize := cfg.Pool.Size
 	if poolSize <= 0 {
 		poolSize = runtime.NumCPU()
 	}
+	lanes := newLanes(cfg, poolSize, log)
+	defer func() {
+		for _, l := range lanes {
+			_ = l.reader.Close()
+		}
+	}()
 	expiry := time.Duration(cfg.Pool.ExpirySeconds) * time.Second
 	if expiry <= 0 {
 		expiry = 5 * time.Minute
 	}
-	jobPool, err := ants.NewPool(poolSize, ants.WithExpiryDuration(expiry))
+	jobPool, err := ants.NewPool(laneCapacity(lanes), ants.WithExpiryDuration(expiry))
 	if err != nil {
 		panic(fmt.Errorf("init worker pool: %w", err))
 	}
 	defer jobPool.Release()
+	metrics.RegisterPool(jobPool)
 
 	rpcTimeout := time.Duration(cfg.ModelService.Timeout) * time.Second
 	if rpcTimeout <= 0 {
@@ -95,79 +141,292 @@ func main() {
 		data:       dataLayer,
 		client:     modelpb.NewStoryboardServiceClient(modelConn.Conn()),
 		logger:     log,
-		reader:     reader,
+		lanes:      lanes,
 		pool:       jobPool,
 		rpcTimeout: rpcTimeout,
 		workerName: "story-worker",
+		exporter:   service.NewStoryExporter(cfg, dataLayer, log),
+		moderator:  service.NewModerator(cfg, log),
+		batches:    service.NewBatchTracker(cfg, dataLayer, log),
+		pipeline:   service.NewPipeline(cfg, dataLayer, log),
+	}
+	defer func() { _ = w.batches.Close() }()
+	defer func() { _ = w.pipeline.Close() }()
+
+	if cfg.Metrics.Enabled && cfg.Metrics.WorkerAddr != "" {
+		stopMetrics := metrics.Serve(cfg.Metrics.WorkerAddr, log)
+		defer stopMetrics()
+		go w.reportConsumerLag(ctx)
+	}
+
+	if cfg.Health.WorkerAddr != "" {
+		checker := health.NewChecker(time.Duration(cfg.Health.TimeoutSeconds)*time.Second, health.Dependencies(cfg, dataLayer)...)
+		stopHealth := health.Serve(cfg.Health.WorkerAddr, checker, log)
+		defer stopHealth()
+	}
+
+	drainTimeout := time.Duration(cfg.Pool.DrainTimeoutSeconds) * time.Second
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
 	}
 
-	go w.run(ctx)
+	commitCtx, stopCommitting := context.WithCancel(context.Background())
+	fetchCtx, stopFetching := context.WithCancel(ctx)
+	var committers, runners sync.WaitGroup
+	for _, l := range lanes {
+		committers.Add(1)
+		go func(l *lane) {
+			defer committers.Done()
+			l.offsets.run(commitCtx)
+		}(l)
+		runners.Add(1)
+		go func(l *lane) {
+			defer runners.Done()
+			w.run(fetchCtx, ctx, l)
+		}(l)
+		log.Info("worker lane started", zap.String("lane", l.name), zap.String("topic", l.reader.Config().Topic), zap.Int("concurrency", cap(l.slots)))
+	}
+	runDone := make(chan struct{})
+	go func() {
+		runners.Wait()
+		close(runDone)
+	}()
 
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt)
-	<-sigCh
-	log.Info("worker shutting down")
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	sig := <-sigCh
+	log.Info("worker shutting down", zap.String("signal", sig.String()), zap.Duration("drain_timeout", drainTimeout))
+
+	stopFetching()
+	idle := w.idle(runDone)
+	select {
+	case <-idle:
+		log.Info("in-flight jobs drained")
+	case <-time.After(drainTimeout):
+		log.Warn("drain timeout exceeded, interrupting in-flight jobs")
+		cancel()
+		select {
+		case <-idle:
+		case <-time.After(interruptGrace):
+			log.Error("in-flight jobs did not stop after interruption")
+		}
+	}
+	stopCommitting()
+	committers.Wait()
+	log.Info("worker stopped")
 }
 
-func (w *worker) run(ctx context.Context) {
+func (w *worker) run(fetchCtx, jobCtx context.Context, l *lane) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.schedule(fetchCtx, jobCtx, l)
+	}()
+	defer wg.Wait()
+
 	for {
-		m, err := w.reader.FetchMessage(ctx)
+		if !l.sched.wait(fetchCtx) {
+			return
+		}
+
+		m, err := l.reader.FetchMessage(fetchCtx)
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
+			if fetchCtx.Err() != nil {
 				return
 			}
-			w.logger.Error("fetch kafka message", zap.Error(err))
+			w.logger.Error("fetch kafka message", zap.String("lane", l.name), zap.Error(err))
 			time.Sleep(time.Second)
 			continue
 		}
 
-		if err := w.submitMessage(ctx, m); err != nil {
-			w.logger.Error("submit job to pool", zap.Error(err))
-			w.processMessage(ctx, m)
+		l.offsets.track(m)
+		l.sched.push(newScheduledJob(m))
+	}
+}
+
+func (w *worker) schedule(fetchCtx, jobCtx context.Context, l *lane) {
+	for {
+		select {
+		case l.slots <- struct{}{}:
+		case <-fetchCtx.Done():
+			return
+		}
+
+		job, ok := l.next(fetchCtx)
+		if !ok {
+			<-l.slots
+			return
+		}
+		if err := w.submitJob(jobCtx, l, job); err != nil {
+			w.logger.Error("submit job to pool, retry later", zap.Error(err),
+				zap.String("lane", l.name),
+				zap.String("topic", job.msg.Topic),
+				zap.Int("partition", job.msg.Partition),
+				zap.Int64("offset", job.msg.Offset),
+			)
+			l.sched.requeue(job)
+			select {
+			case <-time.After(submitRetryDelay):
+			case <-fetchCtx.Done():
+				return
+			}
 		}
 	}
 }
 
-func (w *worker) submitMessage(ctx context.Context, msg kafka.Message) error {
-	if w.pool == nil {
-		w.processMessage(ctx, msg)
-		return nil
+func (w *worker) submitJob(ctx context.Context, l *lane, job scheduledJob) error {
+	w.inflight.Add(1)
+	if err := w.pool.Submit(func() {
+		w.processJob(ctx, l, job)
+	}); err != nil {
+		<-l.slots
+		w.inflight.Done()
+		return err
 	}
-	return w.pool.Submit(func() {
-		w.processMessage(ctx, msg)
-	})
+	return nil
 }
 
-func (w *worker) processMessage(ctx context.Context, msg kafka.Message) {
-	if err := w.handleMessage(ctx, msg.Value); err != nil {
-		w.logger.Error("process job", zap.Error(err))
+func (w *worker) idle(runDone <-chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		<-runDone
+		w.inflight.Wait()
+		close(done)
+	}()
+	return done
+}
+
+func (w *worker) reportConsumerLag(ctx context.Context) {
+	ticker := time.NewTicker(consumerLagInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, l := range w.lanes {
+				stats := l.reader.Stats()
+				metrics.WorkerConsumerLag.WithLabelValues(stats.Topic).Set(float64(stats.Lag))
+			}
+		}
 	}
-	if err := w.reader.CommitMessages(ctx, msg); err != nil {
-		w.logger.Error("commit message", zap.Error(err))
+}
+
+func (w *worker) processJob(ctx context.Context, l *lane, job scheduledJob) {
+	defer w.inflight.Done()
+	defer func() {
+		l.sched.done(job.userID)
+		<-l.slots
+	}()
+	msg := job.msg
+	err := w.handleMessage(ctx, msg)
+	if errors.Is(err, errJobInterrupted) {
+		w.logger.Warn("job interrupted, offset left uncommitted",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+		)
+		return
+	}
+	if err != nil {
+		w.logger.Error("process job", zap.Error(err))
 	}
+	l.offsets.complete(msg)
 }
 
-func (w *worker) handleMessage(ctx context.Context, value []byte) error {
+func (w *worker) handleMessage(ctx context.Context, msg kafka.Message) (err error) {
+	start := time.Now()
+	action, outcome := jobActionUnknown, metrics.OutcomeInvalid
+	ctx = tracing.Extract(ctx, tracing.KafkaHeaderCarrier{Headers: &msg.Headers})
+	ctx, span := tracing.Tracer().Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.kafka.destination.partition", msg.Partition),
+			attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+		),
+	)
+	defer func() {
+		metrics.WorkerJobDuration.WithLabelValues(action, outcome).Observe(time.Since(start).Seconds())
+		span.SetAttributes(attribute.String("job.action", action), attribute.String("job.outcome", outcome))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	var job service.StoryJobMessage
-	if err := json.Unmarshal(value, &job); err != nil {
+	if err := json.Unmarshal(msg.Value, &job); err != nil {
 		return service.WrapServiceError(service.ErrCodeInvalidRequest, "解析任务消息失败", err)
 	}
+	action = jobAction(job)
+	span.SetName("process " + action)
+	span.SetAttributes(
+		attribute.String(string(service.LogKeyOperationID), job.OperationID),
+		attribute.String(string(service.LogKeyStoryID), job.StoryID),
+	)
+	if !msg.Time.IsZero() {
+		metrics.WorkerQueueLag.WithLabelValues(action).Observe(start.Sub(msg.Time).Seconds())
+	}
 
 	opID, err := uuid.Parse(job.OperationID)
 	if err != nil {
 		return service.NewServiceError(service.ErrCodeInvalidRequest, "operation_id 非法")
 	}
 
+	if ctx.Err() != nil {
+		outcome = metrics.OutcomeRequeued
+		return errJobInterrupted
+	}
+	outcome = metrics.OutcomeFailed
 	if err := service.UpdateOperationRunning(ctx, w.data, opID); err != nil {
+		if errors.Is(err, service.ErrOperationNotRunnable) {
+			outcome = metrics.OutcomeSkipped
+			w.logger.Info("skip operation that is cancelled or already finished", zap.String("operation_id", job.OperationID), zap.String("story_id", job.StoryID))
+			return nil
+		}
 		return service.WrapServiceError(service.ErrCodeOperationUpdateFailed, "标记任务为运行中失败", err)
 	}
 
 	err = w.dispatchJob(ctx, job)
+	if errors.Is(err, service.ErrOperationPending) {
+		outcome = metrics.OutcomeDeferred
+		return nil
+	}
+	if err != nil && ctx.Err() != nil {
+		outcome = metrics.OutcomeRequeued
+		requeueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), commitTimeout)
+		defer cancel()
+		if reqErr := service.RequeueOperation(requeueCtx, w.data, opID); reqErr != nil {
+			w.logError(service.LogMsgOperationUpdateFail, reqErr, &job)
+		}
+		return errJobInterrupted
+	}
 	if err != nil {
 		if _, ok := service.AsServiceError(err); !ok {
 			err = service.WrapServiceError(service.ErrCodeWorkerExecutionFailed, "处理任务失败", err)
 		}
+		if job.Payload.ShotID != "" {
+			if svcErr, ok := service.AsServiceError(err); ok {
+				svcErr.WithDetail(service.ErrorDetailShotID, job.Payload.ShotID)
+			}
+		}
+		if job.ParentID != "" && w.pipeline.RetryStage(ctx, job, err) {
+			outcome = metrics.OutcomeRequeued
+			w.logWarn(service.LogMsgWorkerExecutionFail, err, &job)
+			return nil
+		}
 		_ = service.UpdateOperationFailure(ctx, w.data, opID, err)
+		w.finalizeBatch(ctx, job)
+		w.advancePipeline(ctx, job)
+		if svcErr, ok := service.AsServiceError(err); ok && svcErr.Code == service.ErrCodeContentFlagged {
+			outcome = metrics.OutcomeFlagged
+			w.logWarn(service.LogMsgContentFlagged, err, &job)
+			return err
+		}
 		w.handleJobFailure(ctx, job)
 		w.logError(service.LogMsgWorkerExecutionFail, err, &job)
 		return err
@@ -178,21 +437,69 @@ func (w *worker) handleMessage(ctx context.Context, value []byte) error {
 		w.logError(service.LogMsgOperationUpdateFail, err, &job)
 		return err
 	}
+	outcome = metrics.OutcomeSucceeded
+	w.finalizeBatch(ctx, job)
+	w.advancePipeline(ctx, job)
 	return nil
 }
 
+func (w *worker) finalizeBatch(ctx context.Context, job service.StoryJobMessage) {
+	if job.BatchID == "" {
+		return
+	}
+	batchID, err := uuid.Parse(job.BatchID)
+	if err != nil {
+		return
+	}
+	w.batches.Finalize(ctx, batchID)
+}
+
+func (w *worker) advancePipeline(ctx context.Context, job service.StoryJobMessage) {
+	if job.ParentID == "" {
+		return
+	}
+	parentID, err := uuid.Parse(job.ParentID)
+	if err != nil {
+		return
+	}
+	if err := w.pipeline.Advance(ctx, parentID); err != nil {
+		w.logError(service.LogMsgOperationUpdateFail, err, &job, zap.String("parent_id", job.ParentID))
+	}
+}
+
+func jobAction(job service.StoryJobMessage) string {
+	if job.Payload.Action == "" {
+		return jobActionCreate
+	}
+	return job.Payload.Action
+}
+
 func (w *worker) dispatchJob(ctx context.Context, job service.StoryJobMessage) error {
 	switch job.Payload.Action {
 	case "regen_shot":
 		return w.handleRegenerate(ctx, job)
-	case "render_video":
+	case "render_video", service.StageActionCompose:
 		return w.handleRender(ctx, job)
+	case "export_story":
+		return w.handleExport(ctx, job)
+	case service.StageActionScript:
+		return w.handleScriptStage(ctx, job)
+	case service.StageActionImage:
+		return w.handleImageStage(ctx, job)
+	case service.StageActionAudio:
+		return w.handleAudioStage(ctx, job)
 	default:
 		return w.handleCreate(ctx, job)
 	}
 }
 
 func (w *worker) handleCreate(ctx context.Context, job service.StoryJobMessage) error {
+	if w.pipeline.Enabled() {
+		if err := w.pipeline.Start(ctx, job); err != nil {
+			return err
+		}
+		return service.ErrOperationPending
+	}
 	req := &modelpb.CreateStoryboardTaskRequest{
 		OperationId:   job.OperationID,
 		StoryId:       job.StoryID,
@@ -207,7 +514,7 @@ func (w *worker) handleCreate(ctx context.Context, job service.StoryJobMessage)
 		resp, rpcErr = w.client.CreateStoryboardTask(rpcCtx, req)
 		return rpcErr
 	}); err != nil {
-		return service.WrapServiceError(service.ErrCodeWorkerExecutionFailed, "调用模型服务创建故事失败", err)
+		return modelCallError("调用模型服务创建故事失败", err)
 	}
 	if resp == nil || len(resp.Shots) == 0 {
 		return service.NewServiceError(service.ErrCodeShotMissingPartial, "模型服务未返回任何镜头")
@@ -215,6 +522,166 @@ func (w *worker) handleCreate(ctx context.Context, job service.StoryJobMessage)
 	return w.persistShots(ctx, job, resp.Shots)
 }
 
+func (w *worker) handleScriptStage(ctx context.Context, job service.StoryJobMessage) error {
+	opID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return service.NewServiceError(service.ErrCodeInvalidRequest, "operation_id 非法")
+	}
+	storyID, err := uuid.Parse(job.StoryID)
+	if err != nil {
+		return service.NewServiceError(service.ErrCodeInvalidRequest, "story_id 非法")
+	}
+	req := &modelpb.CreateStoryboardTaskRequest{
+		OperationId:   job.OperationID,
+		StoryId:       job.StoryID,
+		UserId:        job.UserID,
+		DisplayName:   job.Payload.DisplayName,
+		ScriptContent: job.Payload.ScriptContent,
+		Style:         job.Payload.Style,
+	}
+	var resp *modelpb.StoryboardReply
+	if err := w.callRPCWithTimeout(ctx, func(rpcCtx context.Context) error {
+		var rpcErr error
+		resp, rpcErr = w.client.GenerateShots(rpcCtx, req)
+		return rpcErr
+	}); err != nil {
+		return modelCallError("调用模型服务生成分镜失败", err)
+	}
+	if resp == nil || len(resp.Shots) == 0 {
+		return service.NewServiceError(service.ErrCodeShotMissingPartial, "模型服务未返回任何镜头")
+	}
+	flagged, err := w.storeShots(ctx, job, resp.Shots)
+	if err != nil {
+		return err
+	}
+	if err := w.data.DB.WithContext(ctx).
+		Model(&model.Shot{}).
+		Where("story_id = ? AND status = ? AND image_url = ''", storyID, global.ShotDone).
+		Update("status", global.ShotRender).Error; err != nil {
+		return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新镜头状态失败", err)
+	}
+	if flagged > 0 {
+		w.logWarn(service.LogMsgContentFlagged, service.NewLocalizedError(service.ErrCodeContentFlagged, service.MsgShotsHeldForReview, service.MessageParams{"count": flagged}), &job)
+	}
+	return service.UpdateOperationResult(ctx, w.data, opID, service.ScriptStageResult{ShotCount: len(resp.Shots), HeldForReview: flagged})
+}
+
+func (w *worker) handleImageStage(ctx context.Context, job service.StoryJobMessage) error {
+	opID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return service.NewServiceError(service.ErrCodeInvalidRequest, "operation_id 非法")
+	}
+	shot, err := w.loadJobShot(ctx, job)
+	if err != nil {
+		return err
+	}
+	prompt := shot.Details
+	if strings.TrimSpace(prompt) == "" {
+		prompt = shot.Description
+	}
+	req := &modelpb.GenerateShotImageRequest{
+		OperationId: job.OperationID,
+		StoryId:     job.StoryID,
+		UserId:      job.UserID,
+		ShotId:      shot.ID.String(),
+		Sequence:    shot.Sequence,
+		Title:       shot.Title,
+		Prompt:      prompt,
+		Style:       job.Payload.Style,
+	}
+	var resp *modelpb.GenerateShotImageReply
+	if err := w.callRPCWithTimeout(ctx, func(rpcCtx context.Context) error {
+		var rpcErr error
+		resp, rpcErr = w.client.GenerateShotImage(rpcCtx, req)
+		return rpcErr
+	}); err != nil {
+		return modelCallError("调用模型服务生成镜头画面失败", err)
+	}
+	if resp == nil || resp.ImageUrl == "" {
+		return service.NewServiceError(service.ErrCodeShotAssetMissing, "模型服务未返回镜头画面")
+	}
+	result := service.ImageStageResult{ImageURL: resp.ImageUrl}
+	if err := w.upsertShot(ctx, job, &modelpb.ShotResult{
+		ShotId:   shot.ID.String(),
+		Sequence: shot.Sequence,
+		ImageUrl: resp.ImageUrl,
+	}); err != nil {
+		svcErr, ok := service.AsServiceError(err)
+		if !ok || svcErr.Code != service.ErrCodeContentFlagged {
+			return err
+		}
+		w.logWarn(service.LogMsgContentFlagged, err, &job)
+		result.HeldForReview = true
+	}
+	return service.UpdateOperationResult(ctx, w.data, opID, result)
+}
+
+func (w *worker) handleAudioStage(ctx context.Context, job service.StoryJobMessage) error {
+	opID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return service.NewServiceError(service.ErrCodeInvalidRequest, "operation_id 非法")
+	}
+	shot, err := w.loadJobShot(ctx, job)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(shot.Narration) == "" {
+		return service.UpdateOperationResult(ctx, w.data, opID, service.AudioStageResult{})
+	}
+	req := &modelpb.SynthesizeShotAudioRequest{
+		OperationId: job.OperationID,
+		StoryId:     job.StoryID,
+		UserId:      job.UserID,
+		ShotId:      shot.ID.String(),
+		Sequence:    shot.Sequence,
+		Text:        shot.Narration,
+		Voice:       shot.Voice,
+	}
+	var resp *modelpb.SynthesizeShotAudioReply
+	if err := w.callRPCWithTimeout(ctx, func(rpcCtx context.Context) error {
+		var rpcErr error
+		resp, rpcErr = w.client.SynthesizeShotAudio(rpcCtx, req)
+		return rpcErr
+	}); err != nil {
+		return modelCallError("调用模型服务合成旁白失败", err)
+	}
+	if resp == nil || resp.AudioUrl == "" {
+		return service.NewServiceError(service.ErrCodeShotAssetMissing, "模型服务未返回旁白音频")
+	}
+	updates := map[string]interface{}{"audio_url": resp.AudioUrl}
+	if resp.AudioDurationMs > 0 {
+		updates["audio_duration_ms"] = resp.AudioDurationMs
+	}
+	if err := w.data.DB.WithContext(ctx).
+		Model(shot).
+		Updates(updates).Error; err != nil {
+		return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新镜头记录失败", err)
+	}
+	return service.UpdateOperationResult(ctx, w.data, opID, service.AudioStageResult{
+		AudioURL:        resp.AudioUrl,
+		AudioDurationMs: resp.AudioDurationMs,
+	})
+}
+
+func (w *worker) loadJobShot(ctx context.Context, job service.StoryJobMessage) (*model.Shot, error) {
+	shotID, err := uuid.Parse(job.Payload.ShotID)
+	if err != nil {
+		return nil, service.NewServiceError(service.ErrCodeInvalidRequest, "shot_id 非法")
+	}
+	storyID, err := uuid.Parse(job.StoryID)
+	if err != nil {
+		return nil, service.NewServiceError(service.ErrCodeInvalidRequest, "story_id 非法")
+	}
+	var shot model.Shot
+	if err := w.data.DB.WithContext(ctx).First(&shot, "id = ? AND story_id = ?", shotID, storyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service.NewServiceError(service.ErrCodeShotNotFound, "镜头不存在")
+		}
+		return nil, service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "查询镜头记录失败", err)
+	}
+	return &shot, nil
+}
+
 func (w *worker) handleRegenerate(ctx context.Context, job service.StoryJobMessage) error {
 	req := &modelpb.RegenerateShotRequest{
 		OperationId: job.OperationID,
@@ -230,7 +697,7 @@ func (w *worker) handleRegenerate(ctx context.Context, job service.StoryJobMessa
 		resp, rpcErr = w.client.RegenerateShot(rpcCtx, req)
 		return rpcErr
 	}); err != nil {
-		return service.WrapServiceError(service.ErrCodeWorkerExecutionFailed, "调用模型服务重生成镜头失败", err)
+		return modelCallError("调用模型服务重生成镜头失败", err)
 	}
 	if resp == nil || resp.Shot == nil {
 		return service.NewServiceError(service.ErrCodeShotContentMissing, "模型服务未返回镜头内容")
@@ -244,13 +711,31 @@ func (w *worker) handleRender(ctx context.Context, job service.StoryJobMessage)
 		StoryId:     job.StoryID,
 		UserId:      job.UserID,
 	}
+	story, shots, err := w.loadStoryWithShots(ctx, job.StoryID)
+	if err != nil {
+		return err
+	}
+	for _, shot := range shots {
+		req.Shots = append(req.Shots, &modelpb.RenderShotAudio{
+			ShotId:    shot.ID.String(),
+			Sequence:  shot.Sequence,
+			Narration: shot.Narration,
+			AudioUrl:  shot.AudioURL,
+		})
+	}
+	subtitles, err := service.BuildSubtitles(story, shots, service.SubtitleFormatSRT)
+	if err != nil {
+		w.logWarn(service.LogMsgResultDataMissing, err, &job)
+	} else {
+		req.SubtitlesSrt = string(subtitles.Content)
+	}
 	var resp *modelpb.RenderVideoReply
 	if err := w.callRPCWithTimeout(ctx, func(rpcCtx context.Context) error {
 		var rpcErr error
 		resp, rpcErr = w.client.RenderVideo(rpcCtx, req)
 		return rpcErr
 	}); err != nil {
-		return service.WrapServiceError(service.ErrCodeWorkerExecutionFailed, "调用模型服务渲染视频失败", err)
+		return modelCallError("调用模型服务渲染视频失败", err)
 	}
 	storyID, err := uuid.Parse(job.StoryID)
 	if err != nil {
@@ -262,13 +747,52 @@ func (w *worker) handleRender(ctx context.Context, job service.StoryJobMessage)
 	}
 	if err := w.data.DB.WithContext(ctx).
 		Model(&model.Story{}).
-		Where("id = ?", storyID).
+		Where("id = ? AND status <> ?", storyID, global.StoryFlag).
 		Updates(update).Error; err != nil {
 		return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新故事渲染结果失败", err)
 	}
 	return nil
 }
 
+func (w *worker) handleExport(ctx context.Context, job service.StoryJobMessage) error {
+	opID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return service.NewServiceError(service.ErrCodeInvalidRequest, "operation_id 非法")
+	}
+	story, shots, err := w.loadStoryWithShots(ctx, job.StoryID)
+	if err != nil {
+		return err
+	}
+	file, err := w.exporter.Build(ctx, story, shots, job.Payload.ExportFormat)
+	if err != nil {
+		return err
+	}
+	artifact, err := w.exporter.Save(opID, file)
+	if err != nil {
+		return err
+	}
+	return service.UpdateOperationResult(ctx, w.data, opID, artifact)
+}
+
+func (w *worker) loadStoryWithShots(ctx context.Context, storyID string) (*model.Story, []model.Shot, error) {
+	id, err := uuid.Parse(storyID)
+	if err != nil {
+		return nil, nil, service.NewServiceError(service.ErrCodeInvalidRequest, "story_id 非法")
+	}
+	var story model.Story
+	if err := w.data.DB.WithContext(ctx).First(&story, "id = ?", id).Error; err != nil {
+		return nil, nil, service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "查询故事失败", err)
+	}
+	var shots []model.Shot
+	if err := w.data.DB.WithContext(ctx).
+		Where("story_id = ?", id).
+		Order(service.ShotSequenceOrderClause).
+		Find(&shots).Error; err != nil {
+		return nil, nil, service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+	}
+	return &story, shots, nil
+}
+
 func (w *worker) persistShots(ctx context.Context, job service.StoryJobMessage, shots []*modelpb.ShotResult) error {
 	storyUUID, err := uuid.Parse(job.StoryID)
 	if err != nil {
@@ -277,23 +801,39 @@ func (w *worker) persistShots(ctx context.Context, job service.StoryJobMessage,
 	if len(shots) == 0 {
 		return service.NewServiceError(service.ErrCodeShotMissingPartial, "模型服务未返回任何镜头")
 	}
-	for _, s := range shots {
-		if err := w.upsertShot(ctx, job, s); err != nil {
-			return err
-		}
+	flagged, err := w.storeShots(ctx, job, shots)
+	if err != nil {
+		return err
 	}
 	if err := w.data.DB.WithContext(ctx).
 		Model(&model.Story{}).
-		Where("id = ?", storyUUID).
+		Where("id = ? AND status <> ?", storyUUID, global.StoryFlag).
 		Update("status", global.StoryReady).Error; err != nil {
 		return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
 	}
 	if err := w.ensureStoryCover(ctx, storyUUID); err != nil {
 		w.logWarn(service.LogMsgResultDataMissing, err, &job, zap.String(string(service.LogKeyStoryID), storyUUID.String()))
 	}
+	if flagged > 0 {
+		return service.NewLocalizedError(service.ErrCodeContentFlagged, service.MsgShotsHeldForReview, service.MessageParams{"count": flagged})
+	}
 	return nil
 }
 
+func (w *worker) storeShots(ctx context.Context, job service.StoryJobMessage, shots []*modelpb.ShotResult) (int, error) {
+	flagged := 0
+	for _, s := range shots {
+		if err := w.upsertShot(ctx, job, s); err != nil {
+			if svcErr, ok := service.AsServiceError(err); ok && svcErr.Code == service.ErrCodeContentFlagged {
+				flagged++
+				continue
+			}
+			return flagged, err
+		}
+	}
+	return flagged, nil
+}
+
 func (w *worker) callRPCWithTimeout(ctx context.Context, fn func(context.Context) error) error {
 	if w.rpcTimeout <= 0 {
 		return fn(ctx)
@@ -307,6 +847,26 @@ func (w *worker) callRPCWithTimeout(ctx context.Context, fn func(context.Context
 	return fn(rpcCtx)
 }
 
+func modelCallError(msg string, err error) *service.ServiceError {
+	svcErr := service.WrapServiceError(service.ErrCodeWorkerExecutionFailed, msg, err)
+	st, ok := status.FromError(err)
+	if !ok {
+		return svcErr
+	}
+	svcErr.WithDetail(service.ErrorDetailRPCCode, st.Code().String())
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.PermissionDenied, codes.Unimplemented:
+		svcErr.WithRetryable(false)
+	}
+	if m := modelHTTPStatusPattern.FindStringSubmatch(st.Message()); m != nil {
+		svcErr.WithDetail(service.ErrorDetailModelHTTPStatus, m[1])
+		if code, _ := strconv.Atoi(m[1]); code >= 400 && code < 500 && code != http.StatusTooManyRequests && code != http.StatusRequestTimeout {
+			svcErr.WithRetryable(false)
+		}
+	}
+	return svcErr
+}
+
 func (w *worker) upsertShot(ctx context.Context, job service.StoryJobMessage, shot *modelpb.ShotResult) error {
 	if shot == nil {
 		return service.NewServiceError(service.ErrCodeResultDataMissing, "模型返回空的镜头结果")
@@ -329,6 +889,26 @@ func (w *worker) upsertShot(ctx context.Context, job service.StoryJobMessage, sh
 		shotIDStr = job.Payload.ShotID
 	}
 
+	content := service.ModerationShotContent{
+		Title:           shot.Title,
+		Description:     shot.Description,
+		Details:         details,
+		Narration:       shot.Narration,
+		Type:            shot.Type,
+		Transition:      shot.Transition,
+		Voice:           shot.Voice,
+		ImageURL:        shot.ImageUrl,
+		BGM:             shot.Bgm,
+		AudioDurationMs: shot.AudioDurationMs,
+	}
+	verdict, err := w.moderator.Moderate(ctx, content.Input())
+	if err != nil {
+		return err
+	}
+	if verdict.Flagged {
+		return w.flagShot(ctx, job, storyID, shotIDStr, sequence, content, verdict)
+	}
+
 	var (
 		existing  model.Shot
 		shotUUID  uuid.UUID
@@ -368,18 +948,19 @@ func (w *worker) upsertShot(ctx context.Context, job service.StoryJobMessage, sh
 				ID:     shotID,
 				UserID: userID,
 			},
-			StoryID:     storyID,
-			Sequence:    sequence,
-			Title:       shot.Title,
-			Description: shot.Description,
-			Details:     details,
-			Narration:   shot.Narration,
-			Type:        shot.Type,
-			Transition:  shot.Transition,
-			Voice:       shot.Voice,
-			Status:      global.ShotDone,
-			ImageURL:    shot.ImageUrl,
-			BGM:         shot.Bgm,
+			StoryID:         storyID,
+			Sequence:        sequence,
+			Title:           shot.Title,
+			Description:     shot.Description,
+			Details:         details,
+			Narration:       shot.Narration,
+			Type:            shot.Type,
+			Transition:      shot.Transition,
+			Voice:           shot.Voice,
+			Status:          global.ShotDone,
+			ImageURL:        shot.ImageUrl,
+			BGM:             shot.Bgm,
+			AudioDurationMs: shot.AudioDurationMs,
 		}
 		if err := w.data.DB.WithContext(ctx).Create(&newShot).Error; err != nil {
 			return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "创建镜头记录失败", err)
@@ -406,6 +987,68 @@ func (w *worker) upsertShot(ctx context.Context, job service.StoryJobMessage, sh
 	return nil
 }
 
+func (w *worker) flagShot(ctx context.Context, job service.StoryJobMessage, storyID uuid.UUID, shotIDStr, sequence string, content service.ModerationShotContent, verdict *service.ModerationVerdict) error {
+	userID, _ := uuid.Parse(job.UserID)
+	err := w.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			existing model.Shot
+			found    bool
+		)
+		if parsed, parseErr := uuid.Parse(shotIDStr); parseErr == nil {
+			err := tx.First(&existing, "id = ?", parsed).Error
+			if err == nil {
+				found = true
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "查询镜头记录失败", err)
+			}
+		}
+		if !found {
+			err := tx.Where("story_id = ? AND sequence = ?", storyID, sequence).First(&existing).Error
+			if err == nil {
+				found = true
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "查询镜头记录失败", err)
+			}
+		}
+		if found {
+			if err := tx.Model(&existing).Update("status", global.ShotFlag).Error; err != nil {
+				return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新镜头状态失败", err)
+			}
+		} else {
+			shotID, parseErr := uuid.Parse(shotIDStr)
+			if parseErr != nil {
+				shotID = uuid.New()
+			}
+			existing = model.Shot{
+				BaseModel: model.BaseModel{ID: shotID, UserID: userID},
+				StoryID:   storyID,
+				Sequence:  sequence,
+				Status:    global.ShotFlag,
+			}
+			if err := tx.Create(&existing).Error; err != nil {
+				return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "创建镜头记录失败", err)
+			}
+		}
+
+		record, err := service.BuildModerationRecord(userID, storyID, &existing.ID, global.ModerationTargetShot, w.moderator.Name(), verdict, content)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "创建审核记录失败", err)
+		}
+		if err := tx.Model(&model.Story{}).Where("id = ?", storyID).Update("status", global.StoryFlag).Error; err != nil {
+			return service.WrapServiceError(service.ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return service.NewLocalizedError(service.ErrCodeContentFlagged, service.MsgShotHeldForReview, service.MessageParams{"sequence": sequence}).
+		WithDetail(service.ErrorDetailShotID, shotIDStr)
+}
+
 func (w *worker) updateShot(ctx context.Context, existing *model.Shot, shot *modelpb.ShotResult, details string) error {
 	updates := map[string]interface{}{
 		"status": global.ShotDone,
@@ -429,6 +1072,10 @@ func (w *worker) updateShot(ctx context.Context, existing *model.Shot, shot *mod
 	if strings.TrimSpace(details) != "" {
 		updates["details"] = details
 	}
+	if shot.AudioDurationMs > 0 {
+		updates["audio_duration_ms"] = shot.AudioDurationMs
+	}
+	service.ClearStaleAudio(existing, updates)
 
 	if err := w.data.DB.WithContext(ctx).
 		Model(existing).
@@ -440,10 +1087,14 @@ func (w *worker) updateShot(ctx context.Context, existing *model.Shot, shot *mod
 
 func (w *worker) handleJobFailure(ctx context.Context, job service.StoryJobMessage) {
 	switch job.Payload.Action {
-	case "regen_shot":
+	case "regen_shot", service.StageActionImage:
 		if err := w.updateShotStatus(ctx, job.Payload.ShotID, job.StoryID, global.ShotFail); err != nil {
 			w.logger.Warn("mark shot failed", zap.Error(err), zap.String("shot_id", job.Payload.ShotID), zap.String("story_id", job.StoryID))
 		}
+	case "export_story":
+		w.logger.Warn(string(service.LogMsgExportFailed), zap.String("operation_id", job.OperationID), zap.String("story_id", job.StoryID))
+	case service.StageActionScript, service.StageActionAudio, service.StageActionCompose:
+		return
 	default:
 		if err := w.updateStoryStatus(ctx, job.StoryID, global.StoryFail); err != nil {
 			w.logger.Warn("mark story failed", zap.Error(err), zap.String("story_id", job.StoryID))
@@ -486,7 +1137,7 @@ func (w *worker) updateShotStatus(ctx context.Context, shotID string, storyID st
 	return nil
 }
 
-func newKafkaReader(cfg *conf.Config) *kafka.Reader {
+func newKafkaReader(cfg *conf.Config, topic string) *kafka.Reader {
 	if len(cfg.Kafka.Brokers) == 0 {
 		panic("kafka brokers config missing")
 	}
@@ -496,7 +1147,7 @@ func newKafkaReader(cfg *conf.Config) *kafka.Reader {
 	return kafka.NewReader(kafka.ReaderConfig{
 		Brokers:  cfg.Kafka.Brokers,
 		GroupID:  cfg.Kafka.Group,
-		Topic:    cfg.Kafka.Topic,
+		Topic:    topic,
 		MinBytes: 1,
 		MaxBytes: 10e6,
 	})
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+type offsetCommitter interface {
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+type partitionOffsets struct {
+	pending []int64
+	done    map[int64]kafka.Message
+}
+
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionOffsets
+	ready      map[partitionKey]kafka.Message
+	notify     chan struct{}
+	committer  offsetCommitter
+	logger     *zap.Logger
+}
+
+func newOffsetTracker(committer offsetCommitter, logger *zap.Logger) *offsetTracker {
+	return &offsetTracker{
+		partitions: make(map[partitionKey]*partitionOffsets),
+		ready:      make(map[partitionKey]kafka.Message),
+		notify:     make(chan struct{}, 1),
+		committer:  committer,
+		logger:     logger,
+	}
+}
+
+func (t *offsetTracker) track(msg kafka.Message) {
+	key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[key]
+	if !ok || (len(p.pending) > 0 && msg.Offset <= p.pending[len(p.pending)-1]) {
+		p = &partitionOffsets{done: make(map[int64]kafka.Message)}
+		t.partitions[key] = p
+	}
+	p.pending = append(p.pending, msg.Offset)
+}
+
+func (t *offsetTracker) complete(msg kafka.Message) {
+	key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[key]
+	if !ok {
+		return
+	}
+	// Completions of messages fetched before a reassignment are dropped so
+	// they cannot count for the redelivered copy of the same offset.
+	idx := sort.Search(len(p.pending), func(i int) bool { return p.pending[i] >= msg.Offset })
+	if idx == len(p.pending) || p.pending[idx] != msg.Offset {
+		return
+	}
+	p.done[msg.Offset] = msg
+	var (
+		last     kafka.Message
+		advanced bool
+	)
+	for len(p.pending) > 0 {
+		m, ok := p.done[p.pending[0]]
+		if !ok {
+			break
+		}
+		delete(p.done, p.pending[0])
+		p.pending = p.pending[1:]
+		last, advanced = m, true
+	}
+	if !advanced {
+		return
+	}
+	t.ready[key] = last
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (t *offsetTracker) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), commitTimeout)
+			t.flush(flushCtx)
+			cancel()
+			return
+		case <-t.notify:
+			t.flush(ctx)
+		}
+	}
+}
+
+func (t *offsetTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	if len(t.ready) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	msgs := make([]kafka.Message, 0, len(t.ready))
+	for key, msg := range t.ready {
+		msgs = append(msgs, msg)
+		delete(t.ready, key)
+	}
+	t.mu.Unlock()
+
+	if err := t.committer.CommitMessages(ctx, msgs...); err != nil {
+		t.logger.Error("commit message", zap.Error(err))
+		t.mu.Lock()
+		for _, msg := range msgs {
+			key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+			if _, ok := t.ready[key]; !ok {
+				t.ready[key] = msg
+			}
+		}
+		t.mu.Unlock()
+	}
+}
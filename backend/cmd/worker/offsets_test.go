@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+type recordingCommitter struct {
+	commits []string
+	fail    int
+}
+
+func (c *recordingCommitter) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	if c.fail > 0 {
+		c.fail--
+		return errors.New("broker unavailable")
+	}
+	for _, msg := range msgs {
+		c.commits = append(c.commits, fmt.Sprintf("p%d@%d", msg.Partition, msg.Offset))
+	}
+	return nil
+}
+
+type offsetStep struct {
+	track     bool
+	partition int
+	offset    int64
+}
+
+func fetched(partition int, offsets ...int64) []offsetStep {
+	steps := make([]offsetStep, 0, len(offsets))
+	for _, offset := range offsets {
+		steps = append(steps, offsetStep{track: true, partition: partition, offset: offset})
+	}
+	return steps
+}
+
+func completed(partition int, offsets ...int64) []offsetStep {
+	steps := make([]offsetStep, 0, len(offsets))
+	for _, offset := range offsets {
+		steps = append(steps, offsetStep{partition: partition, offset: offset})
+	}
+	return steps
+}
+
+func steps(groups ...[]offsetStep) []offsetStep {
+	var out []offsetStep
+	for _, group := range groups {
+		out = append(out, group...)
+	}
+	return out
+}
+
+func TestOffsetTrackerCommits(t *testing.T) {
+	cases := []struct {
+		name  string
+		steps []offsetStep
+		fail  int
+		want  []string
+	}{
+		{
+			name:  "in order",
+			steps: steps(fetched(0, 1, 2, 3), completed(0, 1, 2, 3)),
+			want:  []string{"p0@1", "p0@2", "p0@3"},
+		},
+		{
+			name:  "out of order completion waits for the oldest job",
+			steps: steps(fetched(0, 1, 2, 3), completed(0, 3, 2), completed(0, 1)),
+			want:  []string{"p0@3"},
+		},
+		{
+			name:  "unfinished job holds back later completions",
+			steps: steps(fetched(0, 1, 2, 3), completed(0, 1, 3)),
+			want:  []string{"p0@1"},
+		},
+		{
+			name:  "offset gaps are not waited on",
+			steps: steps(fetched(0, 10, 12, 15), completed(0, 15, 10), completed(0, 12)),
+			want:  []string{"p0@10", "p0@15"},
+		},
+		{
+			name:  "partitions advance independently",
+			steps: steps(fetched(0, 1, 2), fetched(1, 7, 8), completed(1, 7), completed(0, 2), completed(1, 8)),
+			want:  []string{"p1@7", "p1@8"},
+		},
+		{
+			name: "reassignment drops stale completions",
+			steps: steps(
+				fetched(0, 10, 11),
+				fetched(0, 5, 6),
+				completed(0, 10, 11),
+				completed(0, 5),
+				fetched(0, 10),
+				completed(0, 6),
+			),
+			want: []string{"p0@5", "p0@6"},
+		},
+		{
+			name:  "failed commit is retried on the next flush",
+			steps: steps(fetched(0, 1, 2), completed(0, 1), completed(0, 2)),
+			fail:  1,
+			want:  []string{"p0@2"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			committer := &recordingCommitter{fail: tc.fail}
+			tracker := newOffsetTracker(committer, zap.NewNop())
+			for _, step := range tc.steps {
+				msg := kafka.Message{Topic: "story.jobs", Partition: step.partition, Offset: step.offset}
+				if step.track {
+					tracker.track(msg)
+					continue
+				}
+				tracker.complete(msg)
+				tracker.flush(context.Background())
+			}
+			if !reflect.DeepEqual(committer.commits, tc.want) {
+				t.Fatalf("commits = %v, want %v", committer.commits, tc.want)
+			}
+		})
+	}
+}
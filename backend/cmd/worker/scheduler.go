@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/service"
+)
+
+type scheduledJob struct {
+	msg    kafka.Message
+	userID string
+}
+
+func newScheduledJob(msg kafka.Message) scheduledJob {
+	var job service.StoryJobMessage
+	_ = json.Unmarshal(msg.Value, &job)
+	return scheduledJob{msg: msg, userID: strings.ToLower(job.UserID)}
+}
+
+type fairScheduler struct {
+	mu            sync.Mutex
+	queues        map[string][]scheduledJob
+	ring          []string
+	cursor        int
+	served        int
+	inflight      map[string]int
+	maxPerUser    int
+	defaultWeight int
+	weights       map[string]int
+	ready         chan struct{}
+
+	// buffered counts queued jobs against capacity, but only the first
+	// perUserBuffer jobs of each user; the rest overflow so one user's
+	// backlog cannot stop other users' messages from being fetched.
+	buffered      int
+	total         int
+	capacity      int
+	perUserBuffer int
+	overflowLimit int
+	room          chan struct{}
+}
+
+// overflowFactor bounds the total number of queued jobs, overflow
+// included, to a multiple of the lane buffer.
+const overflowFactor = 16
+
+func newFairScheduler(cfg conf.Fairness, capacity int) *fairScheduler {
+	weights := make(map[string]int, len(cfg.UserWeights))
+	for id, weight := range cfg.UserWeights {
+		weights[strings.ToLower(strings.TrimSpace(id))] = weight
+	}
+	defaultWeight := cfg.DefaultWeight
+	if defaultWeight <= 0 {
+		defaultWeight = 1
+	}
+	if capacity <= 0 {
+		capacity = 1
+	}
+	perUserBuffer := cfg.MaxBufferedPerUser
+	if perUserBuffer <= 0 {
+		perUserBuffer = cfg.MaxInflightPerUser
+	}
+	if perUserBuffer <= 0 || perUserBuffer > capacity {
+		perUserBuffer = capacity
+	}
+	return &fairScheduler{
+		queues:        make(map[string][]scheduledJob),
+		inflight:      make(map[string]int),
+		maxPerUser:    cfg.MaxInflightPerUser,
+		defaultWeight: defaultWeight,
+		weights:       weights,
+		ready:         make(chan struct{}, 1),
+		capacity:      capacity,
+		perUserBuffer: perUserBuffer,
+		overflowLimit: capacity * overflowFactor,
+		room:          make(chan struct{}, 1),
+	}
+}
+
+// wait blocks until another message may be fetched into the scheduler.
+func (s *fairScheduler) wait(ctx context.Context) bool {
+	for {
+		if s.hasRoom() {
+			return true
+		}
+		select {
+		case <-s.room:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (s *fairScheduler) hasRoom() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffered < s.capacity && s.total < s.overflowLimit
+}
+
+func (s *fairScheduler) weight(userID string) int {
+	if w, ok := s.weights[userID]; ok && w > 0 {
+		return w
+	}
+	return s.defaultWeight
+}
+
+func (s *fairScheduler) push(job scheduledJob) {
+	s.mu.Lock()
+	if _, ok := s.queues[job.userID]; !ok {
+		s.ring = append(s.ring, job.userID)
+	}
+	s.queues[job.userID] = append(s.queues[job.userID], job)
+	if len(s.queues[job.userID]) <= s.perUserBuffer {
+		s.buffered++
+	}
+	s.total++
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *fairScheduler) pop() (scheduledJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.take()
+	if ok {
+		select {
+		case s.room <- struct{}{}:
+		default:
+		}
+	}
+	return job, ok
+}
+
+func (s *fairScheduler) take() (scheduledJob, bool) {
+	for i := 0; i <= len(s.ring) && len(s.ring) > 0; i++ {
+		userID := s.ring[s.cursor]
+		if s.served >= s.weight(userID) || (s.maxPerUser > 0 && s.inflight[userID] >= s.maxPerUser) {
+			s.advance()
+			continue
+		}
+		queue := s.queues[userID]
+		job := queue[0]
+		s.inflight[userID]++
+		s.served++
+		s.total--
+		if len(queue) <= s.perUserBuffer {
+			s.buffered--
+		}
+		if len(queue) == 1 {
+			delete(s.queues, userID)
+			s.ring = append(s.ring[:s.cursor], s.ring[s.cursor+1:]...)
+			if s.cursor >= len(s.ring) {
+				s.cursor = 0
+			}
+			s.served = 0
+		} else {
+			s.queues[userID] = queue[1:]
+		}
+		return job, true
+	}
+	return scheduledJob{}, false
+}
+
+func (s *fairScheduler) advance() {
+	s.cursor = (s.cursor + 1) % len(s.ring)
+	s.served = 0
+}
+
+func (s *fairScheduler) done(userID string) {
+	s.mu.Lock()
+	s.release(userID)
+	s.mu.Unlock()
+	s.signal()
+}
+
+// requeue puts a job that could not be started back at the head of its
+// user's queue and gives up the inflight slot pop reserved for it.
+func (s *fairScheduler) requeue(job scheduledJob) {
+	s.mu.Lock()
+	s.release(job.userID)
+	queue, ok := s.queues[job.userID]
+	if !ok {
+		s.ring = append(s.ring, job.userID)
+	}
+	s.queues[job.userID] = append([]scheduledJob{job}, queue...)
+	if len(s.queues[job.userID]) <= s.perUserBuffer {
+		s.buffered++
+	}
+	s.total++
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *fairScheduler) release(userID string) {
+	if s.inflight[userID] <= 1 {
+		delete(s.inflight, userID)
+	} else {
+		s.inflight[userID]--
+	}
+}
+
+func (s *fairScheduler) signal() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
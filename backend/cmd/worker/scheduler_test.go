@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"story2video-backend/internal/conf"
+)
+
+func TestFairSchedulerBacklogDoesNotStarveOtherUsers(t *testing.T) {
+	const buffer = 4
+	s := newFairScheduler(conf.Fairness{MaxInflightPerUser: 2}, buffer)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	fetch := func(userID string, offset int64) {
+		t.Helper()
+		if !s.wait(ctx) {
+			t.Fatalf("fetch of %s offset %d blocked on a full buffer", userID, offset)
+		}
+		s.push(scheduledJob{msg: kafka.Message{Offset: offset}, userID: userID})
+	}
+
+	for i := 0; i < 3*buffer; i++ {
+		fetch("a", int64(i))
+	}
+	for i := 0; i < 2; i++ {
+		if job, ok := s.pop(); !ok || job.userID != "a" {
+			t.Fatalf("pop %d = %+v, %v; want a job from a", i, job, ok)
+		}
+	}
+	if job, ok := s.pop(); ok {
+		t.Fatalf("pop past the per-user cap returned %+v", job)
+	}
+
+	fetch("b", int64(3*buffer))
+	job, ok := s.pop()
+	if !ok || job.userID != "b" {
+		t.Fatalf("pop = %+v, %v; want the job from b", job, ok)
+	}
+}
+
+func TestFairSchedulerOverflowLimit(t *testing.T) {
+	const buffer = 2
+	s := newFairScheduler(conf.Fairness{MaxInflightPerUser: 1}, buffer)
+	for i := 0; i < buffer*overflowFactor; i++ {
+		if !s.hasRoom() {
+			t.Fatalf("no room after %d jobs", i)
+		}
+		s.push(scheduledJob{msg: kafka.Message{Offset: int64(i)}, userID: "a"})
+	}
+	if s.hasRoom() {
+		t.Fatalf("room left after %d jobs, want the overflow limit to apply", buffer*overflowFactor)
+	}
+	if _, ok := s.pop(); !ok {
+		t.Fatal("pop returned nothing")
+	}
+	if !s.hasRoom() {
+		t.Fatal("no room after a job left the queue")
+	}
+}
+
+func TestFairSchedulerRequeueRestoresJob(t *testing.T) {
+	s := newFairScheduler(conf.Fairness{MaxInflightPerUser: 1}, 4)
+	s.push(scheduledJob{msg: kafka.Message{Offset: 1}, userID: "a"})
+	s.push(scheduledJob{msg: kafka.Message{Offset: 2}, userID: "a"})
+
+	job, ok := s.pop()
+	if !ok || job.msg.Offset != 1 {
+		t.Fatalf("pop = %+v, %v; want offset 1", job, ok)
+	}
+	s.requeue(job)
+
+	job, ok = s.pop()
+	if !ok || job.msg.Offset != 1 {
+		t.Fatalf("pop after requeue = %+v, %v; want offset 1 again", job, ok)
+	}
+	if job, ok := s.pop(); ok {
+		t.Fatalf("pop past the per-user cap returned %+v", job)
+	}
+	s.done(job.userID)
+	if job, ok := s.pop(); !ok || job.msg.Offset != 2 {
+		t.Fatalf("pop = %+v, %v; want offset 2", job, ok)
+	}
+	if s.buffered != 0 || s.total != 0 {
+		t.Fatalf("buffered = %d, total = %d after draining; want 0", s.buffered, s.total)
+	}
+}
@@ -1,5 +1,6 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
 
 
 CREATE TABLE IF NOT EXISTS stories (
@@ -13,6 +14,7 @@ CREATE TABLE IF NOT EXISTS stories (
     timeline    JSONB,
     cover_url   VARCHAR(512),
     video_url   VARCHAR(512),
+    parent_story_id UUID REFERENCES stories(id) ON DELETE SET NULL,
     created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     deleted_at  TIMESTAMPTZ
@@ -20,6 +22,10 @@ CREATE TABLE IF NOT EXISTS stories (
 
 CREATE INDEX IF NOT EXISTS idx_stories_user_id ON stories (user_id);
 CREATE INDEX IF NOT EXISTS idx_stories_status ON stories (status);
+CREATE INDEX IF NOT EXISTS idx_stories_parent_story_id ON stories (parent_story_id);
+CREATE INDEX IF NOT EXISTS idx_stories_user_updated_at ON stories (user_id, updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_stories_title_trgm ON stories USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_stories_content_trgm ON stories USING gin (content gin_trgm_ops);
 
 CREATE TABLE IF NOT EXISTS shots (
     id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -36,6 +42,8 @@ CREATE TABLE IF NOT EXISTS shots (
     status      VARCHAR(16) NOT NULL DEFAULT 'pending',
     image_url   VARCHAR(512),
     bgm         VARCHAR(255),
+    audio_url   VARCHAR(512),
+    audio_duration_ms BIGINT NOT NULL DEFAULT 0,
     created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     deleted_at  TIMESTAMPTZ
@@ -44,18 +52,41 @@ CREATE TABLE IF NOT EXISTS shots (
 CREATE INDEX IF NOT EXISTS idx_shots_story_id ON shots (story_id);
 CREATE INDEX IF NOT EXISTS idx_shots_story_sequence ON shots (story_id, sequence);
 CREATE INDEX IF NOT EXISTS idx_shots_status ON shots (status);
+CREATE INDEX IF NOT EXISTS idx_shots_narration_trgm ON shots USING gin (narration gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS batches (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID        NOT NULL,
+    status       VARCHAR(16) NOT NULL DEFAULT 'running',
+    total        INTEGER     NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_batches_user_id ON batches (user_id);
+CREATE INDEX IF NOT EXISTS idx_batches_status ON batches (status);
 
 CREATE TABLE IF NOT EXISTS operations (
     id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id     UUID        NOT NULL,
     story_id    UUID        NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
     shot_id     UUID,
+    batch_id    UUID REFERENCES batches(id) ON DELETE SET NULL,
+    parent_id   UUID REFERENCES operations(id) ON DELETE CASCADE,
     type        VARCHAR(32) NOT NULL,
     payload     JSONB,
+    result      JSONB,
     status      VARCHAR(16) NOT NULL DEFAULT 'queued',
     retries     INTEGER     NOT NULL DEFAULT 0,
     error_msg   TEXT,
+    error_code  VARCHAR(16),
+    error_retryable BOOLEAN NOT NULL DEFAULT FALSE,
+    error_details   JSONB,
     worker      VARCHAR(64),
+    scheduled_at TIMESTAMPTZ,
+    dispatch_lease TIMESTAMPTZ,
     started_at  TIMESTAMPTZ,
     finished_at TIMESTAMPTZ,
     created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -65,7 +96,35 @@ CREATE TABLE IF NOT EXISTS operations (
 
 CREATE INDEX IF NOT EXISTS idx_operations_story_id ON operations (story_id);
 CREATE INDEX IF NOT EXISTS idx_operations_shot_id ON operations (shot_id);
-CREATE INDEX IF NOT EXISTS idx_operations_status ON operations (status);
+CREATE INDEX IF NOT EXISTS idx_operations_status_created_at ON operations (status, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_operations_scheduled_at ON operations (scheduled_at);
+CREATE INDEX IF NOT EXISTS idx_operations_dispatch_lease ON operations (dispatch_lease);
+CREATE INDEX IF NOT EXISTS idx_operations_batch_id ON operations (batch_id);
+CREATE INDEX IF NOT EXISTS idx_operations_parent_id ON operations (parent_id);
+
+CREATE TABLE IF NOT EXISTS moderation_records (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID        NOT NULL,
+    story_id    UUID        NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    shot_id     UUID REFERENCES shots(id) ON DELETE CASCADE,
+    target      VARCHAR(16) NOT NULL,
+    backend     VARCHAR(32),
+    categories  JSONB,
+    reason      TEXT,
+    content     JSONB,
+    status      VARCHAR(16) NOT NULL DEFAULT 'pending',
+    reviewer_id UUID,
+    review_note TEXT,
+    reviewed_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_records_user_id ON moderation_records (user_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_records_story_id ON moderation_records (story_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_records_shot_id ON moderation_records (shot_id);
+CREATE INDEX IF NOT EXISTS idx_moderation_records_status ON moderation_records (status);
 
 
 
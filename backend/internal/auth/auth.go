@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderUserID   = "X-User-ID"
+	MetadataUserID = "x-user-id"
+)
+
+var (
+	ErrMissingUser = errors.New("missing user id")
+	ErrInvalidUser = errors.New("invalid user id")
+)
+
+type Identity struct {
+	UserID uuid.UUID
+	Admin  bool
+}
+
+type identityKey struct{}
+
+type Authenticator struct {
+	admins map[uuid.UUID]struct{}
+}
+
+func NewAuthenticator(adminIDs []string) *Authenticator {
+	admins := make(map[uuid.UUID]struct{}, len(adminIDs))
+	for _, raw := range adminIDs {
+		if id, err := uuid.Parse(strings.TrimSpace(raw)); err == nil {
+			admins[id] = struct{}{}
+		}
+	}
+	return &Authenticator{admins: admins}
+}
+
+func (a *Authenticator) Authenticate(rawUserID string) (Identity, error) {
+	rawUserID = strings.TrimSpace(rawUserID)
+	if rawUserID == "" {
+		return Identity{}, ErrMissingUser
+	}
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return Identity{}, ErrInvalidUser
+	}
+	_, admin := a.admins[userID]
+	return Identity{UserID: userID, Admin: admin}, nil
+}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -10,10 +10,12 @@ import (
 )
 
 type Server struct {
-	Port         int    `mapstructure:"port"`
-	Mode         string `mapstructure:"mode"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
+	Port          int    `mapstructure:"port"`
+	Mode          string `mapstructure:"mode"`
+	ReadTimeout   int    `mapstructure:"read_timeout"`
+	WriteTimeout  int    `mapstructure:"write_timeout"`
+	ContractCheck string `mapstructure:"contract_check"`
+	GRPCPort      int    `mapstructure:"grpc_port"`
 }
 
 type Database struct {
@@ -39,8 +41,9 @@ type Redis struct {
 }
 
 type Pool struct {
-	Size          int `mapstructure:"size"`
-	ExpirySeconds int `mapstructure:"expiry_seconds"`
+	Size                int `mapstructure:"size"`
+	ExpirySeconds       int `mapstructure:"expiry_seconds"`
+	DrainTimeoutSeconds int `mapstructure:"drain_timeout_seconds"`
 }
 
 type GRPC struct {
@@ -54,22 +57,124 @@ type ModelService struct {
 }
 
 type Kafka struct {
-	Brokers             []string `mapstructure:"brokers"`
-	Topic               string   `mapstructure:"topic"`
-	Group               string   `mapstructure:"group"`
-	Partitions          int      `mapstructure:"partitions"`
-	ReplicationFactor   int      `mapstructure:"replication_factor"`
-	AutoCreateTopic     bool     `mapstructure:"auto_create_topic"`
-	WriteTimeoutSeconds int      `mapstructure:"write_timeout_seconds"`
-	BatchTimeoutMillis  int      `mapstructure:"batch_timeout_millis"`
-	MaxAttempts         int      `mapstructure:"max_attempts"`
-	RequiredAcks        int      `mapstructure:"required_acks"`
+	Brokers             []string             `mapstructure:"brokers"`
+	Topic               string               `mapstructure:"topic"`
+	Group               string               `mapstructure:"group"`
+	Partitions          int                  `mapstructure:"partitions"`
+	ReplicationFactor   int                  `mapstructure:"replication_factor"`
+	AutoCreateTopic     bool                 `mapstructure:"auto_create_topic"`
+	WriteTimeoutSeconds int                  `mapstructure:"write_timeout_seconds"`
+	BatchTimeoutMillis  int                  `mapstructure:"batch_timeout_millis"`
+	MaxAttempts         int                  `mapstructure:"max_attempts"`
+	RequiredAcks        int                  `mapstructure:"required_acks"`
+	Lanes               map[string]KafkaLane `mapstructure:"lanes"`
+	PriorityUserIDs     []string             `mapstructure:"priority_user_ids"`
+	BatchEventTopic     string               `mapstructure:"batch_event_topic"`
+}
+
+type KafkaLane struct {
+	Topic       string `mapstructure:"topic"`
+	Concurrency int    `mapstructure:"concurrency"`
 }
 
 type CORS struct {
 	AllowOrigins []string `mapstructure:"allow_origins"`
 }
 
+type Export struct {
+	Dir                 string   `mapstructure:"dir"`
+	AsyncShotThreshold  int      `mapstructure:"async_shot_threshold"`
+	FetchTimeoutSeconds int      `mapstructure:"fetch_timeout_seconds"`
+	MaxAssetBytes       int64    `mapstructure:"max_asset_bytes"`
+	AllowedAssetHosts   []string `mapstructure:"allowed_asset_hosts"`
+}
+
+type Storage struct {
+	AssetDir       string `mapstructure:"asset_dir"`
+	AssetURLPrefix string `mapstructure:"asset_url_prefix"`
+	MaxAssetBytes  int64  `mapstructure:"max_asset_bytes"`
+	MaxImportBytes int64  `mapstructure:"max_import_bytes"`
+}
+
+type Script struct {
+	MinRunes              int      `mapstructure:"min_runes"`
+	MaxRunes              int      `mapstructure:"max_runes"`
+	MaxShots              int      `mapstructure:"max_shots"`
+	UnitsPerShot          int      `mapstructure:"units_per_shot"`
+	AllowedLanguages      []string `mapstructure:"allowed_languages"`
+	BannedWords           []string `mapstructure:"banned_words"`
+	ShotProcessingSeconds int      `mapstructure:"shot_processing_seconds"`
+	CostPerShot           float64  `mapstructure:"cost_per_shot"`
+	CostPerVideoMinute    float64  `mapstructure:"cost_per_video_minute"`
+}
+
+type Moderation struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Backend        string   `mapstructure:"backend"`
+	Keywords       []string `mapstructure:"keywords"`
+	Patterns       []string `mapstructure:"patterns"`
+	HTTPURL        string   `mapstructure:"http_url"`
+	HTTPToken      string   `mapstructure:"http_token"`
+	TimeoutSeconds int      `mapstructure:"timeout_seconds"`
+	FailOpen       bool     `mapstructure:"fail_open"`
+	AdminUserIDs   []string `mapstructure:"admin_user_ids"`
+}
+
+type Search struct {
+	Mode             string `mapstructure:"mode"`
+	TextSearchConfig string `mapstructure:"text_search_config"`
+	SnippetRunes     int    `mapstructure:"snippet_runes"`
+}
+
+type Pagination struct {
+	CursorSecret string `mapstructure:"cursor_secret"`
+}
+
+type I18n struct {
+	DefaultLocale string `mapstructure:"default_locale"`
+}
+
+type Metrics struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	WorkerAddr    string `mapstructure:"worker_addr"`
+	RPCServerAddr string `mapstructure:"rpcserver_addr"`
+}
+
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type Health struct {
+	TimeoutSeconds       int    `mapstructure:"timeout_seconds"`
+	WorkerAddr           string `mapstructure:"worker_addr"`
+	ModelProbePath       string `mapstructure:"model_probe_path"`
+	ProbeIntervalSeconds int    `mapstructure:"probe_interval_seconds"`
+}
+
+type Fairness struct {
+	MaxInflightPerUser int            `mapstructure:"max_inflight_per_user"`
+	BufferSize         int            `mapstructure:"buffer_size"`
+	MaxBufferedPerUser int            `mapstructure:"max_buffered_per_user"`
+	DefaultWeight      int            `mapstructure:"default_weight"`
+	UserWeights        map[string]int `mapstructure:"user_weights"`
+}
+
+type Scheduler struct {
+	Enabled             bool `mapstructure:"enabled"`
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"`
+	BatchSize           int  `mapstructure:"batch_size"`
+	MaxAheadDays        int  `mapstructure:"max_ahead_days"`
+}
+
+type Pipeline struct {
+	Enabled         bool `mapstructure:"enabled"`
+	Compose         bool `mapstructure:"compose"`
+	MaxStageRetries int  `mapstructure:"max_stage_retries"`
+}
+
 type Config struct {
 	Server       Server       `mapstructure:"server"`
 	Database     Database     `mapstructure:"database"`
@@ -79,6 +184,19 @@ type Config struct {
 	ModelService ModelService `mapstructure:"model_service"`
 	Kafka        Kafka        `mapstructure:"kafka"`
 	CORS         CORS         `mapstructure:"cors"`
+	Export       Export       `mapstructure:"export"`
+	Storage      Storage      `mapstructure:"storage"`
+	Script       Script       `mapstructure:"script"`
+	Moderation   Moderation   `mapstructure:"moderation"`
+	Search       Search       `mapstructure:"search"`
+	Pagination   Pagination   `mapstructure:"pagination"`
+	I18n         I18n         `mapstructure:"i18n"`
+	Metrics      Metrics      `mapstructure:"metrics"`
+	Tracing      Tracing      `mapstructure:"tracing"`
+	Health       Health       `mapstructure:"health"`
+	Fairness     Fairness     `mapstructure:"fairness"`
+	Scheduler    Scheduler    `mapstructure:"scheduler"`
+	Pipeline     Pipeline     `mapstructure:"pipeline"`
 }
 
 func Load(path string) (*Config, error) {
@@ -128,6 +246,8 @@ func overrideFromEnv(cfg *Config) {
 
 	setString("SERVER_MODE", &cfg.Server.Mode)
 	setInt("SERVER_PORT", &cfg.Server.Port)
+	setString("SERVER_CONTRACT_CHECK", &cfg.Server.ContractCheck)
+	setInt("SERVER_GRPC_PORT", &cfg.Server.GRPCPort)
 
 	setString("DATABASE_HOST", &cfg.Database.Host)
 	setInt("DATABASE_PORT", &cfg.Database.Port)
@@ -142,6 +262,20 @@ func overrideFromEnv(cfg *Config) {
 
 	setInt("POOL_SIZE", &cfg.Pool.Size)
 	setInt("POOL_EXPIRY_SECONDS", &cfg.Pool.ExpirySeconds)
+	setInt("POOL_DRAIN_TIMEOUT_SECONDS", &cfg.Pool.DrainTimeoutSeconds)
+
+	setInt("FAIRNESS_MAX_INFLIGHT_PER_USER", &cfg.Fairness.MaxInflightPerUser)
+	setInt("FAIRNESS_BUFFER_SIZE", &cfg.Fairness.BufferSize)
+	setInt("FAIRNESS_MAX_BUFFERED_PER_USER", &cfg.Fairness.MaxBufferedPerUser)
+	setInt("FAIRNESS_DEFAULT_WEIGHT", &cfg.Fairness.DefaultWeight)
+
+	setBool("SCHEDULER_ENABLED", &cfg.Scheduler.Enabled)
+	setInt("SCHEDULER_POLL_INTERVAL_SECONDS", &cfg.Scheduler.PollIntervalSeconds)
+	setInt("SCHEDULER_BATCH_SIZE", &cfg.Scheduler.BatchSize)
+	setInt("SCHEDULER_MAX_AHEAD_DAYS", &cfg.Scheduler.MaxAheadDays)
+	setBool("PIPELINE_ENABLED", &cfg.Pipeline.Enabled)
+	setBool("PIPELINE_COMPOSE", &cfg.Pipeline.Compose)
+	setInt("PIPELINE_MAX_STAGE_RETRIES", &cfg.Pipeline.MaxStageRetries)
 
 	setString("GRPC_ADDR", &cfg.GRPC.Addr)
 	setInt("GRPC_DIAL_TIMEOUT", &cfg.GRPC.DialTimeout)
@@ -161,4 +295,57 @@ func overrideFromEnv(cfg *Config) {
 	setInt("KAFKA_BATCH_TIMEOUT_MILLIS", &cfg.Kafka.BatchTimeoutMillis)
 	setInt("KAFKA_MAX_ATTEMPTS", &cfg.Kafka.MaxAttempts)
 	setInt("KAFKA_REQUIRED_ACKS", &cfg.Kafka.RequiredAcks)
+	setString("KAFKA_BATCH_EVENT_TOPIC", &cfg.Kafka.BatchEventTopic)
+	if priorityUsers := strings.TrimSpace(os.Getenv("KAFKA_PRIORITY_USER_IDS")); priorityUsers != "" {
+		cfg.Kafka.PriorityUserIDs = strings.Split(priorityUsers, ",")
+	}
+
+	setString("EXPORT_DIR", &cfg.Export.Dir)
+	setInt("EXPORT_ASYNC_SHOT_THRESHOLD", &cfg.Export.AsyncShotThreshold)
+	setInt("EXPORT_FETCH_TIMEOUT_SECONDS", &cfg.Export.FetchTimeoutSeconds)
+	if hosts := strings.TrimSpace(os.Getenv("EXPORT_ALLOWED_ASSET_HOSTS")); hosts != "" {
+		cfg.Export.AllowedAssetHosts = strings.Split(hosts, ",")
+	}
+
+	setString("STORAGE_ASSET_DIR", &cfg.Storage.AssetDir)
+	setString("STORAGE_ASSET_URL_PREFIX", &cfg.Storage.AssetURLPrefix)
+
+	setInt("SCRIPT_MIN_RUNES", &cfg.Script.MinRunes)
+	setInt("SCRIPT_MAX_RUNES", &cfg.Script.MaxRunes)
+	setInt("SCRIPT_MAX_SHOTS", &cfg.Script.MaxShots)
+
+	setString("SEARCH_MODE", &cfg.Search.Mode)
+	setString("SEARCH_TEXT_SEARCH_CONFIG", &cfg.Search.TextSearchConfig)
+
+	setString("PAGINATION_CURSOR_SECRET", &cfg.Pagination.CursorSecret)
+
+	setString("I18N_DEFAULT_LOCALE", &cfg.I18n.DefaultLocale)
+
+	setBool("METRICS_ENABLED", &cfg.Metrics.Enabled)
+	setString("METRICS_WORKER_ADDR", &cfg.Metrics.WorkerAddr)
+	setString("METRICS_RPCSERVER_ADDR", &cfg.Metrics.RPCServerAddr)
+
+	setInt("HEALTH_TIMEOUT_SECONDS", &cfg.Health.TimeoutSeconds)
+	setString("HEALTH_WORKER_ADDR", &cfg.Health.WorkerAddr)
+	setString("HEALTH_MODEL_PROBE_PATH", &cfg.Health.ModelProbePath)
+	setInt("HEALTH_PROBE_INTERVAL_SECONDS", &cfg.Health.ProbeIntervalSeconds)
+
+	setString("TRACING_EXPORTER", &cfg.Tracing.Exporter)
+	setString("TRACING_ENDPOINT", &cfg.Tracing.Endpoint)
+	setBool("TRACING_INSECURE", &cfg.Tracing.Insecure)
+	if ratio := strings.TrimSpace(os.Getenv("TRACING_SAMPLE_RATIO")); ratio != "" {
+		if parsed, err := strconv.ParseFloat(ratio, 64); err == nil {
+			cfg.Tracing.SampleRatio = parsed
+		}
+	}
+
+	setBool("MODERATION_ENABLED", &cfg.Moderation.Enabled)
+	setString("MODERATION_BACKEND", &cfg.Moderation.Backend)
+	setString("MODERATION_HTTP_URL", &cfg.Moderation.HTTPURL)
+	setString("MODERATION_HTTP_TOKEN", &cfg.Moderation.HTTPToken)
+	setInt("MODERATION_TIMEOUT_SECONDS", &cfg.Moderation.TimeoutSeconds)
+	setBool("MODERATION_FAIL_OPEN", &cfg.Moderation.FailOpen)
+	if admins := strings.TrimSpace(os.Getenv("MODERATION_ADMIN_USER_IDS")); admins != "" {
+		cfg.Moderation.AdminUserIDs = strings.Split(admins, ",")
+	}
 }
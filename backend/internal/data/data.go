@@ -40,7 +40,7 @@ func NewDataWithOptions(ctx context.Context, cfg *conf.Config, log *zap.Logger,
 		return nil, nil, err
 	}
 	if !opts.SkipMigration {
-		if err := db.AutoMigrate(&model.Story{}, &model.Shot{}, &model.Operation{}); err != nil {
+		if err := db.AutoMigrate(&model.Story{}, &model.Shot{}, &model.Operation{}, &model.Batch{}, &model.ModerationRecord{}); err != nil {
 			return nil, nil, fmt.Errorf("auto migrate: %w", err)
 		}
 	}
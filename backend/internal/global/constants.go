@@ -5,6 +5,7 @@ const (
 	StoryGen   = "generating"
 	StoryReady = "ready"
 	StoryFail  = "failed"
+	StoryFlag  = "flagged"
 )
 
 const (
@@ -12,14 +13,21 @@ const (
 	ShotRender  = "rendering"
 	ShotDone    = "done"
 	ShotFail    = "failed"
+	ShotFlag    = "flagged"
 )
 
 const (
-	OpQueued  = "queued"
-	OpRunning = "running"
-	OpSuccess = "succeeded"
-	OpFail    = "failed"
-	OpCancel  = "cancelled"
+	OpScheduled = "scheduled"
+	OpQueued    = "queued"
+	OpRunning   = "running"
+	OpSuccess   = "succeeded"
+	OpFail      = "failed"
+	OpCancel    = "cancelled"
+)
+
+const (
+	BatchRunning   = "running"
+	BatchCompleted = "completed"
 )
 
 const (
@@ -31,6 +39,18 @@ const (
 	OpStoryboard  = "story_create"
 	OpShotRegen   = "shot_regen"
 	OpVideoRender = "video_render"
+	OpExport      = "story_export"
+)
+
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+)
+
+const (
+	ModerationTargetScript = "script"
+	ModerationTargetShot   = "shot"
 )
 
 const (
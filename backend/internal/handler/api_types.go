@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/model"
+	"story2video-backend/internal/service"
+)
+
+type storyListQuery struct {
+	Keyword      string `form:"keyword"`
+	Title        string `form:"title"`
+	Q            string `form:"q"`
+	Status       string `form:"status"`
+	CompileState string `form:"compile_state"`
+	Style        string `form:"style"`
+	SortBy       string `form:"sort_by"`
+	Order        string `form:"order"`
+	StartTime    string `form:"start_time"`
+	EndTime      string `form:"end_time"`
+	PageSize     int    `form:"page_size"`
+	PageToken    string `form:"page_token"`
+}
+
+type pageQuery struct {
+	PageSize  int    `form:"page_size"`
+	PageToken string `form:"page_token"`
+}
+
+type operationListQuery struct {
+	StoryID   string `form:"story_id"`
+	Type      string `form:"type"`
+	Status    string `form:"status"`
+	StartTime string `form:"start_time"`
+	EndTime   string `form:"end_time"`
+	PageSize  int    `form:"page_size"`
+	PageToken string `form:"page_token"`
+}
+
+type storyOperationListQuery struct {
+	Type      string `form:"type"`
+	Status    string `form:"status"`
+	StartTime string `form:"start_time"`
+	EndTime   string `form:"end_time"`
+	PageSize  int    `form:"page_size"`
+	PageToken string `form:"page_token"`
+}
+
+type subtitleQuery struct {
+	Format string `form:"format"`
+}
+
+type exportQuery struct {
+	Format string `form:"format"`
+	Async  bool   `form:"async"`
+}
+
+type moderationListQuery struct {
+	Status    string `form:"status"`
+	Target    string `form:"target"`
+	PageSize  int    `form:"page_size"`
+	PageToken string `form:"page_token"`
+}
+
+type uploadStoryForm struct {
+	File            *multipart.FileHeader `form:"file" binding:"required"`
+	DisplayName     string                `form:"display_name"`
+	Style           string                `form:"style"`
+	MaxChapterChars int                   `form:"max_chapter_chars"`
+	DryRun          bool                  `form:"dry_run"`
+}
+
+type operationRef struct {
+	OperationName string    `json:"operation_name"`
+	ShotID        uuid.UUID `json:"shot_id"`
+	State         string    `json:"state"`
+}
+
+type acceptedOperationResponse struct {
+	OperationName string `json:"operation_name"`
+	State         string `json:"state"`
+}
+
+type exportOperationResponse struct {
+	OperationName string `json:"operation_name"`
+	State         string `json:"state"`
+	DownloadURL   string `json:"download_url"`
+}
+
+type storyShot struct {
+	ShotID      uuid.UUID `json:"shot_id"`
+	Index       int       `json:"index"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Details     string    `json:"details"`
+	Narration   string    `json:"narration"`
+	Type        string    `json:"type"`
+	Transition  string    `json:"transition"`
+	Voice       string    `json:"voice"`
+	ImageURL    string    `json:"image_url"`
+	BGM         string    `json:"bgm"`
+	Status      string    `json:"status"`
+}
+
+type storyDetail struct {
+	StoryID       uuid.UUID             `json:"story_id"`
+	DisplayName   string                `json:"display_name"`
+	ScriptContent string                `json:"script_content"`
+	Style         string                `json:"style"`
+	VideoURL      string                `json:"video_url"`
+	CompileState  string                `json:"compile_state"`
+	CoverURL      string                `json:"cover_url"`
+	CreateTime    time.Time             `json:"create_time"`
+	ParentStoryID *uuid.UUID            `json:"parent_story_id"`
+	Shots         []storyShot           `json:"shots"`
+	Lineage       *service.StoryLineage `json:"lineage,omitempty"`
+}
+
+type storyResponse struct {
+	Story storyDetail `json:"story"`
+}
+
+type storyWithOperationsResponse struct {
+	Story      storyDetail    `json:"story"`
+	Operations []operationRef `json:"operations"`
+}
+
+type storyListItem struct {
+	StoryID      uuid.UUID                `json:"story_id"`
+	DisplayName  string                   `json:"display_name"`
+	CoverURL     string                   `json:"cover_url"`
+	CreateTime   time.Time                `json:"create_time"`
+	CompileState string                   `json:"compile_state"`
+	VideoURL     string                   `json:"video_url"`
+	Highlights   []service.StoryHighlight `json:"highlights,omitempty"`
+}
+
+type storyListResponse struct {
+	Items         []storyListItem `json:"items"`
+	NextPageToken string          `json:"next_page_token"`
+}
+
+type batchCreateItem struct {
+	Index         int               `json:"index"`
+	Success       bool              `json:"success"`
+	DisplayName   string            `json:"display_name,omitempty"`
+	ErrorCode     service.ErrorCode `json:"error_code,omitempty"`
+	ErrorMessage  string            `json:"error_message,omitempty"`
+	OperationName string            `json:"operation_name,omitempty"`
+	State         string            `json:"state,omitempty"`
+	CreateTime    *time.Time        `json:"create_time,omitempty"`
+}
+
+type batchCreateResponse struct {
+	BatchName string            `json:"batch_name,omitempty"`
+	Items     []batchCreateItem `json:"items"`
+}
+
+type uploadChapter struct {
+	Index       int    `json:"index"`
+	Title       string `json:"title"`
+	DisplayName string `json:"display_name"`
+	CharCount   int    `json:"char_count"`
+	Preview     string `json:"preview"`
+}
+
+type uploadPreviewResponse struct {
+	Title    string          `json:"title"`
+	Format   string          `json:"format"`
+	Chapters []uploadChapter `json:"chapters"`
+}
+
+type uploadResponse struct {
+	Title     string            `json:"title"`
+	Format    string            `json:"format"`
+	BatchName string            `json:"batch_name,omitempty"`
+	Items     []batchCreateItem `json:"items"`
+}
+
+type shotListResponse struct {
+	Shots         []model.Shot `json:"shots"`
+	NextPageToken string       `json:"next_page_token,omitempty"`
+}
+
+type operationListResponse struct {
+	Operations    []service.OperationView `json:"operations"`
+	NextPageToken string                  `json:"next_page_token"`
+}
+
+type operationResponse struct {
+	*model.Operation
+	Error *service.OperationStatus `json:"error,omitempty"`
+}
+
+type moderationListResponse struct {
+	Items         []model.ModerationRecord `json:"items"`
+	Total         int64                    `json:"total"`
+	NextPageToken string                   `json:"next_page_token"`
+}
+
+type moderationReviewResponse struct {
+	Record        *model.ModerationRecord `json:"record"`
+	OperationName string                  `json:"operation_name,omitempty"`
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/service"
+)
+
+type BatchHandler struct {
+	service *service.BatchService
+}
+
+func NewBatchHandler(service *service.BatchService) *BatchHandler {
+	return &BatchHandler{service: service}
+}
+
+func (h *BatchHandler) Get(c *gin.Context) {
+	h.respond(c, h.service.Get)
+}
+
+func (h *BatchHandler) Cancel(c *gin.Context) {
+	h.respond(c, h.service.Cancel)
+}
+
+func (h *BatchHandler) Retry(c *gin.Context) {
+	h.respond(c, h.service.Retry)
+}
+
+func (h *BatchHandler) respond(c *gin.Context, action func(ctx context.Context, userID, batchID uuid.UUID) (*service.BatchView, error)) {
+	batchID, err := parseUUIDParam(c, "batchID")
+	if err != nil {
+		respondInvalidField(c, "batch_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	view, err := action(c.Request.Context(), userID, batchID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
@@ -1,15 +1,14 @@
 package handler
 
 import (
-	"errors"
-
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"story2video-backend/internal/middleware"
+	"story2video-backend/internal/service"
 )
 
-var errNoUser = errors.New("missing user")
+var errNoUser = service.NewServiceError(service.ErrCodeUnauthenticated, "")
 
 func userIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	val, exists := c.Get(middleware.ContextUserIDKey)
@@ -22,3 +21,7 @@ func userIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	}
 	return id, nil
 }
+
+func requestLocale(c *gin.Context) string {
+	return middleware.RequestLocale(c)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/middleware"
+	"story2video-backend/internal/service"
+)
+
+func CollectionMethods(param string, methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fn, ok := methods[c.Param(param)]
+		if !ok {
+			middleware.AbortWithError(c, service.ErrCodeMethodNotFound, "")
+			return
+		}
+		fn(c)
+	}
+}
+
+func ResourceMethods(param string, methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.Param(param)
+		idx := strings.LastIndex(value, ":")
+		if idx == -1 {
+			middleware.AbortWithError(c, service.ErrCodeMethodNotFound, "")
+			return
+		}
+		fn, ok := methods[value[idx+1:]]
+		if !ok {
+			middleware.AbortWithError(c, service.ErrCodeMethodNotFound, "")
+			return
+		}
+		for i := range c.Params {
+			if c.Params[i].Key == param {
+				c.Params[i].Value = value[:idx]
+			}
+		}
+		fn(c)
+	}
+}
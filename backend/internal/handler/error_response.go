@@ -1,61 +1,56 @@
 package handler
 
 import (
-	"net/http"
+	"errors"
 
 	"github.com/gin-gonic/gin"
 
+	"story2video-backend/internal/middleware"
 	"story2video-backend/internal/service"
 )
 
+type fieldError struct {
+	field  string
+	key    service.MessageKey
+	params service.MessageParams
+}
+
+func (e *fieldError) Error() string {
+	return "invalid " + e.field
+}
+
+func invalidField(field string) *fieldError {
+	return &fieldError{field: field, key: service.MsgFieldInvalid}
+}
+
 func respondServiceError(c *gin.Context, err error) {
-	if err == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	var fe *fieldError
+	if errors.As(err, &fe) {
+		respondFieldViolation(c, fe.field, fe.key, fe.params)
 		return
 	}
 	if svcErr, ok := service.AsServiceError(err); ok {
-		status := httpStatusFromCode(svcErr.Code)
-		message := svcErr.Message
-		if message == "" {
-			message = svcErr.Code.DefaultMessage()
-		}
-		if message == "" {
-			message = "服务处理失败"
-		}
-		c.JSON(status, gin.H{
-			"error":      message,
-			"error_code": string(svcErr.Code),
-		})
+		middleware.AbortWithError(c, svcErr.Code, svcErr.LocalizedMessage(requestLocale(c)))
 		return
 	}
-	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-}
-
-func httpStatusFromCode(code service.ErrorCode) int {
-	switch code {
-	case service.ErrCodeInvalidRequest,
-		service.ErrCodeInvalidStyle,
-		service.ErrCodeInvalidShotDetails:
-		return http.StatusBadRequest
-	case service.ErrCodeStoryNotFound,
-		service.ErrCodeShotNotFound:
-		return http.StatusNotFound
-	case service.ErrCodeOperationTimeout:
-		return http.StatusGatewayTimeout
-	case service.ErrCodeKafkaConfigInvalid:
-		return http.StatusInternalServerError
-	case service.ErrCodeJobEnqueueFailed,
-		service.ErrCodeWorkerExecutionFailed,
-		service.ErrCodeResultDataMissing,
-		service.ErrCodeShotMissingPartial,
-		service.ErrCodeShotContentMissing,
-		service.ErrCodeShotAssetMissing:
-		return http.StatusBadGateway
-	case service.ErrCodeOperationCreateFailed,
-		service.ErrCodeOperationUpdateFailed,
-		service.ErrCodeDatabaseActionFailed:
-		return http.StatusInternalServerError
-	default:
-		return http.StatusInternalServerError
+	middleware.AbortWithError(c, service.ErrCodeInternal, "")
+}
+
+func respondInvalidField(c *gin.Context, field string) {
+	respondFieldViolation(c, field, service.MsgFieldInvalid, nil)
+}
+
+func respondFieldViolation(c *gin.Context, field string, key service.MessageKey, params service.MessageParams) {
+	middleware.AbortWithError(c, service.ErrCodeInvalidRequest, "", fieldViolation(c, field, key, params))
+}
+
+func respondBindingError(c *gin.Context, err error) {
+	middleware.AbortWithError(c, service.ErrCodeInvalidRequest, "", bindingViolations(c, err, "")...)
+}
+
+func fieldViolation(c *gin.Context, field string, key service.MessageKey, params service.MessageParams) middleware.FieldViolation {
+	return middleware.FieldViolation{
+		Field:       field,
+		Description: service.Localize(requestLocale(c), key, params),
 	}
 }
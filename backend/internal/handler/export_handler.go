@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/service"
+)
+
+type ExportHandler struct {
+	service *service.ExportService
+}
+
+func NewExportHandler(service *service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+func (h *ExportHandler) Export(c *gin.Context) {
+	storyID, err := parseUUIDParam(c, "storyID")
+	if err != nil {
+		respondInvalidField(c, "story_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	async := false
+	if raw := c.Query("async"); raw != "" {
+		async, err = strconv.ParseBool(raw)
+		if err != nil {
+			respondInvalidField(c, "async")
+			return
+		}
+	}
+
+	result, err := h.service.Export(c.Request.Context(), userID, storyID, c.Query("format"), async)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	if result.Operation != nil {
+		c.JSON(http.StatusAccepted, exportOperationResponse{
+			OperationName: fmt.Sprintf("operations/%s", result.Operation.ID),
+			State:         result.Operation.Status,
+			DownloadURL:   fmt.Sprintf("/v1/operations/%s/download", result.Operation.ID),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.File.FileName))
+	c.Data(http.StatusOK, result.File.ContentType, result.File.Content)
+}
+
+func (h *ExportHandler) Download(c *gin.Context) {
+	opID, err := parseUUIDParam(c, "operationID")
+	if err != nil {
+		respondInvalidField(c, "operation_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	artifact, path, err := h.service.Download(c.Request.Context(), userID, opID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.Header("Content-Type", artifact.ContentType)
+	c.FileAttachment(path, artifact.FileName)
+}
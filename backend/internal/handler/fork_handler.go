@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/service"
+)
+
+type ForkHandler struct {
+	service *service.ForkService
+}
+
+func NewForkHandler(service *service.ForkService) *ForkHandler {
+	return &ForkHandler{service: service}
+}
+
+type duplicateStoryRequest struct {
+	DisplayName       string      `json:"display_name"`
+	Style             string      `json:"style"`
+	RegenerateShotIDs []uuid.UUID `json:"regenerate_shot_ids"`
+	RegenerateAll     bool        `json:"regenerate_all"`
+}
+
+func (h *ForkHandler) Duplicate(c *gin.Context) {
+	storyID, err := parseUUIDParam(c, "storyID")
+	if err != nil {
+		respondInvalidField(c, "story_id")
+		return
+	}
+	var req duplicateStoryRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindingError(c, err)
+			return
+		}
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	result, err := h.service.Duplicate(c.Request.Context(), userID, storyID, service.DuplicateStoryParams{
+		DisplayName:     req.DisplayName,
+		Style:           req.Style,
+		RegenerateShots: req.RegenerateShotIDs,
+		RegenerateAll:   req.RegenerateAll,
+	})
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, storyWithOperationsResponse{
+		Story:      buildStoryDetail(result.Story, result.Shots),
+		Operations: buildOperationRefs(result.Operations),
+	})
+}
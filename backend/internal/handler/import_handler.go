@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/middleware"
+	"story2video-backend/internal/service"
+)
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(service *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+type importStoryRequest struct {
+	service.StoryManifest
+	RegenerateMissingAssets bool `json:"regenerate_missing_assets"`
+}
+
+func (h *ImportHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxManifestBytes())
+	var req importStoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			middleware.AbortWithError(c, service.ErrCodePayloadTooLarge, "")
+			return
+		}
+		respondBindingError(c, err)
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), userID, &req.StoryManifest, service.ImportOptions{
+		RegenerateMissingAssets: req.RegenerateMissingAssets,
+	})
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, storyWithOperationsResponse{
+		Story:      buildStoryDetail(result.Story, result.Shots),
+		Operations: buildOperationRefs(result.Operations),
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/service"
+)
+
+type ModerationHandler struct {
+	service *service.ModerationService
+}
+
+func NewModerationHandler(service *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+type reviewModerationRequest struct {
+	Decision string `json:"decision" binding:"required"`
+	Note     string `json:"note"`
+}
+
+func (h *ModerationHandler) List(c *gin.Context) {
+	opts := service.ModerationListOptions{
+		Status: c.DefaultQuery("status", global.ModerationPending),
+		Target: c.Query("target"),
+		Limit:  20,
+	}
+	if opts.Status == "all" {
+		opts.Status = ""
+	}
+	if raw := c.Query("page_size"); raw != "" {
+		ps, err := strconv.Atoi(raw)
+		if err != nil || ps <= 0 {
+			respondInvalidField(c, "page_size")
+			return
+		}
+		opts.Limit = ps
+	}
+	if raw := c.Query("page_token"); raw != "" {
+		off, err := strconv.Atoi(raw)
+		if err != nil || off < 0 {
+			respondInvalidField(c, "page_token")
+			return
+		}
+		opts.Offset = off
+	}
+
+	records, total, err := h.service.List(c.Request.Context(), opts)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	nextToken := ""
+	if next := opts.Offset + len(records); int64(next) < total {
+		nextToken = strconv.Itoa(next)
+	}
+	c.JSON(http.StatusOK, moderationListResponse{
+		Items:         records,
+		Total:         total,
+		NextPageToken: nextToken,
+	})
+}
+
+func (h *ModerationHandler) Get(c *gin.Context) {
+	recordID, err := parseUUIDParam(c, "recordID")
+	if err != nil {
+		respondInvalidField(c, "record_id")
+		return
+	}
+	record, err := h.service.Get(c.Request.Context(), recordID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+func (h *ModerationHandler) Review(c *gin.Context) {
+	recordID, err := parseUUIDParam(c, "recordID")
+	if err != nil {
+		respondInvalidField(c, "record_id")
+		return
+	}
+	var req reviewModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	reviewerID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	result, err := h.service.Review(c.Request.Context(), reviewerID, recordID, service.ModerationReviewParams{
+		Decision: req.Decision,
+		Note:     req.Note,
+	})
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	resp := moderationReviewResponse{Record: result.Record}
+	if result.Operation != nil {
+		resp.OperationName = "operations/" + result.Operation.ID.String()
+	}
+	c.JSON(http.StatusOK, resp)
+}
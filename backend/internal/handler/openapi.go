@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+
+	"story2video-backend/internal/model"
+	"story2video-backend/internal/service"
+	"story2video-backend/pkg/openapi"
+)
+
+const (
+	tagStories    = "stories"
+	tagShots      = "shots"
+	tagOperations = "operations"
+	tagBatches    = "batches"
+	tagModeration = "moderation"
+	tagMeta       = "meta"
+)
+
+var (
+	exportBinary   = openapi.Binary{ContentTypes: []string{"application/pdf", "application/zip"}}
+	subtitleBinary = openapi.Binary{ContentTypes: []string{"application/x-subrip", "text/vtt"}}
+)
+
+var (
+	GetOpenAPIDocumentOp = openapi.Operation{
+		ID:        "GetOpenAPIDocument",
+		Tags:      []string{tagMeta},
+		Public:    true,
+		Responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	}
+
+	ListStoriesOp = openapi.Operation{
+		ID:        "ListStories",
+		Tags:      []string{tagStories},
+		Query:     storyListQuery{},
+		Responses: map[int]interface{}{http.StatusOK: storyListResponse{}},
+	}
+	CreateStoryOp = openapi.Operation{
+		ID:        "CreateStory",
+		Tags:      []string{tagStories},
+		Body:      createStoryRequest{},
+		Responses: map[int]interface{}{http.StatusAccepted: service.CreateHomeResult{}},
+	}
+	BatchCreateStoriesOp = openapi.Operation{
+		ID:        "BatchCreateStories",
+		Tags:      []string{tagStories},
+		Body:      batchCreateStoryRequest{},
+		Responses: map[int]interface{}{http.StatusAccepted: batchCreateResponse{}},
+	}
+	ImportStoryOp = openapi.Operation{
+		ID:        "ImportStory",
+		Tags:      []string{tagStories},
+		Body:      importStoryRequest{},
+		Responses: map[int]interface{}{http.StatusCreated: storyWithOperationsResponse{}},
+	}
+	UploadStoriesOp = openapi.Operation{
+		ID:   "UploadStories",
+		Tags: []string{tagStories},
+		Form: uploadStoryForm{},
+		Responses: map[int]interface{}{
+			http.StatusOK:       uploadPreviewResponse{},
+			http.StatusAccepted: uploadResponse{},
+		},
+	}
+	EstimateStoryOp = openapi.Operation{
+		ID:        "EstimateStory",
+		Tags:      []string{tagStories},
+		Body:      estimateStoryRequest{},
+		Responses: map[int]interface{}{http.StatusOK: service.ScriptCheckResult{}},
+	}
+	GetStoryOp = openapi.Operation{
+		ID:        "GetStory",
+		Tags:      []string{tagStories},
+		Responses: map[int]interface{}{http.StatusOK: storyResponse{}},
+	}
+	DuplicateStoryOp = openapi.Operation{
+		ID:        "DuplicateStory",
+		Tags:      []string{tagStories},
+		Body:      duplicateStoryRequest{},
+		Responses: map[int]interface{}{http.StatusCreated: storyWithOperationsResponse{}},
+	}
+	GetStorySubtitlesOp = openapi.Operation{
+		ID:        "GetStorySubtitles",
+		Tags:      []string{tagStories},
+		Query:     subtitleQuery{},
+		Responses: map[int]interface{}{http.StatusOK: subtitleBinary},
+	}
+	ExportStoryOp = openapi.Operation{
+		ID:    "ExportStory",
+		Tags:  []string{tagStories},
+		Query: exportQuery{},
+		Responses: map[int]interface{}{
+			http.StatusOK:       exportBinary,
+			http.StatusAccepted: exportOperationResponse{},
+		},
+	}
+	CompileStoryOp = openapi.Operation{
+		ID:        "CompileStory",
+		Tags:      []string{tagStories},
+		Responses: map[int]interface{}{http.StatusAccepted: acceptedOperationResponse{}},
+	}
+
+	ListShotsOp = openapi.Operation{
+		ID:        "ListShots",
+		Tags:      []string{tagShots},
+		Query:     pageQuery{},
+		Responses: map[int]interface{}{http.StatusOK: shotListResponse{}},
+	}
+	GetShotOp = openapi.Operation{
+		ID:        "GetShot",
+		Tags:      []string{tagShots},
+		Responses: map[int]interface{}{http.StatusOK: model.Shot{}},
+	}
+	UpdateShotOp = openapi.Operation{
+		ID:        "UpdateShot",
+		Tags:      []string{tagShots},
+		Body:      updateShotBody{},
+		Responses: map[int]interface{}{http.StatusOK: model.Shot{}},
+	}
+	RegenerateShotOp = openapi.Operation{
+		ID:        "RegenerateShot",
+		Tags:      []string{tagShots},
+		Body:      regenerateShotRequest{},
+		Responses: map[int]interface{}{http.StatusAccepted: acceptedOperationResponse{}},
+	}
+
+	ListOperationsOp = openapi.Operation{
+		ID:        "ListOperations",
+		Tags:      []string{tagOperations},
+		Query:     operationListQuery{},
+		Responses: map[int]interface{}{http.StatusOK: operationListResponse{}},
+	}
+	ListStoryOperationsOp = openapi.Operation{
+		ID:        "ListStoryOperations",
+		Tags:      []string{tagOperations},
+		Query:     storyOperationListQuery{},
+		Responses: map[int]interface{}{http.StatusOK: operationListResponse{}},
+	}
+	GetOperationOp = openapi.Operation{
+		ID:        "GetOperation",
+		Tags:      []string{tagOperations},
+		Responses: map[int]interface{}{http.StatusOK: operationResponse{}},
+	}
+	CancelOperationOp = openapi.Operation{
+		ID:        "CancelOperation",
+		Tags:      []string{tagOperations},
+		Responses: map[int]interface{}{http.StatusOK: operationResponse{}},
+	}
+	RescheduleOperationOp = openapi.Operation{
+		ID:        "RescheduleOperation",
+		Tags:      []string{tagOperations},
+		Body:      rescheduleOperationRequest{},
+		Responses: map[int]interface{}{http.StatusOK: operationResponse{}},
+	}
+	RetryOperationOp = openapi.Operation{
+		ID:        "RetryOperation",
+		Tags:      []string{tagOperations},
+		Responses: map[int]interface{}{http.StatusOK: operationResponse{}},
+	}
+	DownloadOperationOp = openapi.Operation{
+		ID:        "DownloadOperation",
+		Tags:      []string{tagOperations},
+		Responses: map[int]interface{}{http.StatusOK: exportBinary},
+	}
+
+	GetBatchOp = openapi.Operation{
+		ID:        "GetBatch",
+		Tags:      []string{tagBatches},
+		Responses: map[int]interface{}{http.StatusOK: service.BatchView{}},
+	}
+	CancelBatchOp = openapi.Operation{
+		ID:        "CancelBatch",
+		Tags:      []string{tagBatches},
+		Responses: map[int]interface{}{http.StatusOK: service.BatchView{}},
+	}
+	RetryBatchOp = openapi.Operation{
+		ID:        "RetryBatch",
+		Tags:      []string{tagBatches},
+		Responses: map[int]interface{}{http.StatusOK: service.BatchView{}},
+	}
+
+	ListModerationRecordsOp = openapi.Operation{
+		ID:        "ListModerationRecords",
+		Tags:      []string{tagModeration},
+		Query:     moderationListQuery{},
+		Responses: map[int]interface{}{http.StatusOK: moderationListResponse{}},
+	}
+	GetModerationRecordOp = openapi.Operation{
+		ID:        "GetModerationRecord",
+		Tags:      []string{tagModeration},
+		Responses: map[int]interface{}{http.StatusOK: model.ModerationRecord{}},
+	}
+	ReviewModerationRecordOp = openapi.Operation{
+		ID:        "ReviewModerationRecord",
+		Tags:      []string{tagModeration},
+		Body:      reviewModerationRequest{},
+		Responses: map[int]interface{}{http.StatusOK: moderationReviewResponse{}},
+	}
+)
@@ -1,22 +1,97 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
-	"story2video-backend/internal/data"
+	"story2video-backend/internal/middleware"
 	"story2video-backend/internal/model"
+	"story2video-backend/internal/service"
 )
 
 type OperationHandler struct {
-	data *data.Data
+	service *service.OperationService
 }
 
-func NewOperationHandler(d *data.Data) *OperationHandler {
-	return &OperationHandler{data: d}
+func NewOperationHandler(service *service.OperationService) *OperationHandler {
+	return &OperationHandler{service: service}
+}
+
+func (h *OperationHandler) List(c *gin.Context) {
+	opts, ok := parseOperationListQuery(c)
+	if !ok {
+		return
+	}
+	if raw := c.Query("story_id"); raw != "" {
+		storyID, err := uuid.Parse(raw)
+		if err != nil {
+			respondInvalidField(c, "story_id")
+			return
+		}
+		opts.StoryID = &storyID
+	}
+	h.respondList(c, opts)
+}
+
+func (h *OperationHandler) ListForStory(c *gin.Context) {
+	storyID, err := parseUUIDParam(c, "storyID")
+	if err != nil {
+		respondInvalidField(c, "story_id")
+		return
+	}
+	opts, ok := parseOperationListQuery(c)
+	if !ok {
+		return
+	}
+	opts.StoryID = &storyID
+	h.respondList(c, opts)
+}
+
+func (h *OperationHandler) respondList(c *gin.Context, opts service.OperationListOptions) {
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	opts.Status = operationStatusOptions(c)
+	ops, nextToken, err := h.service.List(c.Request.Context(), userID, opts)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, operationListResponse{
+		Operations:    ops,
+		NextPageToken: nextToken,
+	})
+}
+
+func parseOperationListQuery(c *gin.Context) (service.OperationListOptions, bool) {
+	opts := service.OperationListOptions{
+		Types:     splitQueryList(c.Query("type")),
+		Statuses:  splitQueryList(c.Query("status")),
+		PageToken: c.Query("page_token"),
+	}
+	if raw, ok := c.GetQuery("page_size"); ok {
+		ps, err := strconv.Atoi(raw)
+		if err != nil || ps <= 0 {
+			respondInvalidField(c, "page_size")
+			return opts, false
+		}
+		opts.PageSize = ps
+	}
+	start, end, err := parseTimeRangeQuery(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return opts, false
+	}
+	opts.StartTime, opts.EndTime = start, end
+	return opts, true
 }
 
 func (h *OperationHandler) Get(c *gin.Context) {
@@ -24,22 +99,125 @@ func (h *OperationHandler) Get(c *gin.Context) {
 	opIDParam = strings.TrimPrefix(opIDParam, "operations/")
 	opID, err := uuid.Parse(opIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid operation_id"})
+		respondInvalidField(c, "operation_id")
 		return
 	}
 
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
+		return
+	}
+
+	op, err := h.service.Get(c.Request.Context(), userID, opID)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
 
-	var op model.Operation
-	if err := h.data.DB.WithContext(c.Request.Context()).
-		First(&op, "id = ? AND user_id = ?", opID, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "operation not found"})
+	c.JSON(http.StatusOK, operationResponse{
+		Operation: op,
+		Error:     service.NewOperationStatus(op, operationStatusOptions(c)),
+	})
+}
+
+func (h *OperationHandler) Cancel(c *gin.Context) {
+	opID, err := parseUUIDParam(c, "operationID")
+	if err != nil {
+		respondInvalidField(c, "operation_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, op)
+	op, err := h.service.Cancel(c.Request.Context(), userID, opID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, operationResponse{
+		Operation: op,
+		Error:     service.NewOperationStatus(op, operationStatusOptions(c)),
+	})
+}
+
+func (h *OperationHandler) Retry(c *gin.Context) {
+	opID, err := parseUUIDParam(c, "operationID")
+	if err != nil {
+		respondInvalidField(c, "operation_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	op, err := h.service.Retry(c.Request.Context(), userID, opID)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, operationResponse{
+		Operation: op,
+		Error:     service.NewOperationStatus(op, operationStatusOptions(c)),
+	})
+}
+
+type rescheduleOperationRequest struct {
+	ScheduledAt *time.Time `json:"scheduled_at" binding:"required"`
+}
+
+func (h *OperationHandler) Reschedule(c *gin.Context) {
+	opID, err := parseUUIDParam(c, "operationID")
+	if err != nil {
+		respondInvalidField(c, "operation_id")
+		return
+	}
+	var req rescheduleOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+	if key, params, ok := h.service.CheckScheduleTime(*req.ScheduledAt); !ok {
+		respondFieldViolation(c, "scheduled_at", key, params)
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	op, err := h.service.Reschedule(c.Request.Context(), userID, opID, *req.ScheduledAt)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, operationResponse{
+		Operation: op,
+		Error:     service.NewOperationStatus(op, operationStatusOptions(c)),
+	})
+}
+
+func operationStatusOptions(c *gin.Context) service.StatusOptions {
+	return service.StatusOptions{
+		IncludeDebug: middleware.IsAdmin(c),
+		Locale:       requestLocale(c),
+	}
+}
+
+func buildOperationRefs(ops []*model.Operation) []operationRef {
+	refs := make([]operationRef, 0, len(ops))
+	for _, op := range ops {
+		refs = append(refs, operationRef{
+			OperationName: fmt.Sprintf("operations/%s", op.ID),
+			ShotID:        op.ShotID,
+			State:         op.Status,
+		})
+	}
+	return refs
 }
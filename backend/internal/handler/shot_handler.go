@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,6 +13,8 @@ import (
 	"story2video-backend/internal/service"
 )
 
+const defaultShotPageSize = 20
+
 type ShotHandler struct {
 	service *service.ShotService
 }
@@ -21,20 +26,48 @@ func NewShotHandler(service *service.ShotService) *ShotHandler {
 func (h *ShotHandler) List(c *gin.Context) {
 	storyID, err := parseUUIDParam(c, "storyID")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid story_id"})
+		respondInvalidField(c, "story_id")
 		return
 	}
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
+		return
+	}
+	pageSizeStr, hasPageSize := c.GetQuery("page_size")
+	pageToken, hasPageToken := c.GetQuery("page_token")
+	if !hasPageSize && !hasPageToken {
+		shots, err := h.service.List(c.Request.Context(), userID, storyID)
+		if err != nil {
+			respondServiceError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, shotListResponse{Shots: shots})
+		return
+	}
+
+	pageSize := defaultShotPageSize
+	if hasPageSize {
+		ps, err := strconv.Atoi(pageSizeStr)
+		if err != nil || ps <= 0 {
+			respondInvalidField(c, "page_size")
+			return
+		}
+		pageSize = ps
+	}
+	if hasPageToken && pageToken == "" {
+		respondInvalidField(c, "page_token")
 		return
 	}
-	shots, err := h.service.List(c.Request.Context(), userID, storyID)
+	shots, nextToken, err := h.service.ListPage(c.Request.Context(), userID, storyID, pageSize, pageToken)
 	if err != nil {
 		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"shots": shots})
+	c.JSON(http.StatusOK, shotListResponse{
+		Shots:         shots,
+		NextPageToken: nextToken,
+	})
 }
 
 func (h *ShotHandler) Get(c *gin.Context) {
@@ -44,7 +77,7 @@ func (h *ShotHandler) Get(c *gin.Context) {
 	}
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	shot, err := h.service.Get(c.Request.Context(), userID, storyID, shotID)
@@ -76,13 +109,13 @@ func (h *ShotHandler) Update(c *gin.Context) {
 	}
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
 	var req updateShotBody
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindingError(c, err)
 		return
 	}
 
@@ -134,18 +167,18 @@ func (h *ShotHandler) Regenerate(c *gin.Context) {
 		return
 	}
 	var req regenerateShotRequest
-	if err := c.ShouldBindJSON(&req); err != nil && err.Error() != "EOF" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondBindingError(c, err)
 		return
 	}
 	if req.AssetType != "" && req.AssetType != "ASSET_IMAGE" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported asset_type"})
+		respondFieldViolation(c, "asset_type", service.MsgFieldOneOf, service.MessageParams{"values": "ASSET_IMAGE"})
 		return
 	}
 
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
@@ -154,18 +187,18 @@ func (h *ShotHandler) Regenerate(c *gin.Context) {
 		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusAccepted, gin.H{"operation_name": fmt.Sprintf("operations/%s", op.ID), "state": op.Status})
+	c.JSON(http.StatusAccepted, acceptedOperationResponse{OperationName: fmt.Sprintf("operations/%s", op.ID), State: op.Status})
 }
 
 func (h *ShotHandler) Render(c *gin.Context) {
 	storyID, err := parseUUIDParam(c, "storyID")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid story_id"})
+		respondInvalidField(c, "story_id")
 		return
 	}
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
@@ -174,18 +207,18 @@ func (h *ShotHandler) Render(c *gin.Context) {
 		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusAccepted, gin.H{"operation_name": fmt.Sprintf("operations/%s", op.ID), "state": op.Status})
+	c.JSON(http.StatusAccepted, acceptedOperationResponse{OperationName: fmt.Sprintf("operations/%s", op.ID), State: op.Status})
 }
 
 func (h *ShotHandler) parseStoryShotIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
 	storyID, err := parseUUIDParam(c, "storyID")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid story_id"})
+		respondInvalidField(c, "story_id")
 		return uuid.Nil, uuid.Nil, false
 	}
 	shotID, err := parseUUIDParam(c, "shotID")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shot_id"})
+		respondInvalidField(c, "shot_id")
 		return uuid.Nil, uuid.Nil, false
 	}
 	return storyID, shotID, true
@@ -4,19 +4,18 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"story2video-backend/internal/global"
+	"story2video-backend/internal/middleware"
 	"story2video-backend/internal/model"
 	"story2video-backend/internal/service"
 )
 
-var validate = validator.New()
-
 type StoryHandler struct {
 	home  *service.HomeService
 	story *service.StoryService
@@ -32,29 +31,42 @@ func NewStoryHandler(home *service.HomeService, story *service.StoryService) *St
 }
 
 type createStoryRequest struct {
-	DisplayName   string `json:"display_name" binding:"required"`
-	ScriptContent string `json:"script_content" binding:"required"`
-	Style         string `json:"style" binding:"required"`
+	DisplayName   string     `json:"display_name" binding:"required"`
+	ScriptContent string     `json:"script_content" binding:"required"`
+	Style         string     `json:"style" binding:"required"`
+	ScheduledAt   *time.Time `json:"scheduled_at"`
+}
+
+type estimateStoryRequest struct {
+	ScriptContent string `json:"script_content"`
+	Style         string `json:"style"`
 }
 
 type batchCreateStoryRequest struct {
-	Items []createStoryRequest `json:"items" binding:"required"`
+	Items       []createStoryRequest `json:"items" binding:"required"`
+	ScheduledAt *time.Time           `json:"scheduled_at"`
 }
 
 func (h *StoryHandler) Create(c *gin.Context) {
 	var req createStoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindingError(c, err)
 		return
 	}
-	if err := validate.Struct(req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := validateStruct(req); err != nil {
+		respondBindingError(c, err)
 		return
 	}
+	if req.ScheduledAt != nil {
+		if key, params, ok := h.home.CheckScheduleTime(*req.ScheduledAt); !ok {
+			respondFieldViolation(c, "scheduled_at", key, params)
+			return
+		}
+	}
 
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
@@ -65,6 +77,7 @@ func (h *StoryHandler) Create(c *gin.Context) {
 			DisplayName:   req.DisplayName,
 			ScriptContent: req.ScriptContent,
 			Style:         req.Style,
+			ScheduledAt:   req.ScheduledAt,
 		},
 	)
 	if err != nil {
@@ -78,156 +91,145 @@ func (h *StoryHandler) Create(c *gin.Context) {
 func (h *StoryHandler) BatchCreate(c *gin.Context) {
 	var req batchCreateStoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindingError(c, err)
 		return
 	}
 	if len(req.Items) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "items 不能为空"})
+		respondFieldViolation(c, "items", service.MsgFieldRequired, nil)
 		return
 	}
+	var violations []middleware.FieldViolation
+	if req.ScheduledAt != nil {
+		if key, params, ok := h.home.CheckScheduleTime(*req.ScheduledAt); !ok {
+			violations = append(violations, fieldViolation(c, "scheduled_at", key, params))
+		}
+	}
 	for idx, item := range req.Items {
-		if err := validate.Struct(item); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("items[%d] 参数不合法: %v", idx, err),
-			})
-			return
+		if err := validateStruct(item); err != nil {
+			violations = append(violations, bindingViolations(c, err, fmt.Sprintf("items[%d].", idx))...)
+		}
+		if item.ScheduledAt != nil {
+			if key, params, ok := h.home.CheckScheduleTime(*item.ScheduledAt); !ok {
+				violations = append(violations, fieldViolation(c, fmt.Sprintf("items[%d].scheduled_at", idx), key, params))
+			}
 		}
 	}
+	if len(violations) > 0 {
+		middleware.AbortWithError(c, service.ErrCodeInvalidRequest, "", violations...)
+		return
+	}
 
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
 	params := make([]service.CreateHomeParams, len(req.Items))
 	for idx, item := range req.Items {
+		scheduledAt := req.ScheduledAt
+		if item.ScheduledAt != nil {
+			scheduledAt = item.ScheduledAt
+		}
 		params[idx] = service.CreateHomeParams{
 			DisplayName:   item.DisplayName,
 			ScriptContent: item.ScriptContent,
 			Style:         item.Style,
+			ScheduledAt:   scheduledAt,
 		}
 	}
 
-	results, err := h.home.CreateBatch(c.Request.Context(), userID, params, batchCreateMaxConcurrency)
+	batch, results, err := h.home.CreateBatch(c.Request.Context(), userID, params, batchCreateMaxConcurrency)
 	if err != nil {
 		respondServiceError(c, err)
 		return
 	}
 
-	respItems := make([]gin.H, len(results))
-	for idx, item := range results {
-		entry := gin.H{
-			"index":   idx,
-			"success": item.Err == nil,
-		}
-		if item.Err != nil {
-			if svcErr, ok := service.AsServiceError(item.Err); ok {
-				entry["error_code"] = svcErr.Code
-				entry["error_message"] = svcErr.Message
-			} else {
-				entry["error_message"] = item.Err.Error()
-			}
-		} else if item.Result != nil {
-			entry["operation_name"] = item.Result.OperationName
-			entry["state"] = item.Result.State
-			entry["create_time"] = item.Result.CreateTime
-		}
-		respItems[idx] = entry
+	c.JSON(http.StatusAccepted, batchCreateResponse{
+		BatchName: batchName(batch),
+		Items:     buildBatchCreateItems(c, results),
+	})
+}
+
+func (h *StoryHandler) Estimate(c *gin.Context) {
+	var req estimateStoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
 	}
+	if _, err := userIDFromContext(c); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	result, err := h.home.Estimate(req.Style, req.ScriptContent)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	result.Localize(requestLocale(c))
 
-	c.JSON(http.StatusAccepted, gin.H{"items": respItems})
+	c.JSON(http.StatusOK, result)
 }
 
 func (h *StoryHandler) List(c *gin.Context) {
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
 	keyword := c.Query("keyword")
 	titleParam := c.Query("title")
+	searchQuery := strings.TrimSpace(c.Query("q"))
 	pageSizeStr := c.Query("page_size")
 	pageTokenStr := c.Query("page_token")
-	startTimeStr := c.Query("start_time")
-	endTimeStr := c.Query("end_time")
 	rawQ := c.Request.URL.Query()
 	pageSize := 0
-	offset := 0
-	var startPtr *time.Time
-	var endPtr *time.Time
 
 	if _, ok := rawQ["page_size"]; ok {
 		if pageSizeStr == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_size"})
+			respondInvalidField(c, "page_size")
 			return
 		}
 		ps, err := strconv.Atoi(pageSizeStr)
 		if err != nil || ps < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_size"})
+			respondInvalidField(c, "page_size")
 			return
 		}
 		pageSize = ps
 	}
-	if _, ok := rawQ["page_token"]; ok {
-		if pageTokenStr == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_token"})
-			return
-		}
-		off, err := strconv.Atoi(pageTokenStr)
-		if err != nil || off < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page_token"})
-			return
-		}
-		offset = off
+	if _, ok := rawQ["page_token"]; ok && pageTokenStr == "" {
+		respondInvalidField(c, "page_token")
+		return
 	}
 
-	parseFlexibleTime := func(s string) (*time.Time, bool, error) {
-		if s == "" {
-			return nil, false, nil
-		}
-		if t, err := time.Parse(time.RFC3339, s); err == nil {
-			return &t, false, nil
-		}
-		if t, err := time.Parse("2006-01-02", s); err == nil {
-			return &t, true, nil
-		}
-		return nil, false, err
+	startPtr, endPtr, err := parseTimeRangeQuery(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
 	}
-	endIsDate := false
-	if _, ok := rawQ["start_time"]; ok {
-		if startTimeStr == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time"})
-			return
-		}
-		t, _, err := parseFlexibleTime(startTimeStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_time"})
-			return
-		}
-		startPtr = t
+
+	statuses, err := parseStoryStatusFilter(c.Query("status"), c.Query("compile_state"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
 	}
-	if _, ok := rawQ["end_time"]; ok {
-		if endTimeStr == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time"})
-			return
-		}
-		t, isDate, err := parseFlexibleTime(endTimeStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_time"})
+	styles := splitQueryList(c.Query("style"))
+	for _, style := range styles {
+		if !service.IsSupportedStyle(style) {
+			respondInvalidField(c, "style")
 			return
 		}
-		endPtr = t
-		endIsDate = isDate
 	}
-	if endPtr != nil && endIsDate {
-		endOfDay := endPtr.Add(24*time.Hour - time.Nanosecond)
-		endPtr = &endOfDay
+	sortBy, sortDesc, err := service.NormalizeStorySort(c.Query("sort_by"), c.Query("order"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
 	}
 
 	needFilter := false
-	for _, k := range []string{"keyword", "title", "page_size", "page_token", "start_time", "end_time"} {
+	for _, k := range []string{"keyword", "title", "q", "status", "compile_state", "style", "sort_by", "order", "page_size", "page_token", "start_time", "end_time"} {
 		if _, ok := rawQ[k]; ok {
 			needFilter = true
 			break
@@ -236,6 +238,11 @@ func (h *StoryHandler) List(c *gin.Context) {
 
 	opts := service.StoryListOptions{
 		Keyword:   keyword,
+		Query:     searchQuery,
+		Statuses:  statuses,
+		Styles:    styles,
+		SortBy:    sortBy,
+		SortDesc:  sortDesc,
 		StartTime: startPtr,
 		EndTime:   endPtr,
 	}
@@ -243,6 +250,12 @@ func (h *StoryHandler) List(c *gin.Context) {
 		opts.ExactTitle = titleParam
 	}
 
+	legacyToken, err := h.story.ApplyPageToken(&opts, pageTokenStr)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
 	paginated := false
 	if needFilter {
 		paginated = true
@@ -250,7 +263,9 @@ func (h *StoryHandler) List(c *gin.Context) {
 			pageSize = 10
 		}
 		opts.Limit = pageSize
-		opts.Offset = offset
+		if !legacyToken {
+			opts.Limit = pageSize + 1
+		}
 	}
 
 	stories, total, err := h.story.ListStories(c.Request.Context(), userID, opts)
@@ -258,17 +273,28 @@ func (h *StoryHandler) List(c *gin.Context) {
 		respondServiceError(c, err)
 		return
 	}
-	items := buildStoryListItems(stories)
 	nextToken := ""
 	if paginated {
-		nextOffset := offset + len(stories)
-		if int64(nextOffset) < total {
-			nextToken = strconv.Itoa(nextOffset)
+		switch {
+		case legacyToken:
+			if next := opts.Offset + len(stories); int64(next) < total {
+				nextToken = strconv.Itoa(next)
+			}
+		case len(stories) > pageSize:
+			stories = stories[:pageSize]
+			nextToken = h.story.NextPageToken(stories, opts)
+		}
+	}
+	items := buildStoryListItems(stories)
+	if searchQuery != "" {
+		highlights := h.story.SearchHighlights(c.Request.Context(), stories, searchQuery)
+		for idx, st := range stories {
+			items[idx].Highlights = highlights[st.ID]
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"items":           items,
-		"next_page_token": nextToken,
+	c.JSON(http.StatusOK, storyListResponse{
+		Items:         items,
+		NextPageToken: nextToken,
 	})
 }
 
@@ -276,12 +302,12 @@ func (h *StoryHandler) Get(c *gin.Context) {
 	storyIDStr := c.Param("storyID")
 	storyUUID, err := uuid.Parse(storyIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid story_id"})
+		respondInvalidField(c, "story_id")
 		return
 	}
 	userID, err := userIDFromContext(c)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	story, shots, err := h.story.Get(c.Request.Context(), userID, storyUUID)
@@ -289,67 +315,231 @@ func (h *StoryHandler) Get(c *gin.Context) {
 		respondServiceError(c, err)
 		return
 	}
-	shotItems := make([]gin.H, 0, len(shots))
+	lineage, err := h.story.Lineage(c.Request.Context(), userID, story)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	resp := buildStoryDetail(story, shots)
+	resp.Lineage = lineage
+	c.JSON(http.StatusOK, storyResponse{Story: resp})
+}
+
+func (h *StoryHandler) Subtitles(c *gin.Context) {
+	storyID, err := parseUUIDParam(c, "storyID")
+	if err != nil {
+		respondInvalidField(c, "story_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	file, err := h.story.Subtitles(c.Request.Context(), userID, storyID, c.Query("format"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s.%s", storyID, file.Format)))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *StoryHandler) Asset(c *gin.Context) {
+	storyID, err := parseUUIDParam(c, "storyID")
+	if err != nil {
+		respondInvalidField(c, "story_id")
+		return
+	}
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	local, err := h.story.AssetFile(c.Request.Context(), userID, middleware.IsAdmin(c), storyID, c.Param("name"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.File(local)
+}
+
+func parseFlexibleTime(s string) (*time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, false, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil, false, err
+	}
+	return &t, true, nil
+}
+
+func parseTimeRangeQuery(c *gin.Context) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+	if raw, ok := c.GetQuery("start_time"); ok {
+		t, _, err := parseFlexibleTime(raw)
+		if raw == "" || err != nil {
+			return nil, nil, invalidField("start_time")
+		}
+		start = t
+	}
+	if raw, ok := c.GetQuery("end_time"); ok {
+		t, isDate, err := parseFlexibleTime(raw)
+		if raw == "" || err != nil {
+			return nil, nil, invalidField("end_time")
+		}
+		if isDate {
+			endOfDay := t.Add(24*time.Hour - time.Nanosecond)
+			t = &endOfDay
+		}
+		end = t
+	}
+	return start, end, nil
+}
+
+var (
+	allowedListStatuses = map[string]struct{}{
+		global.StoryDraft: {},
+		global.StoryGen:   {},
+		global.StoryReady: {},
+		global.StoryFail:  {},
+		global.StoryFlag:  {},
+	}
+)
+
+func splitQueryList(raw string) []string {
+	var values []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func parseStoryStatusFilter(statusParam, compileStateParam string) ([]string, error) {
+	var statuses []string
+	for _, status := range splitQueryList(statusParam) {
+		if _, ok := allowedListStatuses[status]; !ok {
+			return nil, invalidField("status")
+		}
+		statuses = append(statuses, status)
+	}
+	states := splitQueryList(compileStateParam)
+	if len(states) == 0 {
+		return statuses, nil
+	}
+	fromStates := make(map[string]struct{})
+	for _, state := range states {
+		matched := false
+		for status := range allowedListStatuses {
+			if service.StoryCompileState(status) == strings.ToUpper(state) {
+				fromStates[status] = struct{}{}
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, invalidField("compile_state")
+		}
+	}
+	if len(statuses) == 0 {
+		for status := range fromStates {
+			statuses = append(statuses, status)
+		}
+		return statuses, nil
+	}
+	filtered := statuses[:0]
+	for _, status := range statuses {
+		if _, ok := fromStates[status]; ok {
+			filtered = append(filtered, status)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, &fieldError{field: "compile_state", key: service.MsgFieldConflict, params: service.MessageParams{"other": "status"}}
+	}
+	return filtered, nil
+}
+
+func batchName(batch *model.Batch) string {
+	if batch == nil {
+		return ""
+	}
+	return fmt.Sprintf("batches/%s", batch.ID)
+}
+
+func buildBatchCreateItems(c *gin.Context, results []service.BatchCreateItemResult) []batchCreateItem {
+	respItems := make([]batchCreateItem, len(results))
+	for idx, item := range results {
+		entry := batchCreateItem{
+			Index:   idx,
+			Success: item.Err == nil,
+		}
+		if item.Err != nil {
+			if svcErr, ok := service.AsServiceError(item.Err); ok {
+				entry.ErrorCode = svcErr.Code
+				entry.ErrorMessage = svcErr.LocalizedMessage(requestLocale(c))
+			} else {
+				entry.ErrorMessage = item.Err.Error()
+			}
+		} else if item.Result != nil {
+			createTime := item.Result.CreateTime
+			entry.OperationName = item.Result.OperationName
+			entry.State = item.Result.State
+			entry.CreateTime = &createTime
+		}
+		respItems[idx] = entry
+	}
+	return respItems
+}
+
+func buildStoryDetail(story *model.Story, shots []model.Shot) storyDetail {
+	shotItems := make([]storyShot, 0, len(shots))
 	for idx, sh := range shots {
-		shotItems = append(shotItems, gin.H{
-			"shot_id":     sh.ID,
-			"index":       idx,
-			"title":       sh.Title,
-			"description": sh.Description,
-			"details":     sh.Details,
-			"narration":   sh.Narration,
-			"type":        sh.Type,
-			"transition":  sh.Transition,
-			"voice":       sh.Voice,
-			"image_url":   sh.ImageURL,
-			"bgm":         sh.BGM,
-			"status":      sh.Status,
+		shotItems = append(shotItems, storyShot{
+			ShotID:      sh.ID,
+			Index:       idx,
+			Title:       sh.Title,
+			Description: sh.Description,
+			Details:     sh.Details,
+			Narration:   sh.Narration,
+			Type:        sh.Type,
+			Transition:  sh.Transition,
+			Voice:       sh.Voice,
+			ImageURL:    sh.ImageURL,
+			BGM:         sh.BGM,
+			Status:      sh.Status,
 		})
 	}
-	compileState := mapStoryStatusToGenState(story.Status)
 	cover := story.CoverURL
 	if cover == "" && len(shots) > 0 {
 		cover = shots[0].ImageURL
 	}
-	resp := gin.H{
-		"story_id":       story.ID,
-		"display_name":   story.Title,
-		"script_content": story.Content,
-		"style":          story.Style,
-		"video_url":      story.VideoURL,
-		"compile_state":  compileState,
-		"cover_url":      cover,
-		"create_time":    story.CreatedAt,
-		"shots":          shotItems,
-	}
-	c.JSON(http.StatusOK, gin.H{"story": resp})
-}
-
-func mapStoryStatusToGenState(status string) string {
-	switch status {
-	case global.StoryDraft:
-		return "STATE_PENDING"
-	case global.StoryGen:
-		return "STATE_RUNNING"
-	case global.StoryReady:
-		return "STATE_SUCCEEDED"
-	case global.StoryFail:
-		return "STATE_FAILED"
-	default:
-		return "STATE_UNSPECIFIED"
+	return storyDetail{
+		StoryID:       story.ID,
+		DisplayName:   story.Title,
+		ScriptContent: story.Content,
+		Style:         story.Style,
+		VideoURL:      story.VideoURL,
+		CompileState:  service.StoryCompileState(story.Status),
+		CoverURL:      cover,
+		CreateTime:    story.CreatedAt,
+		ParentStoryID: story.ParentStoryID,
+		Shots:         shotItems,
 	}
 }
 
-func buildStoryListItems(stories []model.Story) []gin.H {
-	items := make([]gin.H, 0, len(stories))
+func buildStoryListItems(stories []model.Story) []storyListItem {
+	items := make([]storyListItem, 0, len(stories))
 	for _, st := range stories {
-		items = append(items, gin.H{
-			"story_id":      st.ID,
-			"display_name":  st.Title,
-			"cover_url":     st.CoverURL,
-			"create_time":   st.CreatedAt,
-			"compile_state": mapStoryStatusToGenState(st.Status),
-			"video_url":     st.VideoURL,
+		items = append(items, storyListItem{
+			StoryID:      st.ID,
+			DisplayName:  st.Title,
+			CoverURL:     st.CoverURL,
+			CreateTime:   st.CreatedAt,
+			CompileState: service.StoryCompileState(st.Status),
+			VideoURL:     st.VideoURL,
 		})
 	}
 	return items
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/middleware"
+	"story2video-backend/internal/service"
+)
+
+const (
+	uploadMaxFileBytes  = 20 << 20
+	uploadMaxChapters   = 50
+	uploadPreviewLength = 120
+)
+
+func (h *StoryHandler) Upload(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, uploadMaxFileBytes+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondFieldViolation(c, "file", service.MsgFieldRequired, nil)
+		return
+	}
+	if fileHeader.Size > uploadMaxFileBytes {
+		middleware.AbortWithError(c, service.ErrCodePayloadTooLarge, "",
+			fieldViolation(c, "file", service.MsgFileTooLarge, service.MessageParams{"limit": uploadMaxFileBytes >> 20}))
+		return
+	}
+	chapterLimit := h.home.MaxChapterRunes()
+	maxChapterRunes := min(service.DefaultChapterMaxRunes, chapterLimit)
+	if raw := c.PostForm("max_chapter_chars"); raw != "" {
+		maxChapterRunes, err = strconv.Atoi(raw)
+		if err != nil || maxChapterRunes <= 0 {
+			respondInvalidField(c, "max_chapter_chars")
+			return
+		}
+		if maxChapterRunes > chapterLimit {
+			respondFieldViolation(c, "max_chapter_chars", service.MsgFieldMax, service.MessageParams{"limit": chapterLimit})
+			return
+		}
+	}
+	dryRun := false
+	if raw := c.PostForm("dry_run"); raw != "" {
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			respondInvalidField(c, "dry_run")
+			return
+		}
+	}
+	style := c.PostForm("style")
+	if !dryRun && style == "" {
+		respondFieldViolation(c, "style", service.MsgFieldRequired, nil)
+		return
+	}
+
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondInvalidField(c, "file")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondInvalidField(c, "file")
+		return
+	}
+
+	parsed, err := service.ParseScriptDocument(fileHeader.Filename, data, maxChapterRunes, uploadMaxFileBytes)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	baseName := strings.TrimSpace(c.PostForm("display_name"))
+	if baseName == "" {
+		baseName = parsed.Title
+	}
+
+	if len(parsed.Chapters) > uploadMaxChapters {
+		respondFieldViolation(c, "max_chapter_chars", service.MsgUploadTooManyChapters, service.MessageParams{
+			"count": len(parsed.Chapters),
+			"max":   uploadMaxChapters,
+		})
+		return
+	}
+
+	if dryRun {
+		chapters := make([]uploadChapter, len(parsed.Chapters))
+		for idx, ch := range parsed.Chapters {
+			chapters[idx] = uploadChapter{
+				Index:       idx,
+				Title:       ch.Title,
+				DisplayName: service.ChapterDisplayName(baseName, idx, ch, len(parsed.Chapters)),
+				CharCount:   utf8.RuneCountInString(ch.Content),
+				Preview:     truncateRunes(ch.Content, uploadPreviewLength),
+			}
+		}
+		c.JSON(http.StatusOK, uploadPreviewResponse{
+			Title:    parsed.Title,
+			Format:   parsed.Format,
+			Chapters: chapters,
+		})
+		return
+	}
+	params := make([]service.CreateHomeParams, len(parsed.Chapters))
+	for idx, ch := range parsed.Chapters {
+		params[idx] = service.CreateHomeParams{
+			DisplayName:   service.ChapterDisplayName(baseName, idx, ch, len(parsed.Chapters)),
+			ScriptContent: ch.Content,
+			Style:         style,
+		}
+	}
+	batch, results, err := h.home.CreateBatch(c.Request.Context(), userID, params, batchCreateMaxConcurrency)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	items := buildBatchCreateItems(c, results)
+	for idx := range items {
+		items[idx].DisplayName = params[idx].DisplayName
+	}
+	c.JSON(http.StatusAccepted, uploadResponse{
+		Title:     parsed.Title,
+		Format:    parsed.Format,
+		BatchName: batchName(batch),
+		Items:     items,
+	})
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"story2video-backend/internal/middleware"
+	"story2video-backend/internal/service"
+)
+
+var registerValidationOnce sync.Once
+
+func RegisterValidation() {
+	registerValidationOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(jsonFieldName)
+	})
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func validateStruct(obj interface{}) error {
+	return binding.Validator.ValidateStruct(obj)
+}
+
+func bindingViolations(c *gin.Context, err error, prefix string) []middleware.FieldViolation {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+		syntaxErr      *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &validationErrs):
+		violations := make([]middleware.FieldViolation, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			key, params := validationMessage(fe)
+			violations = append(violations, fieldViolation(c, prefix+validationFieldPath(fe), key, params))
+		}
+		return violations
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return []middleware.FieldViolation{
+			fieldViolation(c, prefix+field, service.MsgFieldType, service.MessageParams{"type": typeErr.Type.String()}),
+		}
+	case errors.As(err, &syntaxErr):
+		return []middleware.FieldViolation{fieldViolation(c, "body", service.MsgBodyMalformed, nil)}
+	case errors.Is(err, io.EOF):
+		return []middleware.FieldViolation{fieldViolation(c, "body", service.MsgBodyEmpty, nil)}
+	default:
+		return []middleware.FieldViolation{fieldViolation(c, "body", service.MsgBodyMalformed, nil)}
+	}
+}
+
+func validationFieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if idx := strings.Index(ns, "."); idx >= 0 {
+		return ns[idx+1:]
+	}
+	return fe.Field()
+}
+
+func validationMessage(fe validator.FieldError) (service.MessageKey, service.MessageParams) {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return service.MsgFieldRequired, nil
+	case "oneof":
+		return service.MsgFieldOneOf, service.MessageParams{"values": strings.Join(strings.Fields(fe.Param()), ", ")}
+	case "min", "gte":
+		return service.MsgFieldMin, service.MessageParams{"limit": fe.Param()}
+	case "max", "lte":
+		return service.MsgFieldMax, service.MessageParams{"limit": fe.Param()}
+	default:
+		return service.MsgFieldInvalid, nil
+	}
+}
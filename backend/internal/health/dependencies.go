@@ -0,0 +1,23 @@
+package health
+
+import (
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/rpc/modelpb"
+	"story2video-backend/internal/service"
+)
+
+func Dependencies(cfg *conf.Config, d *data.Data) []Check {
+	checks := []Check{
+		Postgres(d.DB),
+		Redis(d.Redis),
+		Kafka(cfg.Kafka.Brokers, service.KafkaTopics(cfg.Kafka)),
+	}
+	if conn := d.RPC.Conn(); conn != nil {
+		checks = append(checks,
+			GRPC("grpc", conn, ""),
+			GRPC("model_service", conn, modelpb.StoryboardService_ServiceDesc.ServiceName),
+		)
+	}
+	return checks
+}
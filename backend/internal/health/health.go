@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 3 * time.Second
+)
+
+type Probe func(ctx context.Context) error
+
+type Check struct {
+	Name  string
+	Probe Probe
+}
+
+type Result struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status    string    `json:"status"`
+	Checks    []Result  `json:"checks"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+func (c *Checker) Add(checks ...Check) {
+	c.checks = append(c.checks, checks...)
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results, CheckedAt: time.Now().UTC()}
+	for _, res := range results {
+		if res.Status != StatusUp {
+			report.Status = StatusDown
+			break
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) Result {
+	start := time.Now()
+	err := check.Probe(ctx)
+	res := Result{
+		Name:      check.Name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
+
+func LiveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusUp})
+	}
+}
+
+func ReadyHandler(c *Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		code := http.StatusOK
+		if report.Status != StatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	}
+}
+
+func Register(mux *http.ServeMux, c *Checker) {
+	mux.HandleFunc(LivePath, LiveHandler())
+	mux.HandleFunc(ReadyPath, ReadyHandler(c))
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"gorm.io/gorm"
+)
+
+func Postgres(db *gorm.DB) Check {
+	return Check{Name: "postgres", Probe: func(ctx context.Context) error {
+		if db == nil {
+			return errors.New("database not configured")
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}}
+}
+
+func Redis(client *redis.Client) Check {
+	return Check{Name: "redis", Probe: func(ctx context.Context) error {
+		if client == nil {
+			return errors.New("redis not configured")
+		}
+		return client.Ping(ctx).Err()
+	}}
+}
+
+func Kafka(brokers []string, topics []string) Check {
+	return Check{Name: "kafka", Probe: func(ctx context.Context) error {
+		if len(brokers) == 0 {
+			return errors.New("no kafka brokers configured")
+		}
+		var lastErr error
+		for _, broker := range brokers {
+			if err := probeBroker(ctx, broker, topics); err != nil {
+				lastErr = err
+				continue
+			}
+			return nil
+		}
+		return lastErr
+	}}
+}
+
+func probeBroker(ctx context.Context, broker string, topics []string) error {
+	conn, err := kafka.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", broker, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if len(topics) == 0 {
+		_, err = conn.Brokers()
+		return err
+	}
+	partitions, err := conn.ReadPartitions(topics...)
+	if err != nil {
+		return fmt.Errorf("read partitions: %w", err)
+	}
+	found := make(map[string]bool, len(topics))
+	for _, p := range partitions {
+		found[p.Topic] = true
+	}
+	for _, topic := range topics {
+		if !found[topic] {
+			return fmt.Errorf("topic %s has no partitions", topic)
+		}
+	}
+	return nil
+}
+
+func GRPC(name string, conn *grpc.ClientConn, service string) Check {
+	return Check{Name: name, Probe: func(ctx context.Context) error {
+		if conn == nil {
+			return errors.New("grpc connection not configured")
+		}
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", resp.GetStatus())
+		}
+		return nil
+	}}
+}
+
+func HTTP(name, url string, client *http.Client) Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return Check{Name: name, Probe: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const serverShutdownTimeout = 5 * time.Second
+
+func Serve(addr string, c *Checker, logger *zap.Logger) func() {
+	mux := http.NewServeMux()
+	Register(mux, c)
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("health server error", zap.Error(err))
+		}
+	}()
+	logger.Info("health server started", zap.String("addr", addr))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}
+}
+
+func Watch(ctx context.Context, server *grpchealth.Server, c *Checker, interval time.Duration, logger *zap.Logger, services ...string) {
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	update := func() {
+		report := c.Run(ctx)
+		status := healthpb.HealthCheckResponse_SERVING
+		if report.Status != StatusUp {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if status != last {
+			logger.Info("serving status changed", zap.String("status", status.String()), zap.Any("checks", report.Checks))
+			last = status
+		}
+		for _, service := range services {
+			server.SetServingStatus(service, status)
+		}
+	}
+
+	update()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			update()
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const (
+	namespace = "story2video"
+
+	Path = "/metrics"
+
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeFlagged   = "flagged"
+	OutcomeInvalid   = "invalid"
+	OutcomeRequeued  = "requeued"
+	OutcomeDeferred  = "deferred"
+	OutcomeSkipped   = "skipped"
+
+	ResultOK    = "ok"
+	ResultError = "error"
+
+	serverShutdownTimeout = 5 * time.Second
+)
+
+var jobBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1200}
+
+var (
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	KafkaPublishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "publish_duration_seconds",
+		Help:      "Latency of publishing job messages to Kafka by result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	KafkaPublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "publish_failures_total",
+		Help:      "Job messages that could not be published to Kafka by error code.",
+	}, []string{"code"})
+
+	WorkerJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "job_duration_seconds",
+		Help:      "Worker job processing time by action and outcome.",
+		Buckets:   jobBuckets,
+	}, []string{"action", "outcome"})
+
+	WorkerQueueLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "queue_lag_seconds",
+		Help:      "Time between a job being published and a worker starting it, by action.",
+		Buckets:   jobBuckets,
+	}, []string{"action"})
+
+	WorkerConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "consumer_lag_messages",
+		Help:      "Kafka messages not yet fetched by the worker consumer group, by topic.",
+	}, []string{"topic"})
+
+	GRPCRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC unary call latency by full method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	GRPCRateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "rate_limit_rejections_total",
+		Help:      "gRPC calls rejected by the concurrency limiter, by full method.",
+	}, []string{"method"})
+
+	ModelServiceResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "model_service",
+		Name:      "responses_total",
+		Help:      "Model service HTTP responses by path and status code.",
+	}, []string{"path", "status"})
+)
+
+type Pool interface {
+	Running() int
+	Cap() int
+	Waiting() int
+}
+
+func RegisterPool(pool Pool) {
+	gauge := func(name, help string, value func() int) {
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return float64(value()) })
+	}
+	gauge("pool_running", "Goroutines currently running jobs in the worker pool.", pool.Running)
+	gauge("pool_capacity", "Capacity of the worker pool.", pool.Cap)
+	gauge("pool_waiting", "Jobs blocked waiting for a free worker pool slot.", pool.Waiting)
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func Serve(addr string, logger *zap.Logger) func() {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server error", zap.Error(err))
+		}
+	}()
+	logger.Info("metrics server started", zap.String("addr", addr))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}
+}
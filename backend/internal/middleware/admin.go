@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/service"
+)
+
+const ContextAdminKey = "is_admin"
+
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(ContextUserIDKey); !ok {
+			AbortWithError(c, service.ErrCodeUnauthenticated, "")
+			return
+		}
+		if !IsAdmin(c) {
+			AbortWithError(c, service.ErrCodePermissionDenied, "")
+			return
+		}
+		c.Next()
+	}
+}
+
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(ContextAdminKey)
+}
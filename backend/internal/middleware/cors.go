@@ -21,8 +21,8 @@ func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Authorization", "Content-Type", "X-User-ID"},
-		ExposeHeaders:    []string{},
+		AllowHeaders:     []string{"Authorization", "Content-Type", "Accept-Language", "X-Request-ID", "X-User-ID", "X-User-Locale"},
+		ExposeHeaders:    []string{"Content-Language", "X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           86400,
 	}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/service"
+)
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type ErrorBody struct {
+	Code       service.ErrorCode `json:"code"`
+	Message    string            `json:"message"`
+	Violations []FieldViolation  `json:"violations,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+}
+
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+func AbortWithError(c *gin.Context, code service.ErrorCode, message string, violations ...FieldViolation) {
+	if message == "" {
+		message = code.LocalizedMessage(RequestLocale(c))
+	}
+	c.AbortWithStatusJSON(httpStatusFromCode(code), ErrorEnvelope{Error: ErrorBody{
+		Code:       code,
+		Message:    message,
+		Violations: violations,
+		RequestID:  RequestIDFromContext(c),
+	}})
+}
+
+func RequestLocale(c *gin.Context) string {
+	if locale := LocaleFromContext(c); locale != "" {
+		return locale
+	}
+	return service.DefaultLocale
+}
+
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		AbortWithError(c, service.ErrCodeMethodNotFound, "")
+	}
+}
+
+func httpStatusFromCode(code service.ErrorCode) int {
+	switch code {
+	case service.ErrCodeInvalidRequest,
+		service.ErrCodeInvalidStyle,
+		service.ErrCodeInvalidShotDetails,
+		service.ErrCodeDocumentParseFailed,
+		service.ErrCodeScriptEmpty,
+		service.ErrCodeScriptTooShort,
+		service.ErrCodeScriptLanguage,
+		service.ErrCodeScriptBannedContent:
+		return http.StatusBadRequest
+	case service.ErrCodeUnauthenticated:
+		return http.StatusUnauthorized
+	case service.ErrCodePermissionDenied:
+		return http.StatusForbidden
+	case service.ErrCodeScriptTooLong,
+		service.ErrCodeScriptTooManyShots,
+		service.ErrCodePayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case service.ErrCodeUnsupportedDocument:
+		return http.StatusUnsupportedMediaType
+	case service.ErrCodeContentFlagged:
+		return http.StatusUnprocessableEntity
+	case service.ErrCodeStoryNotFound,
+		service.ErrCodeShotNotFound,
+		service.ErrCodeOperationNotFound,
+		service.ErrCodeModerationNotFound,
+		service.ErrCodeBatchNotFound,
+		service.ErrCodeMethodNotFound:
+		return http.StatusNotFound
+	case service.ErrCodeExportNotReady, service.ErrCodeOperationNotCancellable, service.ErrCodeOperationNotScheduled, service.ErrCodeOperationNotRetryable:
+		return http.StatusConflict
+	case service.ErrCodeOperationTimeout:
+		return http.StatusGatewayTimeout
+	case service.ErrCodeKafkaConfigInvalid:
+		return http.StatusInternalServerError
+	case service.ErrCodeJobEnqueueFailed,
+		service.ErrCodeWorkerExecutionFailed,
+		service.ErrCodeResultDataMissing,
+		service.ErrCodeExportFailed,
+		service.ErrCodeModerationFailed,
+		service.ErrCodeShotMissingPartial,
+		service.ErrCodeShotContentMissing,
+		service.ErrCodeShotAssetMissing:
+		return http.StatusBadGateway
+	case service.ErrCodeOperationCreateFailed,
+		service.ErrCodeOperationUpdateFailed,
+		service.ErrCodeInternal,
+		service.ErrCodeDatabaseActionFailed:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ContextLocaleKey = "locale"
+
+func Locale(defaultLocale string, normalize func(string) (string, bool)) gin.HandlerFunc {
+	if resolved, ok := normalize(defaultLocale); ok {
+		defaultLocale = resolved
+	}
+	return func(c *gin.Context) {
+		locale := defaultLocale
+		if resolved, ok := resolveLocale(c, normalize); ok {
+			locale = resolved
+		}
+		c.Set(ContextLocaleKey, locale)
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
+
+func LocaleFromContext(c *gin.Context) string {
+	return c.GetString(ContextLocaleKey)
+}
+
+func resolveLocale(c *gin.Context, normalize func(string) (string, bool)) (string, bool) {
+	for _, candidate := range []string{c.Query("lang"), c.GetHeader("X-User-Locale")} {
+		if candidate == "" {
+			continue
+		}
+		if resolved, ok := normalize(candidate); ok {
+			return resolved, true
+		}
+	}
+	for _, tag := range parseAcceptLanguage(c.GetHeader("Accept-Language")) {
+		if resolved, ok := normalize(tag); ok {
+			return resolved, true
+		}
+	}
+	return "", false
+}
+
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+	tags := make([]string, len(entries))
+	for i, e := range entries {
+		tags[i] = e.tag
+	}
+	return tags
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"story2video-backend/internal/tracing"
 )
 
 func Logger(log *zap.Logger) gin.HandlerFunc {
@@ -20,6 +22,8 @@ func Logger(log *zap.Logger) gin.HandlerFunc {
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("duration", duration),
 			zap.String("client_ip", c.ClientIP()),
+			zap.String("request_id", RequestIDFromContext(c)),
+			zap.String("trace_id", tracing.TraceID(c.Request.Context())),
 		)
 	}
 }
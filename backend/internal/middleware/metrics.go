@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"story2video-backend/internal/metrics"
+	"story2video-backend/pkg/openapi"
+)
+
+const unmatchedRoute = "unmatched"
+
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, routeLabel(c), strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+func routeLabel(c *gin.Context) string {
+	if route := c.GetString(openapi.ContextRouteKey); route != "" {
+		return route
+	}
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return unmatchedRoute
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	ContextRequestIDKey = "request_id"
+	HeaderRequestID     = "X-Request-ID"
+	maxRequestIDLength  = 128
+)
+
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(ContextRequestIDKey, requestID)
+		c.Header(HeaderRequestID, requestID)
+		c.Next()
+	}
+}
+
+func RequestIDFromContext(c *gin.Context) string {
+	return c.GetString(ContextRequestIDKey)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -1,27 +1,33 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+
+	"story2video-backend/internal/auth"
+	"story2video-backend/internal/service"
 )
 
 const ContextUserIDKey = "user_id"
 
-func User() gin.HandlerFunc {
+func User(authn *auth.Authenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userHeader := c.GetHeader("X-User-ID")
-		if userHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID"})
-			return
-		}
-		userID, err := uuid.Parse(userHeader)
+		identity, err := authn.Authenticate(c.GetHeader(auth.HeaderUserID))
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid X-User-ID"})
+			key := service.MsgFieldInvalid
+			if errors.Is(err, auth.ErrMissingUser) {
+				key = service.MsgFieldRequired
+			}
+			AbortWithError(c, service.ErrCodeUnauthenticated, "", FieldViolation{
+				Field:       auth.HeaderUserID,
+				Description: service.Localize(RequestLocale(c), key, nil),
+			})
 			return
 		}
-		c.Set(ContextUserIDKey, userID)
+		c.Set(ContextUserIDKey, identity.UserID)
+		c.Set(ContextAdminKey, identity.Admin)
+		c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
 		c.Next()
 	}
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/global"
+)
+
+type Batch struct {
+	BaseModel
+	Status      string     `gorm:"type:varchar(16);not null;default:'running';index" json:"status"`
+	Total       int        `gorm:"not null" json:"total"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func NewBatch(id, userID uuid.UUID, total int) *Batch {
+	return &Batch{
+		BaseModel: BaseModel{
+			ID:     id,
+			UserID: userID,
+		},
+		Status: global.BatchRunning,
+		Total:  total,
+	}
+}
+
+func (Batch) TableName() string {
+	return "batches"
+}
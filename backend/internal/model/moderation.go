@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"story2video-backend/internal/global"
+)
+
+type ModerationRecord struct {
+	BaseModel
+	StoryID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"story_id"`
+	ShotID     *uuid.UUID     `gorm:"type:uuid;index" json:"shot_id,omitempty"`
+	Target     string         `gorm:"type:varchar(16);not null" json:"target"`
+	Backend    string         `gorm:"type:varchar(32)" json:"backend"`
+	Categories datatypes.JSON `json:"categories"`
+	Reason     string         `gorm:"type:text" json:"reason"`
+	Content    datatypes.JSON `json:"content"`
+	Status     string         `gorm:"type:varchar(16);not null;default:'pending';index" json:"status"`
+	ReviewerID *uuid.UUID     `gorm:"type:uuid" json:"reviewer_id,omitempty"`
+	ReviewNote string         `gorm:"type:text" json:"review_note"`
+	ReviewedAt *time.Time     `json:"reviewed_at,omitempty"`
+}
+
+func NewModerationRecord(userID, storyID uuid.UUID, target string) *ModerationRecord {
+	return &ModerationRecord{
+		BaseModel: BaseModel{
+			ID:     uuid.New(),
+			UserID: userID,
+		},
+		StoryID: storyID,
+		Target:  target,
+		Status:  global.ModerationPending,
+	}
+}
+
+func (ModerationRecord) TableName() string {
+	return "moderation_records"
+}
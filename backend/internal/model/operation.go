@@ -11,16 +11,25 @@ import (
 
 type Operation struct {
 	BaseModel
-	StoryID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"story_id"`
-	ShotID     uuid.UUID      `gorm:"type:uuid" json:"shot_id"`
-	Type       string         `gorm:"type:varchar(32);not null" json:"type"`
-	Payload    datatypes.JSON `json:"payload"`
-	Status     string         `gorm:"type:varchar(16);not null;default:'queued'" json:"status"`
-	Retries    int            `json:"retries"`
-	ErrorMsg   string         `gorm:"type:text" json:"error_msg"`
-	Worker     string         `gorm:"type:varchar(64)" json:"worker"`
-	StartedAt  *time.Time     `json:"started_at"`
-	FinishedAt *time.Time     `json:"finished_at"`
+	StoryID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"story_id"`
+	ShotID         uuid.UUID      `gorm:"type:uuid" json:"shot_id"`
+	BatchID        *uuid.UUID     `gorm:"type:uuid;index" json:"batch_id,omitempty"`
+	ParentID       *uuid.UUID     `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Type           string         `gorm:"type:varchar(32);not null" json:"type"`
+	Payload        datatypes.JSON `json:"payload"`
+	Result         datatypes.JSON `json:"result"`
+	Status         string         `gorm:"type:varchar(16);not null;default:'queued'" json:"status"`
+	Retries        int            `json:"retries"`
+	ErrorMsg       string         `gorm:"type:text" json:"error_msg"`
+	ErrorCode      string         `gorm:"type:varchar(16)" json:"error_code"`
+	ErrorRetryable bool           `gorm:"not null;default:false" json:"error_retryable"`
+	ErrorDetails   datatypes.JSON `json:"-"`
+	Worker         string         `gorm:"type:varchar(64)" json:"worker"`
+	ScheduledAt    *time.Time     `gorm:"index" json:"scheduled_at,omitempty"`
+	DispatchLease  *time.Time     `gorm:"index" json:"-"`
+	StartedAt      *time.Time     `json:"started_at"`
+	FinishedAt     *time.Time     `json:"finished_at"`
+	QueuePosition  *int           `gorm:"-" json:"queue_position,omitempty"`
 }
 
 func NewOperation(id, userID, storyID uuid.UUID, shotID uuid.UUID, opType string, payload datatypes.JSON) *Operation {
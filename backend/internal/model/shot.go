@@ -8,18 +8,20 @@ import (
 
 type Shot struct {
 	BaseModel
-	StoryID     uuid.UUID `gorm:"type:uuid;not null;index" json:"story_id"`
-	Sequence    string    `gorm:"type:varchar(64);index" json:"sequence"`
-	Title       string    `gorm:"type:varchar(255)" json:"title"`
-	Description string    `gorm:"type:text" json:"description"`
-	Details     string    `gorm:"type:text" json:"details"`
-	Narration   string    `gorm:"type:text" json:"narration"`
-	Type        string    `gorm:"type:text" json:"type"`
-	Transition  string    `gorm:"type:varchar(32);not null;default:'none'" json:"transition"`
-	Voice       string    `gorm:"type:varchar(8)" json:"voice"`
-	Status      string    `gorm:"type:varchar(16);not null;default:'pending'" json:"status"`
-	ImageURL    string    `gorm:"type:varchar(512)" json:"image_url"`
-	BGM         string    `gorm:"type:varchar(255)" json:"bgm"`
+	StoryID         uuid.UUID `gorm:"type:uuid;not null;index" json:"story_id"`
+	Sequence        string    `gorm:"type:varchar(64);index" json:"sequence"`
+	Title           string    `gorm:"type:varchar(255)" json:"title"`
+	Description     string    `gorm:"type:text" json:"description"`
+	Details         string    `gorm:"type:text" json:"details"`
+	Narration       string    `gorm:"type:text" json:"narration"`
+	Type            string    `gorm:"type:text" json:"type"`
+	Transition      string    `gorm:"type:varchar(32);not null;default:'none'" json:"transition"`
+	Voice           string    `gorm:"type:varchar(8)" json:"voice"`
+	Status          string    `gorm:"type:varchar(16);not null;default:'pending'" json:"status"`
+	ImageURL        string    `gorm:"type:varchar(512)" json:"image_url"`
+	BGM             string    `gorm:"type:varchar(255)" json:"bgm"`
+	AudioURL        string    `gorm:"type:varchar(512)" json:"audio_url"`
+	AudioDurationMs int64     `gorm:"not null;default:0" json:"audio_duration_ms"`
 }
 
 func NewShot(id, userID, storyID uuid.UUID) *Shot {
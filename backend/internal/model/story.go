@@ -9,14 +9,15 @@ import (
 
 type Story struct {
 	BaseModel
-	Content  string         `gorm:"type:text;not null" json:"content"`
-	Title    string         `gorm:"type:varchar(255)" json:"title"`
-	Style    string         `gorm:"type:varchar(64)" json:"style"`
-	Duration int            `json:"duration"`
-	Status   string         `gorm:"type:varchar(16);not null;default:'draft'" json:"status"`
-	Timeline datatypes.JSON `json:"timeline"`
-	CoverURL string         `gorm:"type:varchar(512)" json:"cover_url"`
-	VideoURL string         `gorm:"type:varchar(512)" json:"video_url"`
+	Content       string         `gorm:"type:text;not null" json:"content"`
+	Title         string         `gorm:"type:varchar(255)" json:"title"`
+	Style         string         `gorm:"type:varchar(64)" json:"style"`
+	Duration      int            `json:"duration"`
+	Status        string         `gorm:"type:varchar(16);not null;default:'draft'" json:"status"`
+	Timeline      datatypes.JSON `json:"timeline"`
+	CoverURL      string         `gorm:"type:varchar(512)" json:"cover_url"`
+	VideoURL      string         `gorm:"type:varchar(512)" json:"video_url"`
+	ParentStoryID *uuid.UUID     `gorm:"type:uuid;index" json:"parent_story_id"`
 }
 
 func NewStory(id, userID uuid.UUID, content string) *Story {
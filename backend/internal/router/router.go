@@ -1,49 +1,167 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"story2video-backend/internal/auth"
 	"story2video-backend/internal/conf"
-	"story2video-backend/internal/data"
 	"story2video-backend/internal/handler"
+	"story2video-backend/internal/health"
+	"story2video-backend/internal/metrics"
 	"story2video-backend/internal/middleware"
 	"story2video-backend/internal/service"
+	"story2video-backend/pkg/openapi"
+)
+
+const (
+	apiTitle   = "story2video"
+	apiVersion = "v1"
+
+	contractCheckLog    = "log"
+	contractCheckStrict = "strict"
 )
 
 func NewRouter(
 	cfg *conf.Config,
 	log *zap.Logger,
-	d *data.Data,
+	authn *auth.Authenticator,
+	checker *health.Checker,
 	homeService *service.HomeService,
 	storyService *service.StoryService,
 	shotService *service.ShotService,
+	operationService *service.OperationService,
+	batchService *service.BatchService,
+	exportService *service.ExportService,
+	importService *service.ImportService,
+	forkService *service.ForkService,
+	moderationService *service.ModerationService,
 ) *gin.Engine {
+	r, _ := newRouter(cfg, log, authn, checker, homeService, storyService, shotService, operationService, batchService, exportService, importService, forkService, moderationService)
+	return r
+}
+
+func newRouter(
+	cfg *conf.Config,
+	log *zap.Logger,
+	authn *auth.Authenticator,
+	checker *health.Checker,
+	homeService *service.HomeService,
+	storyService *service.StoryService,
+	shotService *service.ShotService,
+	operationService *service.OperationService,
+	batchService *service.BatchService,
+	exportService *service.ExportService,
+	importService *service.ImportService,
+	forkService *service.ForkService,
+	moderationService *service.ModerationService,
+) (*gin.Engine, *openapi.Document) {
 	gin.SetMode(cfg.Server.Mode)
 
 	r := gin.New()
-	r.Use(gin.Recovery())
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		middleware.AbortWithError(c, service.ErrCodeInternal, "")
+	}))
+	r.GET(health.LivePath, gin.WrapF(health.LiveHandler()))
+	r.GET(health.ReadyPath, gin.WrapF(health.ReadyHandler(checker)))
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Tracing())
+	if cfg.Metrics.Enabled {
+		r.Use(middleware.Metrics())
+		r.GET(metrics.Path, gin.WrapH(metrics.Handler()))
+	}
 	r.Use(middleware.Logger(log))
 	r.Use(middleware.CORSWithOrigins(cfg.CORS.AllowOrigins))
+	r.Use(middleware.Locale(cfg.I18n.DefaultLocale, service.NormalizeLocale))
+	r.NoRoute(middleware.NoRoute())
+	handler.RegisterValidation()
+
+	spec := openapi.NewDocument(apiTitle, apiVersion, middleware.ErrorEnvelope{})
+	if check := cfg.Server.ContractCheck; check == contractCheckLog || check == contractCheckStrict {
+		r.Use(openapi.ContractCheck(spec, check == contractCheckStrict, contractReporter(log, check == contractCheckStrict)))
+	}
+	openapi.NewRoutes(spec, &r.RouterGroup).Handle(http.MethodGet, "/v1/openapi.json", handler.GetOpenAPIDocumentOp, spec.Handler())
 
 	api := r.Group("/v1")
-	api.Use(middleware.User())
+	api.Use(middleware.User(authn))
+	routes := openapi.NewRoutes(spec, api)
+	assets := r.Group(service.NewAssetStore(cfg).URLPrefix())
+	assets.Use(middleware.User(authn))
 
 	storyHandler := handler.NewStoryHandler(homeService, storyService)
 	shotHandler := handler.NewShotHandler(shotService)
-	opHandler := handler.NewOperationHandler(d)
+	opHandler := handler.NewOperationHandler(operationService)
+	batchHandler := handler.NewBatchHandler(batchService)
+	exportHandler := handler.NewExportHandler(exportService)
+	importHandler := handler.NewImportHandler(importService)
+	forkHandler := handler.NewForkHandler(forkService)
+	moderationHandler := handler.NewModerationHandler(moderationService)
 
-	api.GET("/stories", storyHandler.List)
-	api.POST("/stories", storyHandler.Create)
-	api.POST("/stories/batch", storyHandler.BatchCreate)
-	api.GET("/stories/:storyID", storyHandler.Get)
-	api.GET("/stories/:storyID/shots", shotHandler.List)
-	api.GET("/stories/:storyID/shots/:shotID", shotHandler.Get)
-	api.PATCH("/stories/:storyID/shots/:shotID", shotHandler.Update)
-	api.POST("/stories/:storyID/shots/:shotID/regenerate", shotHandler.Regenerate)
-	api.POST("/stories/:storyID/compile", shotHandler.Render)
+	assets.GET("/stories/:storyID/:name", storyHandler.Asset)
 
-	api.GET("/operations/:operationID", opHandler.Get)
+	routes.Handle(http.MethodGet, "/stories", handler.ListStoriesOp, storyHandler.List)
+	routes.Handle(http.MethodPost, "/stories", handler.CreateStoryOp, storyHandler.Create)
+	routes.Handle(http.MethodPost, "/stories/batch", handler.BatchCreateStoriesOp, storyHandler.BatchCreate)
+	api.POST("/:collectionMethod", handler.CollectionMethods("collectionMethod", map[string]gin.HandlerFunc{
+		"stories:import":   routes.Method(http.MethodPost, "/stories:import", handler.ImportStoryOp, importHandler.Import),
+		"stories:upload":   routes.Method(http.MethodPost, "/stories:upload", handler.UploadStoriesOp, storyHandler.Upload),
+		"stories:estimate": routes.Method(http.MethodPost, "/stories:estimate", handler.EstimateStoryOp, storyHandler.Estimate),
+	}))
+	routes.Handle(http.MethodGet, "/stories/:storyID", handler.GetStoryOp, storyHandler.Get)
+	api.POST("/stories/:storyID", handler.ResourceMethods("storyID", map[string]gin.HandlerFunc{
+		"duplicate": routes.Method(http.MethodPost, "/stories/:storyID:duplicate", handler.DuplicateStoryOp, forkHandler.Duplicate),
+	}))
+	routes.Handle(http.MethodGet, "/stories/:storyID/subtitles", handler.GetStorySubtitlesOp, storyHandler.Subtitles)
+	routes.Handle(http.MethodGet, "/stories/:storyID/export", handler.ExportStoryOp, exportHandler.Export)
+	routes.Handle(http.MethodGet, "/stories/:storyID/operations", handler.ListStoryOperationsOp, opHandler.ListForStory)
+	routes.Handle(http.MethodGet, "/stories/:storyID/shots", handler.ListShotsOp, shotHandler.List)
+	routes.Handle(http.MethodGet, "/stories/:storyID/shots/:shotID", handler.GetShotOp, shotHandler.Get)
+	routes.Handle(http.MethodPatch, "/stories/:storyID/shots/:shotID", handler.UpdateShotOp, shotHandler.Update)
+	routes.Handle(http.MethodPost, "/stories/:storyID/shots/:shotID/regenerate", handler.RegenerateShotOp, shotHandler.Regenerate)
+	routes.Handle(http.MethodPost, "/stories/:storyID/compile", handler.CompileStoryOp, shotHandler.Render)
 
-	return r
+	routes.Handle(http.MethodGet, "/operations", handler.ListOperationsOp, opHandler.List)
+	routes.Handle(http.MethodGet, "/operations/:operationID", handler.GetOperationOp, opHandler.Get)
+	api.POST("/operations/:operationID", handler.ResourceMethods("operationID", map[string]gin.HandlerFunc{
+		"cancel":     routes.Method(http.MethodPost, "/operations/:operationID:cancel", handler.CancelOperationOp, opHandler.Cancel),
+		"reschedule": routes.Method(http.MethodPost, "/operations/:operationID:reschedule", handler.RescheduleOperationOp, opHandler.Reschedule),
+		"retry":      routes.Method(http.MethodPost, "/operations/:operationID:retry", handler.RetryOperationOp, opHandler.Retry),
+	}))
+	routes.Handle(http.MethodGet, "/operations/:operationID/download", handler.DownloadOperationOp, exportHandler.Download)
+
+	routes.Handle(http.MethodGet, "/batches/:batchID", handler.GetBatchOp, batchHandler.Get)
+	api.POST("/batches/:batchID", handler.ResourceMethods("batchID", map[string]gin.HandlerFunc{
+		"cancel": routes.Method(http.MethodPost, "/batches/:batchID:cancel", handler.CancelBatchOp, batchHandler.Cancel),
+		"retry":  routes.Method(http.MethodPost, "/batches/:batchID:retry", handler.RetryBatchOp, batchHandler.Retry),
+	}))
+
+	admin := api.Group("/admin")
+	admin.Use(middleware.Admin())
+	adminRoutes := openapi.NewRoutes(spec, admin)
+	adminRoutes.Handle(http.MethodGet, "/moderation", handler.ListModerationRecordsOp, moderationHandler.List)
+	adminRoutes.Handle(http.MethodGet, "/moderation/:recordID", handler.GetModerationRecordOp, moderationHandler.Get)
+	admin.POST("/moderation/:recordID", handler.ResourceMethods("recordID", map[string]gin.HandlerFunc{
+		"review": adminRoutes.Method(http.MethodPost, "/moderation/:recordID:review", handler.ReviewModerationRecordOp, moderationHandler.Review),
+	}))
+
+	if missing := spec.Undocumented(r.Routes(), "/v1/"); len(missing) > 0 {
+		log.Warn("routes missing from openapi document", zap.Strings("routes", missing))
+	}
+
+	return r, spec
+}
+
+func contractReporter(log *zap.Logger, strict bool) openapi.ContractReporter {
+	return func(c *gin.Context, err error) {
+		log.Error("response drifted from openapi document",
+			zap.String("request_id", middleware.RequestIDFromContext(c)),
+			zap.String("path", c.FullPath()),
+			zap.Error(err),
+		)
+		if strict {
+			middleware.AbortWithError(c, service.ErrCodeInternal, "")
+		}
+	}
 }
@@ -0,0 +1,439 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"story2video-backend/internal/auth"
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/health"
+	"story2video-backend/internal/service"
+	"story2video-backend/pkg/openapi"
+)
+
+const (
+	testUserID   = "00000000-0000-0000-0000-000000000001"
+	testStoryID  = "00000000-0000-0000-0000-0000000000a1"
+	testShotID   = "00000000-0000-0000-0000-0000000000b1"
+	testOpID     = "00000000-0000-0000-0000-0000000000c1"
+	testBatchID  = "00000000-0000-0000-0000-0000000000d1"
+	testRecordID = "00000000-0000-0000-0000-0000000000e1"
+)
+
+type contractCase struct {
+	op          string
+	method      string
+	path        string
+	body        string
+	contentType string
+}
+
+func TestRouterContract(t *testing.T) {
+	r, spec := newTestRouter(t)
+
+	if missing := spec.Undocumented(r.Routes(), "/v1/"); len(missing) > 0 {
+		t.Fatalf("routes missing from openapi document: %v", missing)
+	}
+
+	upload, uploadType := multipartUpload(t)
+	cases := []contractCase{
+		{op: "GetOpenAPIDocument", method: http.MethodGet, path: "/v1/openapi.json"},
+		{op: "ListStories", method: http.MethodGet, path: "/v1/stories?page_size=5"},
+		{op: "CreateStory", method: http.MethodPost, path: "/v1/stories", body: `{"display_name":"t","script_content":"从前有座山，山里有座庙，庙里有个老和尚。","style":"movie"}`},
+		{op: "BatchCreateStories", method: http.MethodPost, path: "/v1/stories/batch", body: `{"items":[{"display_name":"t","script_content":"从前有座山，山里有座庙，庙里有个老和尚。","style":"movie"}]}`},
+		{op: "ImportStory", method: http.MethodPost, path: "/v1/stories:import", body: `{"version":1,"story":{"display_name":"t","script_content":"从前有座山，山里有座庙，庙里有个老和尚。","style":"movie"},"shots":[{"sequence":"1","title":"s","description":"c","details":"d","narration":"n","image_url":"/assets/stories/` + testStoryID + `/a.png"}]}`},
+		{op: "UploadStories", method: http.MethodPost, path: "/v1/stories:upload", body: upload, contentType: uploadType},
+		{op: "EstimateStory", method: http.MethodPost, path: "/v1/stories:estimate", body: `{"script_content":"从前有座山，山里有座庙，庙里有个老和尚。","style":"movie"}`},
+		{op: "GetStory", method: http.MethodGet, path: "/v1/stories/" + testStoryID},
+		{op: "DuplicateStory", method: http.MethodPost, path: "/v1/stories/" + testStoryID + ":duplicate", body: `{"display_name":"copy"}`},
+		{op: "GetStorySubtitles", method: http.MethodGet, path: "/v1/stories/" + testStoryID + "/subtitles"},
+		{op: "ExportStory", method: http.MethodGet, path: "/v1/stories/" + testStoryID + "/export?format=zip"},
+		{op: "ListStoryOperations", method: http.MethodGet, path: "/v1/stories/" + testStoryID + "/operations"},
+		{op: "ListShots", method: http.MethodGet, path: "/v1/stories/" + testStoryID + "/shots"},
+		{op: "GetShot", method: http.MethodGet, path: "/v1/stories/" + testStoryID + "/shots/" + testShotID},
+		{op: "UpdateShot", method: http.MethodPatch, path: "/v1/stories/" + testStoryID + "/shots/" + testShotID, body: `{"shot":{"title":"new"}}`},
+		{op: "RegenerateShot", method: http.MethodPost, path: "/v1/stories/" + testStoryID + "/shots/" + testShotID + "/regenerate", body: `{"details":"d"}`},
+		{op: "CompileStory", method: http.MethodPost, path: "/v1/stories/" + testStoryID + "/compile"},
+		{op: "ListOperations", method: http.MethodGet, path: "/v1/operations"},
+		{op: "GetOperation", method: http.MethodGet, path: "/v1/operations/" + testOpID},
+		{op: "CancelOperation", method: http.MethodPost, path: "/v1/operations/" + testOpID + ":cancel"},
+		{op: "RescheduleOperation", method: http.MethodPost, path: "/v1/operations/" + testOpID + ":reschedule", body: `{"scheduled_at":"` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`},
+		{op: "RetryOperation", method: http.MethodPost, path: "/v1/operations/" + testOpID + ":retry"},
+		{op: "DownloadOperation", method: http.MethodGet, path: "/v1/operations/" + testOpID + "/download"},
+		{op: "GetBatch", method: http.MethodGet, path: "/v1/batches/" + testBatchID},
+		{op: "CancelBatch", method: http.MethodPost, path: "/v1/batches/" + testBatchID + ":cancel"},
+		{op: "RetryBatch", method: http.MethodPost, path: "/v1/batches/" + testBatchID + ":retry"},
+		{op: "ListModerationRecords", method: http.MethodGet, path: "/v1/admin/moderation"},
+		{op: "GetModerationRecord", method: http.MethodGet, path: "/v1/admin/moderation/" + testRecordID},
+		{op: "ReviewModerationRecord", method: http.MethodPost, path: "/v1/admin/moderation/" + testRecordID + ":review", body: `{"decision":"approve"}`},
+	}
+
+	exercised := make(map[string]bool, len(cases))
+	for _, tc := range cases {
+		t.Run(tc.op, func(t *testing.T) {
+			if _, ok := spec.Operation(tc.op); !ok {
+				t.Fatalf("operation %s is not documented", tc.op)
+			}
+			exercised[tc.op] = true
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(auth.HeaderUserID, testUserID)
+			if tc.body != "" {
+				contentType := tc.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				req.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if strings.Contains(w.Body.String(), string(service.ErrCodeMethodNotFound)) {
+				t.Fatalf("%s %s did not reach a route: %s", tc.method, tc.path, w.Body.String())
+			}
+			if w.Code >= http.StatusInternalServerError {
+				t.Logf("%s responded %d: %s", tc.op, w.Code, w.Body.String())
+			}
+			if err := spec.ValidateResponse(tc.op, w.Code, w.Header().Get("Content-Type"), w.Body.Bytes()); err != nil {
+				t.Fatalf("%v\nbody: %s", err, w.Body.String())
+			}
+		})
+	}
+
+	var documented []string
+	for _, item := range spec.Paths {
+		for _, op := range item {
+			documented = append(documented, op.OperationID)
+		}
+	}
+	sort.Strings(documented)
+	for _, id := range documented {
+		if !exercised[id] {
+			t.Errorf("documented operation %s is not exercised", id)
+		}
+	}
+}
+
+func newTestRouter(t *testing.T) (*gin.Engine, *openapi.Document) {
+	t.Helper()
+	sql.Register(t.Name(), &fixtureDriver{tables: fixtureTables()})
+	db, err := gorm.Open(postgres.New(postgres.Config{DriverName: t.Name(), DSN: "fixture"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open fixture db: %v", err)
+	}
+
+	cfg := &conf.Config{}
+	cfg.Server.Mode = "test"
+	cfg.Storage.AssetDir = t.TempDir()
+	cfg.Export.Dir = t.TempDir()
+	log := zap.NewNop()
+	d := &data.Data{DB: db}
+
+	r, spec := newRouter(cfg, log,
+		auth.NewAuthenticator([]string{testUserID}),
+		health.NewChecker(time.Second),
+		service.NewHomeService(cfg, d, log),
+		service.NewStoryService(cfg, d, log),
+		service.NewShotService(cfg, d, log),
+		service.NewOperationService(cfg, d, log),
+		service.NewBatchService(cfg, d, log),
+		service.NewExportService(cfg, d, log),
+		service.NewImportService(cfg, d, log),
+		service.NewForkService(cfg, d, log),
+		service.NewModerationService(cfg, d, log),
+	)
+	return r, spec
+}
+
+func multipartUpload(t *testing.T) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", "story.txt")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	_, _ = io.WriteString(part, "第一章\n从前有座山。\n")
+	_ = mw.WriteField("dry_run", "true")
+	if err := mw.Close(); err != nil {
+		t.Fatalf("close multipart: %v", err)
+	}
+	return buf.String(), mw.FormDataContentType()
+}
+
+func fixtureTables() map[string]map[string]driver.Value {
+	now := time.Now().UTC()
+	base := func(id string) map[string]driver.Value {
+		return map[string]driver.Value{
+			"id":         id,
+			"user_id":    testUserID,
+			"created_at": now,
+			"updated_at": now,
+			"deleted_at": nil,
+		}
+	}
+	with := func(row map[string]driver.Value, fields map[string]driver.Value) map[string]driver.Value {
+		for k, v := range fields {
+			row[k] = v
+		}
+		return row
+	}
+	return map[string]map[string]driver.Value{
+		"stories": with(base(testStoryID), map[string]driver.Value{
+			"content":         "从前有座山，山里有座庙，庙里有个老和尚。",
+			"title":           "fixture",
+			"style":           global.StyleMovie,
+			"duration":        int64(10),
+			"status":          global.StoryReady,
+			"timeline":        []byte(`[]`),
+			"cover_url":       "",
+			"video_url":       "",
+			"parent_story_id": nil,
+		}),
+		"shots": with(base(testShotID), map[string]driver.Value{
+			"story_id":          testStoryID,
+			"sequence":          "1",
+			"title":             "shot",
+			"description":       "desc",
+			"details":           "details",
+			"narration":         "从前有座山，山里有座庙，庙里有个老和尚。",
+			"type":              "",
+			"transition":        "none",
+			"voice":             "",
+			"status":            global.ShotDone,
+			"image_url":         "/assets/stories/" + testStoryID + "/a.png",
+			"bgm":               "",
+			"audio_url":         "",
+			"audio_duration_ms": int64(1200),
+		}),
+		"operations": with(base(testOpID), map[string]driver.Value{
+			"story_id":        testStoryID,
+			"shot_id":         testShotID,
+			"batch_id":        nil,
+			"parent_id":       nil,
+			"type":            global.OpShotRegen,
+			"payload":         []byte(`{"action":"regenerate_shot","shot_id":"` + testShotID + `"}`),
+			"result":          nil,
+			"status":          global.OpScheduled,
+			"retries":         int64(0),
+			"error_msg":       "",
+			"error_code":      "",
+			"error_retryable": false,
+			"error_details":   nil,
+			"worker":          "",
+			"scheduled_at":    now.Add(time.Hour),
+			"dispatch_lease":  nil,
+			"started_at":      nil,
+			"finished_at":     nil,
+		}),
+		"batches": with(base(testBatchID), map[string]driver.Value{
+			"status":       global.BatchRunning,
+			"total":        int64(1),
+			"completed_at": nil,
+		}),
+		"moderation_records": with(base(testRecordID), map[string]driver.Value{
+			"story_id":    testStoryID,
+			"shot_id":     nil,
+			"target":      global.ModerationTargetScript,
+			"backend":     "keyword",
+			"categories":  []byte(`["violence"]`),
+			"reason":      "fixture",
+			"content":     []byte(`{}`),
+			"status":      global.ModerationPending,
+			"reviewer_id": nil,
+			"review_note": "",
+			"reviewed_at": nil,
+		}),
+	}
+}
+
+// fixtureDriver answers every SELECT on a known table with that table's
+// single fixture row, projected onto the selected columns; writes succeed
+// without touching anything.
+type fixtureDriver struct {
+	tables map[string]map[string]driver.Value
+	mu     sync.Mutex
+}
+
+func (d *fixtureDriver) Open(string) (driver.Conn, error) {
+	return &fixtureConn{driver: d}, nil
+}
+
+type fixtureConn struct {
+	driver *fixtureDriver
+}
+
+func (c *fixtureConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fixtureConn) Close() error { return nil }
+
+func (c *fixtureConn) Begin() (driver.Tx, error) { return fixtureTx{}, nil }
+
+func (c *fixtureConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fixtureTx{}, nil
+}
+
+func (c *fixtureConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fixtureConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+	return c.driver.query(query), nil
+}
+
+type fixtureTx struct{}
+
+func (fixtureTx) Commit() error   { return nil }
+func (fixtureTx) Rollback() error { return nil }
+
+var (
+	sqlIdentifier = regexp.MustCompile(`^"?(\w+)"?`)
+	sqlAlias      = regexp.MustCompile(`(?i)\s+AS\s+"?(\w+)"?\s*$`)
+	sqlColumn     = regexp.MustCompile(`"?(\w+)"?\s*$`)
+)
+
+func (d *fixtureDriver) query(query string) driver.Rows {
+	query = strings.TrimSpace(query)
+	if !strings.HasPrefix(strings.ToUpper(query), "SELECT") {
+		return &fixtureRows{}
+	}
+	selectList, rest, ok := splitTopLevelFrom(query[len("SELECT"):])
+	if !ok {
+		return &fixtureRows{}
+	}
+	table := ""
+	if m := sqlIdentifier.FindStringSubmatch(strings.TrimSpace(rest)); m != nil {
+		table = m[1]
+	}
+	row, known := d.tables[table]
+	grouped := strings.Contains(strings.ToUpper(rest), "GROUP BY")
+
+	var (
+		columns   []string
+		values    []driver.Value
+		aggregate bool
+	)
+	for _, expr := range splitTopLevel(selectList) {
+		expr = strings.TrimSpace(expr)
+		if strings.HasPrefix(strings.ToUpper(expr), "DISTINCT ") {
+			expr = strings.TrimSpace(expr[len("DISTINCT "):])
+		}
+		if expr == "*" || strings.HasSuffix(expr, ".*") {
+			names := make([]string, 0, len(row))
+			for name := range row {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				columns = append(columns, name)
+				values = append(values, row[name])
+			}
+			continue
+		}
+		name := ""
+		if m := sqlAlias.FindStringSubmatch(expr); m != nil {
+			name = m[1]
+		} else if m := sqlColumn.FindStringSubmatch(expr); m != nil {
+			name = m[1]
+		}
+		if strings.Contains(strings.ToLower(expr), "count(") {
+			aggregate = true
+			if !sqlAlias.MatchString(expr) {
+				name = "count"
+			}
+			columns = append(columns, name)
+			values = append(values, int64(0))
+			continue
+		}
+		columns = append(columns, name)
+		values = append(values, row[name])
+	}
+	if grouped || !known && !aggregate {
+		return &fixtureRows{columns: columns}
+	}
+	return &fixtureRows{columns: columns, rows: [][]driver.Value{values}}
+}
+
+func splitTopLevelFrom(query string) (string, string, bool) {
+	depth := 0
+	upper := strings.ToUpper(query)
+	for i := 0; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ' ', '\n', '\t':
+			if depth == 0 && strings.HasPrefix(upper[i:], " FROM ") {
+				return query[:i], query[i+len(" FROM "):], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func splitTopLevel(list string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, list[start:])
+}
+
+type fixtureRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fixtureRows) Columns() []string { return r.columns }
+
+func (r *fixtureRows) Close() error { return nil }
+
+func (r *fixtureRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
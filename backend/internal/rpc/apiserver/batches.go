@@ -0,0 +1,63 @@
+package apiserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"story2video-backend/internal/rpc/apipb"
+	"story2video-backend/internal/service"
+)
+
+const batchNamePrefix = "batches/"
+
+func (s *Server) GetBatch(ctx context.Context, req *apipb.GetBatchRequest) (*apipb.Batch, error) {
+	return s.batchAction(ctx, req.GetName(), s.batch.Get)
+}
+
+func (s *Server) CancelBatch(ctx context.Context, req *apipb.CancelBatchRequest) (*apipb.Batch, error) {
+	return s.batchAction(ctx, req.GetName(), s.batch.Cancel)
+}
+
+func (s *Server) RetryBatch(ctx context.Context, req *apipb.RetryBatchRequest) (*apipb.Batch, error) {
+	return s.batchAction(ctx, req.GetName(), s.batch.Retry)
+}
+
+func (s *Server) batchAction(ctx context.Context, name string, action func(ctx context.Context, userID, batchID uuid.UUID) (*service.BatchView, error)) (*apipb.Batch, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batchID, err := uuid.Parse(strings.TrimPrefix(name, batchNamePrefix))
+	if err != nil {
+		return nil, s.invalidField(ctx, "name")
+	}
+	view, err := action(ctx, identity.UserID, batchID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBBatch(view), nil
+}
+
+func toPBBatch(view *service.BatchView) *apipb.Batch {
+	return &apipb.Batch{
+		Name:    view.Name,
+		BatchId: view.BatchID.String(),
+		State:   view.State,
+		Total:   int32(view.Total),
+		Counts: &apipb.BatchCounts{
+			Scheduled: int32(view.Counts.Scheduled),
+			Queued:    int32(view.Counts.Queued),
+			Running:   int32(view.Counts.Running),
+			Succeeded: int32(view.Counts.Succeeded),
+			Failed:    int32(view.Counts.Failed),
+			Cancelled: int32(view.Counts.Cancelled),
+		},
+		OperationNames: view.OperationNames,
+		CreateTime:     timestamppb.New(view.CreateTime),
+		UpdateTime:     timestamppb.New(view.UpdateTime),
+		CompleteTime:   optionalTimestamp(view.CompleteTime),
+	}
+}
@@ -0,0 +1,108 @@
+package apiserver
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"story2video-backend/internal/model"
+	"story2video-backend/internal/rpc/apipb"
+	"story2video-backend/internal/service"
+)
+
+func toPBStory(story *model.Story, shots []model.Shot) *apipb.Story {
+	out := &apipb.Story{
+		StoryId:       story.ID.String(),
+		DisplayName:   story.Title,
+		ScriptContent: story.Content,
+		Style:         story.Style,
+		VideoUrl:      story.VideoURL,
+		CompileState:  service.StoryCompileState(story.Status),
+		CoverUrl:      story.CoverURL,
+		CreateTime:    timestamppb.New(story.CreatedAt),
+	}
+	if story.ParentStoryID != nil {
+		out.ParentStoryId = story.ParentStoryID.String()
+	}
+	if out.CoverUrl == "" && len(shots) > 0 {
+		out.CoverUrl = shots[0].ImageURL
+	}
+	for idx, sh := range shots {
+		out.Shots = append(out.Shots, &apipb.Shot{
+			ShotId:      sh.ID.String(),
+			Index:       int32(idx),
+			Title:       sh.Title,
+			Description: sh.Description,
+			Details:     sh.Details,
+			Narration:   sh.Narration,
+			Type:        sh.Type,
+			Transition:  sh.Transition,
+			Voice:       sh.Voice,
+			ImageUrl:    sh.ImageURL,
+			Bgm:         sh.BGM,
+			Status:      sh.Status,
+		})
+	}
+	return out
+}
+
+func toPBOperation(op *model.Operation, opts service.StatusOptions) *apipb.Operation {
+	return toPBOperationView(service.NewOperationView(op, opts))
+}
+
+func toPBOperationView(view service.OperationView) *apipb.Operation {
+	out := &apipb.Operation{
+		Name:         view.OperationName,
+		OperationId:  view.OperationID.String(),
+		StoryId:      view.StoryID.String(),
+		Type:         view.Type,
+		State:        view.State,
+		Retries:      int32(view.Retries),
+		CreateTime:   timestamppb.New(view.CreateTime),
+		UpdateTime:   timestamppb.New(view.UpdateTime),
+		StartTime:    optionalTimestamp(view.StartTime),
+		FinishTime:   optionalTimestamp(view.FinishTime),
+		ScheduleTime: optionalTimestamp(view.ScheduleTime),
+		BatchName:    view.BatchName,
+		ParentName:   view.ParentName,
+		Error:        toPBOperationError(view.Error),
+	}
+	if view.ShotID != nil {
+		out.ShotId = view.ShotID.String()
+	}
+	if view.QueuePosition != nil {
+		out.QueuePosition = int32(*view.QueuePosition)
+	}
+	return out
+}
+
+func toPBOperationError(st *service.OperationStatus) *apipb.OperationError {
+	if st == nil {
+		return nil
+	}
+	out := &apipb.OperationError{
+		Code:    int32(st.Code),
+		Status:  st.Status,
+		Message: st.Message,
+	}
+	if len(st.Details) > 0 {
+		out.Reason, _ = st.Details[0]["reason"].(string)
+		out.Metadata, _ = st.Details[0]["metadata"].(map[string]string)
+	}
+	return out
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
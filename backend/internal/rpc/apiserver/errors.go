@@ -0,0 +1,71 @@
+package apiserver
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"story2video-backend/internal/service"
+)
+
+type fieldViolation struct {
+	field  string
+	key    service.MessageKey
+	params service.MessageParams
+}
+
+func (s *Server) toStatus(ctx context.Context, err error) error {
+	svcErr, ok := service.AsServiceError(err)
+	if !ok {
+		s.logger.Error("grpc api unexpected error", zap.Error(err))
+		svcErr = service.NewServiceError(service.ErrCodeInternal, "")
+	}
+	st := status.New(service.GRPCCode(svcErr.Code), svcErr.LocalizedMessage(s.locale(ctx)))
+	info := &errdetails.ErrorInfo{
+		Reason:   string(svcErr.Code),
+		Domain:   service.ErrorDomain,
+		Metadata: publicErrorDetails(err),
+	}
+	if detailed, detailErr := st.WithDetails(info); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func (s *Server) invalidField(ctx context.Context, field string) error {
+	return s.invalidArgument(ctx, fieldViolation{field: field, key: service.MsgFieldInvalid})
+}
+
+func (s *Server) invalidArgument(ctx context.Context, violations ...fieldViolation) error {
+	locale := s.locale(ctx)
+	st := status.New(codes.InvalidArgument, service.ErrCodeInvalidRequest.LocalizedMessage(locale))
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.field,
+			Description: service.Localize(locale, v.key, v.params),
+		})
+	}
+	info := &errdetails.ErrorInfo{Reason: string(service.ErrCodeInvalidRequest), Domain: service.ErrorDomain}
+	if detailed, err := st.WithDetails(info, badRequest); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func publicErrorDetails(err error) map[string]string {
+	var metadata map[string]string
+	for k, v := range service.ErrorDetails(err) {
+		if !service.IsPublicErrorDetail(k) {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[k] = v
+	}
+	return metadata
+}
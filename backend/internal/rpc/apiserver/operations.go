@@ -0,0 +1,123 @@
+package apiserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/rpc/apipb"
+	"story2video-backend/internal/service"
+)
+
+const operationNamePrefix = "operations/"
+
+func (s *Server) GetOperation(ctx context.Context, req *apipb.GetOperationRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opID, err := parseOperationName(req.GetName())
+	if err != nil {
+		return nil, s.invalidField(ctx, "name")
+	}
+	op, err := s.operation.Get(ctx, identity.UserID, opID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func (s *Server) ListOperations(ctx context.Context, req *apipb.ListOperationsRequest) (*apipb.ListOperationsResponse, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetPageSize() < 0 {
+		return nil, s.invalidField(ctx, "page_size")
+	}
+	opts := service.OperationListOptions{
+		Types:     req.GetTypes(),
+		Statuses:  req.GetStates(),
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		Status:    s.statusOptions(ctx, identity),
+	}
+	if raw := req.GetStoryId(); raw != "" {
+		storyID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, s.invalidField(ctx, "story_id")
+		}
+		opts.StoryID = &storyID
+	}
+	views, next, err := s.operation.List(ctx, identity.UserID, opts)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &apipb.ListOperationsResponse{
+		Operations:    make([]*apipb.Operation, 0, len(views)),
+		NextPageToken: next,
+	}
+	for _, view := range views {
+		resp.Operations = append(resp.Operations, toPBOperationView(view))
+	}
+	return resp, nil
+}
+
+func (s *Server) CancelOperation(ctx context.Context, req *apipb.CancelOperationRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opID, err := parseOperationName(req.GetName())
+	if err != nil {
+		return nil, s.invalidField(ctx, "name")
+	}
+	op, err := s.operation.Cancel(ctx, identity.UserID, opID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func (s *Server) RescheduleOperation(ctx context.Context, req *apipb.RescheduleOperationRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opID, err := parseOperationName(req.GetName())
+	if err != nil {
+		return nil, s.invalidField(ctx, "name")
+	}
+	if req.GetScheduledAt() == nil {
+		return nil, s.invalidArgument(ctx, fieldViolation{field: "scheduled_at", key: service.MsgFieldRequired})
+	}
+	if key, params, ok := s.operation.CheckScheduleTime(req.GetScheduledAt().AsTime()); !ok {
+		return nil, s.invalidArgument(ctx, fieldViolation{field: "scheduled_at", key: key, params: params})
+	}
+	op, err := s.operation.Reschedule(ctx, identity.UserID, opID, req.GetScheduledAt().AsTime())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func (s *Server) RetryOperation(ctx context.Context, req *apipb.RetryOperationRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opID, err := parseOperationName(req.GetName())
+	if err != nil {
+		return nil, s.invalidField(ctx, "name")
+	}
+	op, err := s.operation.Retry(ctx, identity.UserID, opID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func parseOperationName(name string) (uuid.UUID, error) {
+	return uuid.Parse(strings.TrimPrefix(name, operationNamePrefix))
+}
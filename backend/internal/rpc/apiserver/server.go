@@ -0,0 +1,320 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"story2video-backend/internal/auth"
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/rpc/apipb"
+	"story2video-backend/internal/service"
+)
+
+const (
+	batchCreateMaxConcurrency = 3
+	defaultStoryPageSize      = 10
+)
+
+var storyStatuses = map[string]struct{}{
+	global.StoryDraft: {},
+	global.StoryGen:   {},
+	global.StoryReady: {},
+	global.StoryFail:  {},
+	global.StoryFlag:  {},
+}
+
+type Server struct {
+	apipb.UnimplementedStoryServiceServer
+	apipb.UnimplementedOperationServiceServer
+	apipb.UnimplementedBatchServiceServer
+	home          *service.HomeService
+	story         *service.StoryService
+	shot          *service.ShotService
+	operation     *service.OperationService
+	batch         *service.BatchService
+	defaultLocale string
+	logger        *zap.Logger
+}
+
+func NewServer(cfg *conf.Config, home *service.HomeService, story *service.StoryService, shot *service.ShotService, operation *service.OperationService, batch *service.BatchService, logger *zap.Logger) *Server {
+	locale := service.DefaultLocale
+	if cfg != nil {
+		if resolved, ok := service.NormalizeLocale(cfg.I18n.DefaultLocale); ok {
+			locale = resolved
+		}
+	}
+	return &Server{
+		home:          home,
+		story:         story,
+		shot:          shot,
+		operation:     operation,
+		batch:         batch,
+		defaultLocale: locale,
+		logger:        logger,
+	}
+}
+
+func (s *Server) CreateStory(ctx context.Context, req *apipb.CreateStoryRequest) (*apipb.CreateStoryResponse, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	violations := createStoryViolations(req, "")
+	violations = append(violations, s.scheduleViolations("scheduled_at", req.GetScheduledAt())...)
+	if len(violations) > 0 {
+		return nil, s.invalidArgument(ctx, violations...)
+	}
+	result, err := s.home.Create(ctx, identity.UserID, createParams(req))
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return &apipb.CreateStoryResponse{
+		OperationName: result.OperationName,
+		State:         result.State,
+		CreateTime:    timestamppb.New(result.CreateTime),
+	}, nil
+}
+
+func (s *Server) BatchCreateStories(ctx context.Context, req *apipb.BatchCreateStoriesRequest) (*apipb.BatchCreateStoriesResponse, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetItems()) == 0 {
+		return nil, s.invalidArgument(ctx, fieldViolation{field: "items", key: service.MsgFieldRequired})
+	}
+	violations := s.scheduleViolations("scheduled_at", req.GetScheduledAt())
+	params := make([]service.CreateHomeParams, len(req.GetItems()))
+	for idx, item := range req.GetItems() {
+		violations = append(violations, createStoryViolations(item, fmt.Sprintf("items[%d].", idx))...)
+		violations = append(violations, s.scheduleViolations(fmt.Sprintf("items[%d].scheduled_at", idx), item.GetScheduledAt())...)
+		params[idx] = createParams(item)
+		if params[idx].ScheduledAt == nil {
+			params[idx].ScheduledAt = optionalTime(req.GetScheduledAt())
+		}
+	}
+	if len(violations) > 0 {
+		return nil, s.invalidArgument(ctx, violations...)
+	}
+
+	batch, results, err := s.home.CreateBatch(ctx, identity.UserID, params, batchCreateMaxConcurrency)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	locale := s.locale(ctx)
+	items := make([]*apipb.BatchCreateStoryResult, len(results))
+	for idx, item := range results {
+		entry := &apipb.BatchCreateStoryResult{Index: int32(idx), Success: item.Err == nil}
+		if item.Err != nil {
+			if svcErr, ok := service.AsServiceError(item.Err); ok {
+				entry.ErrorCode = string(svcErr.Code)
+				entry.ErrorMessage = svcErr.LocalizedMessage(locale)
+			} else {
+				entry.ErrorMessage = item.Err.Error()
+			}
+		} else if item.Result != nil {
+			entry.OperationName = item.Result.OperationName
+			entry.State = item.Result.State
+			entry.CreateTime = timestamppb.New(item.Result.CreateTime)
+		}
+		items[idx] = entry
+	}
+	resp := &apipb.BatchCreateStoriesResponse{Items: items}
+	if batch != nil {
+		resp.BatchName = batchNamePrefix + batch.ID.String()
+	}
+	return resp, nil
+}
+
+func (s *Server) GetStory(ctx context.Context, req *apipb.GetStoryRequest) (*apipb.Story, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storyID, err := uuid.Parse(req.GetStoryId())
+	if err != nil {
+		return nil, s.invalidField(ctx, "story_id")
+	}
+	story, shots, err := s.story.Get(ctx, identity.UserID, storyID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBStory(story, shots), nil
+}
+
+func (s *Server) ListStories(ctx context.Context, req *apipb.ListStoriesRequest) (*apipb.ListStoriesResponse, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetPageSize() < 0 {
+		return nil, s.invalidField(ctx, "page_size")
+	}
+	for _, status := range req.GetStatuses() {
+		if _, ok := storyStatuses[status]; !ok {
+			return nil, s.invalidField(ctx, "statuses")
+		}
+	}
+	for _, style := range req.GetStyles() {
+		if !service.IsSupportedStyle(style) {
+			return nil, s.invalidField(ctx, "styles")
+		}
+	}
+	sortBy, sortDesc, err := service.NormalizeStorySort(req.GetSortBy(), req.GetOrder())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+
+	opts := service.StoryListOptions{
+		Keyword:  req.GetKeyword(),
+		Query:    strings.TrimSpace(req.GetQuery()),
+		Statuses: req.GetStatuses(),
+		Styles:   req.GetStyles(),
+		SortBy:   sortBy,
+		SortDesc: sortDesc,
+	}
+	legacyToken, err := s.story.ApplyPageToken(&opts, req.GetPageToken())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultStoryPageSize
+	}
+	opts.Limit = pageSize
+	if !legacyToken {
+		opts.Limit = pageSize + 1
+	}
+
+	stories, total, err := s.story.ListStories(ctx, identity.UserID, opts)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	resp := &apipb.ListStoriesResponse{}
+	switch {
+	case legacyToken:
+		if next := opts.Offset + len(stories); int64(next) < total {
+			resp.NextPageToken = strconv.Itoa(next)
+		}
+	case len(stories) > pageSize:
+		stories = stories[:pageSize]
+		resp.NextPageToken = s.story.NextPageToken(stories, opts)
+	}
+	resp.Stories = make([]*apipb.Story, 0, len(stories))
+	for idx := range stories {
+		resp.Stories = append(resp.Stories, toPBStory(&stories[idx], nil))
+	}
+	return resp, nil
+}
+
+func (s *Server) RegenerateShot(ctx context.Context, req *apipb.RegenerateShotRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storyID, err := uuid.Parse(req.GetStoryId())
+	if err != nil {
+		return nil, s.invalidField(ctx, "story_id")
+	}
+	shotID, err := uuid.Parse(req.GetShotId())
+	if err != nil {
+		return nil, s.invalidField(ctx, "shot_id")
+	}
+	op, err := s.shot.UpdateScript(ctx, identity.UserID, storyID, shotID, req.GetDetails())
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func (s *Server) CompileStory(ctx context.Context, req *apipb.CompileStoryRequest) (*apipb.Operation, error) {
+	identity, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	storyID, err := uuid.Parse(req.GetStoryId())
+	if err != nil {
+		return nil, s.invalidField(ctx, "story_id")
+	}
+	op, err := s.shot.RenderStory(ctx, identity.UserID, storyID)
+	if err != nil {
+		return nil, s.toStatus(ctx, err)
+	}
+	return toPBOperation(op, s.statusOptions(ctx, identity)), nil
+}
+
+func (s *Server) identity(ctx context.Context) (auth.Identity, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.Identity{}, s.toStatus(ctx, service.NewServiceError(service.ErrCodeUnauthenticated, ""))
+	}
+	return identity, nil
+}
+
+func (s *Server) locale(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return s.defaultLocale
+	}
+	candidates := md.Get("x-user-locale")
+	for _, header := range md.Get("accept-language") {
+		for _, part := range strings.Split(header, ",") {
+			tag, _, _ := strings.Cut(part, ";")
+			candidates = append(candidates, tag)
+		}
+	}
+	for _, candidate := range candidates {
+		if resolved, ok := service.NormalizeLocale(candidate); ok {
+			return resolved
+		}
+	}
+	return s.defaultLocale
+}
+
+func (s *Server) statusOptions(ctx context.Context, identity auth.Identity) service.StatusOptions {
+	return service.StatusOptions{
+		IncludeDebug: identity.Admin,
+		Locale:       s.locale(ctx),
+	}
+}
+
+func createStoryViolations(req *apipb.CreateStoryRequest, prefix string) []fieldViolation {
+	var violations []fieldViolation
+	if strings.TrimSpace(req.GetDisplayName()) == "" {
+		violations = append(violations, fieldViolation{field: prefix + "display_name", key: service.MsgFieldRequired})
+	}
+	if strings.TrimSpace(req.GetScriptContent()) == "" {
+		violations = append(violations, fieldViolation{field: prefix + "script_content", key: service.MsgFieldRequired})
+	}
+	if strings.TrimSpace(req.GetStyle()) == "" {
+		violations = append(violations, fieldViolation{field: prefix + "style", key: service.MsgFieldRequired})
+	}
+	return violations
+}
+
+func createParams(req *apipb.CreateStoryRequest) service.CreateHomeParams {
+	return service.CreateHomeParams{
+		DisplayName:   req.GetDisplayName(),
+		ScriptContent: req.GetScriptContent(),
+		Style:         req.GetStyle(),
+		ScheduledAt:   optionalTime(req.GetScheduledAt()),
+	}
+}
+
+func (s *Server) scheduleViolations(field string, ts *timestamppb.Timestamp) []fieldViolation {
+	if ts == nil {
+		return nil
+	}
+	if key, params, ok := s.home.CheckScheduleTime(ts.AsTime()); !ok {
+		return []fieldViolation{{field: field, key: key, params: params}}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"story2video-backend/internal/auth"
+)
+
+func AuthInterceptor(authn *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var raw string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(auth.MetadataUserID); len(ids) > 0 {
+				raw = ids[0]
+			}
+		}
+		identity, err := authn.Authenticate(raw)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(auth.WithIdentity(ctx, identity), req)
+	}
+}
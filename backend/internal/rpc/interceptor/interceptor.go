@@ -3,6 +3,7 @@ package interceptor
 import (
 	"context"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,11 +12,17 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+
+	"story2video-backend/internal/metrics"
+	"story2video-backend/internal/tracing"
 )
 
 type traceIDKey struct{}
 
-const traceHeader = "x-trace-id"
+const (
+	traceHeader         = "x-trace-id"
+	healthServicePrefix = "/grpc.health.v1.Health/"
+)
 
 func TraceIDFromContext(ctx context.Context) string {
 	if ctx == nil {
@@ -50,6 +57,17 @@ func LoggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+func MetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.GRPCRequestDuration.
+			WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
 func RecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	if logger == nil {
 		logger = zap.NewNop()
@@ -135,7 +153,13 @@ func RateLimitInterceptor(limiter *RateLimiter) grpc.UnaryServerInterceptor {
 		}
 	}
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
 		if err := limiter.acquire(ctx); err != nil {
+			if status.Code(err) == codes.ResourceExhausted {
+				metrics.GRPCRateLimitRejections.WithLabelValues(info.FullMethod).Inc()
+			}
 			return nil, err
 		}
 		defer limiter.release()
@@ -150,6 +174,9 @@ func ensureTraceID(ctx context.Context) string {
 	if val := TraceIDFromContext(ctx); val != "" {
 		return val
 	}
+	if val := tracing.TraceID(ctx); val != "" {
+		return val
+	}
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		if ids := md.Get(traceHeader); len(ids) > 0 && ids[0] != "" {
 			return ids[0]
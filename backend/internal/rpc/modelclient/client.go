@@ -8,6 +8,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"story2video-backend/internal/conf"
+	"story2video-backend/internal/rpc/interceptor"
 )
 
 type Client struct {
@@ -28,6 +29,7 @@ func New(cfg conf.GRPC) (*Client, func(), error) {
 	conn, err := grpc.DialContext(ctx, cfg.Addr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(interceptor.TracingClientInterceptor()),
 	)
 	cancel()
 	if err != nil {
@@ -22,22 +22,23 @@ const (
 )
 
 type ShotResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShotId        string                 `protobuf:"bytes,1,opt,name=shot_id,json=shotId,proto3" json:"shot_id,omitempty"`
-	Sequence      string                 `protobuf:"bytes,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
-	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Script        string                 `protobuf:"bytes,5,opt,name=script,proto3" json:"script,omitempty"`
-	Details       string                 `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
-	Narration     string                 `protobuf:"bytes,7,opt,name=narration,proto3" json:"narration,omitempty"`
-	Type          string                 `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
-	Transition    string                 `protobuf:"bytes,9,opt,name=transition,proto3" json:"transition,omitempty"`
-	Voice         string                 `protobuf:"bytes,10,opt,name=voice,proto3" json:"voice,omitempty"`
-	ImageUrl      string                 `protobuf:"bytes,11,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
-	Bgm           string                 `protobuf:"bytes,12,opt,name=bgm,proto3" json:"bgm,omitempty"`
-	ImageData     []byte                 `protobuf:"bytes,13,opt,name=image_data,json=imageData,proto3" json:"image_data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ShotId          string                 `protobuf:"bytes,1,opt,name=shot_id,json=shotId,proto3" json:"shot_id,omitempty"`
+	Sequence        string                 `protobuf:"bytes,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Title           string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description     string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Script          string                 `protobuf:"bytes,5,opt,name=script,proto3" json:"script,omitempty"`
+	Details         string                 `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
+	Narration       string                 `protobuf:"bytes,7,opt,name=narration,proto3" json:"narration,omitempty"`
+	Type            string                 `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
+	Transition      string                 `protobuf:"bytes,9,opt,name=transition,proto3" json:"transition,omitempty"`
+	Voice           string                 `protobuf:"bytes,10,opt,name=voice,proto3" json:"voice,omitempty"`
+	ImageUrl        string                 `protobuf:"bytes,11,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Bgm             string                 `protobuf:"bytes,12,opt,name=bgm,proto3" json:"bgm,omitempty"`
+	ImageData       []byte                 `protobuf:"bytes,13,opt,name=image_data,json=imageData,proto3" json:"image_data,omitempty"`
+	AudioDurationMs int64                  `protobuf:"varint,14,opt,name=audio_duration_ms,json=audioDurationMs,proto3" json:"audio_duration_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ShotResult) Reset() {
@@ -161,6 +162,13 @@ func (x *ShotResult) GetImageData() []byte {
 	return nil
 }
 
+func (x *ShotResult) GetAudioDurationMs() int64 {
+	if x != nil {
+		return x.AudioDurationMs
+	}
+	return 0
+}
+
 type CreateStoryboardTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
@@ -417,18 +425,384 @@ func (x *RegenerateShotReply) GetShot() *ShotResult {
 	return nil
 }
 
+type GenerateShotImageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	StoryId       string                 `protobuf:"bytes,2,opt,name=story_id,json=storyId,proto3" json:"story_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ShotId        string                 `protobuf:"bytes,4,opt,name=shot_id,json=shotId,proto3" json:"shot_id,omitempty"`
+	Sequence      string                 `protobuf:"bytes,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Title         string                 `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Prompt        string                 `protobuf:"bytes,7,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	Style         string                 `protobuf:"bytes,8,opt,name=style,proto3" json:"style,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateShotImageRequest) Reset() {
+	*x = GenerateShotImageRequest{}
+	mi := &file_storyboard_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateShotImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateShotImageRequest) ProtoMessage() {}
+
+func (x *GenerateShotImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storyboard_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateShotImageRequest.ProtoReflect.Descriptor instead.
+func (*GenerateShotImageRequest) Descriptor() ([]byte, []int) {
+	return file_storyboard_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GenerateShotImageRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetStoryId() string {
+	if x != nil {
+		return x.StoryId
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetShotId() string {
+	if x != nil {
+		return x.ShotId
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetSequence() string {
+	if x != nil {
+		return x.Sequence
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *GenerateShotImageRequest) GetStyle() string {
+	if x != nil {
+		return x.Style
+	}
+	return ""
+}
+
+type GenerateShotImageReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ImageUrl      string                 `protobuf:"bytes,1,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	ImageData     []byte                 `protobuf:"bytes,2,opt,name=image_data,json=imageData,proto3" json:"image_data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateShotImageReply) Reset() {
+	*x = GenerateShotImageReply{}
+	mi := &file_storyboard_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateShotImageReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateShotImageReply) ProtoMessage() {}
+
+func (x *GenerateShotImageReply) ProtoReflect() protoreflect.Message {
+	mi := &file_storyboard_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateShotImageReply.ProtoReflect.Descriptor instead.
+func (*GenerateShotImageReply) Descriptor() ([]byte, []int) {
+	return file_storyboard_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GenerateShotImageReply) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *GenerateShotImageReply) GetImageData() []byte {
+	if x != nil {
+		return x.ImageData
+	}
+	return nil
+}
+
+type SynthesizeShotAudioRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	StoryId       string                 `protobuf:"bytes,2,opt,name=story_id,json=storyId,proto3" json:"story_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ShotId        string                 `protobuf:"bytes,4,opt,name=shot_id,json=shotId,proto3" json:"shot_id,omitempty"`
+	Sequence      string                 `protobuf:"bytes,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Text          string                 `protobuf:"bytes,6,opt,name=text,proto3" json:"text,omitempty"`
+	Voice         string                 `protobuf:"bytes,7,opt,name=voice,proto3" json:"voice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SynthesizeShotAudioRequest) Reset() {
+	*x = SynthesizeShotAudioRequest{}
+	mi := &file_storyboard_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeShotAudioRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeShotAudioRequest) ProtoMessage() {}
+
+func (x *SynthesizeShotAudioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storyboard_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeShotAudioRequest.ProtoReflect.Descriptor instead.
+func (*SynthesizeShotAudioRequest) Descriptor() ([]byte, []int) {
+	return file_storyboard_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SynthesizeShotAudioRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetStoryId() string {
+	if x != nil {
+		return x.StoryId
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetShotId() string {
+	if x != nil {
+		return x.ShotId
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetSequence() string {
+	if x != nil {
+		return x.Sequence
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioRequest) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+type SynthesizeShotAudioReply struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AudioUrl        string                 `protobuf:"bytes,1,opt,name=audio_url,json=audioUrl,proto3" json:"audio_url,omitempty"`
+	AudioDurationMs int64                  `protobuf:"varint,2,opt,name=audio_duration_ms,json=audioDurationMs,proto3" json:"audio_duration_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SynthesizeShotAudioReply) Reset() {
+	*x = SynthesizeShotAudioReply{}
+	mi := &file_storyboard_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SynthesizeShotAudioReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SynthesizeShotAudioReply) ProtoMessage() {}
+
+func (x *SynthesizeShotAudioReply) ProtoReflect() protoreflect.Message {
+	mi := &file_storyboard_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SynthesizeShotAudioReply.ProtoReflect.Descriptor instead.
+func (*SynthesizeShotAudioReply) Descriptor() ([]byte, []int) {
+	return file_storyboard_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SynthesizeShotAudioReply) GetAudioUrl() string {
+	if x != nil {
+		return x.AudioUrl
+	}
+	return ""
+}
+
+func (x *SynthesizeShotAudioReply) GetAudioDurationMs() int64 {
+	if x != nil {
+		return x.AudioDurationMs
+	}
+	return 0
+}
+
+type RenderShotAudio struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShotId        string                 `protobuf:"bytes,1,opt,name=shot_id,json=shotId,proto3" json:"shot_id,omitempty"`
+	Sequence      string                 `protobuf:"bytes,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Narration     string                 `protobuf:"bytes,3,opt,name=narration,proto3" json:"narration,omitempty"`
+	AudioUrl      string                 `protobuf:"bytes,4,opt,name=audio_url,json=audioUrl,proto3" json:"audio_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderShotAudio) Reset() {
+	*x = RenderShotAudio{}
+	mi := &file_storyboard_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderShotAudio) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderShotAudio) ProtoMessage() {}
+
+func (x *RenderShotAudio) ProtoReflect() protoreflect.Message {
+	mi := &file_storyboard_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderShotAudio.ProtoReflect.Descriptor instead.
+func (*RenderShotAudio) Descriptor() ([]byte, []int) {
+	return file_storyboard_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RenderShotAudio) GetShotId() string {
+	if x != nil {
+		return x.ShotId
+	}
+	return ""
+}
+
+func (x *RenderShotAudio) GetSequence() string {
+	if x != nil {
+		return x.Sequence
+	}
+	return ""
+}
+
+func (x *RenderShotAudio) GetNarration() string {
+	if x != nil {
+		return x.Narration
+	}
+	return ""
+}
+
+func (x *RenderShotAudio) GetAudioUrl() string {
+	if x != nil {
+		return x.AudioUrl
+	}
+	return ""
+}
+
 type RenderVideoRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
 	StoryId       string                 `protobuf:"bytes,2,opt,name=story_id,json=storyId,proto3" json:"story_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SubtitlesSrt  string                 `protobuf:"bytes,4,opt,name=subtitles_srt,json=subtitlesSrt,proto3" json:"subtitles_srt,omitempty"`
+	Shots         []*RenderShotAudio     `protobuf:"bytes,5,rep,name=shots,proto3" json:"shots,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RenderVideoRequest) Reset() {
 	*x = RenderVideoRequest{}
-	mi := &file_storyboard_proto_msgTypes[5]
+	mi := &file_storyboard_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -440,7 +814,7 @@ func (x *RenderVideoRequest) String() string {
 func (*RenderVideoRequest) ProtoMessage() {}
 
 func (x *RenderVideoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_storyboard_proto_msgTypes[5]
+	mi := &file_storyboard_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -453,7 +827,7 @@ func (x *RenderVideoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RenderVideoRequest.ProtoReflect.Descriptor instead.
 func (*RenderVideoRequest) Descriptor() ([]byte, []int) {
-	return file_storyboard_proto_rawDescGZIP(), []int{5}
+	return file_storyboard_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RenderVideoRequest) GetOperationId() string {
@@ -477,6 +851,20 @@ func (x *RenderVideoRequest) GetUserId() string {
 	return ""
 }
 
+func (x *RenderVideoRequest) GetSubtitlesSrt() string {
+	if x != nil {
+		return x.SubtitlesSrt
+	}
+	return ""
+}
+
+func (x *RenderVideoRequest) GetShots() []*RenderShotAudio {
+	if x != nil {
+		return x.Shots
+	}
+	return nil
+}
+
 type RenderVideoReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	VideoUrl      string                 `protobuf:"bytes,1,opt,name=video_url,json=videoUrl,proto3" json:"video_url,omitempty"`
@@ -487,7 +875,7 @@ type RenderVideoReply struct {
 
 func (x *RenderVideoReply) Reset() {
 	*x = RenderVideoReply{}
-	mi := &file_storyboard_proto_msgTypes[6]
+	mi := &file_storyboard_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -499,7 +887,7 @@ func (x *RenderVideoReply) String() string {
 func (*RenderVideoReply) ProtoMessage() {}
 
 func (x *RenderVideoReply) ProtoReflect() protoreflect.Message {
-	mi := &file_storyboard_proto_msgTypes[6]
+	mi := &file_storyboard_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -512,7 +900,7 @@ func (x *RenderVideoReply) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RenderVideoReply.ProtoReflect.Descriptor instead.
 func (*RenderVideoReply) Descriptor() ([]byte, []int) {
-	return file_storyboard_proto_rawDescGZIP(), []int{6}
+	return file_storyboard_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RenderVideoReply) GetVideoUrl() string {
@@ -533,7 +921,7 @@ var File_storyboard_proto protoreflect.FileDescriptor
 
 const file_storyboard_proto_rawDesc = "" +
 	"\n" +
-	"\x10storyboard.proto\x12\rstoryboard.v1\"\xe1\x02\n" +
+	"\x10storyboard.proto\x12\rstoryboard.v1\"\x8d\x03\n" +
 	"\n" +
 	"ShotResult\x12\x17\n" +
 	"\ashot_id\x18\x01 \x01(\tR\x06shotId\x12\x1a\n" +
@@ -552,7 +940,8 @@ const file_storyboard_proto_rawDesc = "" +
 	"\timage_url\x18\v \x01(\tR\bimageUrl\x12\x10\n" +
 	"\x03bgm\x18\f \x01(\tR\x03bgm\x12\x1d\n" +
 	"\n" +
-	"image_data\x18\r \x01(\fR\timageData\"\xd4\x01\n" +
+	"image_data\x18\r \x01(\fR\timageData\x12*\n" +
+	"\x11audio_duration_ms\x18\x0e \x01(\x03R\x0faudioDurationMs\"\xd4\x01\n" +
 	"\x1bCreateStoryboardTaskRequest\x12!\n" +
 	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x19\n" +
 	"\bstory_id\x18\x02 \x01(\tR\astoryId\x12\x17\n" +
@@ -570,19 +959,53 @@ const file_storyboard_proto_rawDesc = "" +
 	"\x05style\x18\x05 \x01(\tR\x05style\x12\x17\n" +
 	"\auser_id\x18\x06 \x01(\tR\x06userId\"D\n" +
 	"\x13RegenerateShotReply\x12-\n" +
-	"\x04shot\x18\x01 \x01(\v2\x19.storyboard.v1.ShotResultR\x04shot\"k\n" +
+	"\x04shot\x18\x01 \x01(\v2\x19.storyboard.v1.ShotResultR\x04shot\"\xea\x01\n" +
+	"\x18GenerateShotImageRequest\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x19\n" +
+	"\bstory_id\x18\x02 \x01(\tR\astoryId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x17\n" +
+	"\ashot_id\x18\x04 \x01(\tR\x06shotId\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\tR\bsequence\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12\x16\n" +
+	"\x06prompt\x18\a \x01(\tR\x06prompt\x12\x14\n" +
+	"\x05style\x18\b \x01(\tR\x05style\"T\n" +
+	"\x16GenerateShotImageReply\x12\x1b\n" +
+	"\timage_url\x18\x01 \x01(\tR\bimageUrl\x12\x1d\n" +
+	"\n" +
+	"image_data\x18\x02 \x01(\fR\timageData\"\xd2\x01\n" +
+	"\x1aSynthesizeShotAudioRequest\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x19\n" +
+	"\bstory_id\x18\x02 \x01(\tR\astoryId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x17\n" +
+	"\ashot_id\x18\x04 \x01(\tR\x06shotId\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\tR\bsequence\x12\x12\n" +
+	"\x04text\x18\x06 \x01(\tR\x04text\x12\x14\n" +
+	"\x05voice\x18\a \x01(\tR\x05voice\"c\n" +
+	"\x18SynthesizeShotAudioReply\x12\x1b\n" +
+	"\taudio_url\x18\x01 \x01(\tR\baudioUrl\x12*\n" +
+	"\x11audio_duration_ms\x18\x02 \x01(\x03R\x0faudioDurationMs\"\x81\x01\n" +
+	"\x0fRenderShotAudio\x12\x17\n" +
+	"\ashot_id\x18\x01 \x01(\tR\x06shotId\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\tR\bsequence\x12\x1c\n" +
+	"\tnarration\x18\x03 \x01(\tR\tnarration\x12\x1b\n" +
+	"\taudio_url\x18\x04 \x01(\tR\baudioUrl\"\xc6\x01\n" +
 	"\x12RenderVideoRequest\x12!\n" +
 	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x19\n" +
 	"\bstory_id\x18\x02 \x01(\tR\astoryId\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\"N\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12#\n" +
+	"\rsubtitles_srt\x18\x04 \x01(\tR\fsubtitlesSrt\x124\n" +
+	"\x05shots\x18\x05 \x03(\v2\x1e.storyboard.v1.RenderShotAudioR\x05shots\"N\n" +
 	"\x10RenderVideoReply\x12\x1b\n" +
 	"\tvideo_url\x18\x01 \x01(\tR\bvideoUrl\x12\x1d\n" +
 	"\n" +
-	"video_data\x18\x02 \x01(\fR\tvideoData2\xa6\x02\n" +
+	"video_data\x18\x02 \x01(\fR\tvideoData2\xd3\x04\n" +
 	"\x11StoryboardService\x12b\n" +
 	"\x14CreateStoryboardTask\x12*.storyboard.v1.CreateStoryboardTaskRequest\x1a\x1e.storyboard.v1.StoryboardReply\x12Z\n" +
 	"\x0eRegenerateShot\x12$.storyboard.v1.RegenerateShotRequest\x1a\".storyboard.v1.RegenerateShotReply\x12Q\n" +
-	"\vRenderVideo\x12!.storyboard.v1.RenderVideoRequest\x1a\x1f.storyboard.v1.RenderVideoReplyB2Z0story2video-backend/internal/rpc/modelpb;modelpbb\x06proto3"
+	"\vRenderVideo\x12!.storyboard.v1.RenderVideoRequest\x1a\x1f.storyboard.v1.RenderVideoReply\x12[\n" +
+	"\rGenerateShots\x12*.storyboard.v1.CreateStoryboardTaskRequest\x1a\x1e.storyboard.v1.StoryboardReply\x12c\n" +
+	"\x11GenerateShotImage\x12'.storyboard.v1.GenerateShotImageRequest\x1a%.storyboard.v1.GenerateShotImageReply\x12i\n" +
+	"\x13SynthesizeShotAudio\x12).storyboard.v1.SynthesizeShotAudioRequest\x1a'.storyboard.v1.SynthesizeShotAudioReplyB2Z0story2video-backend/internal/rpc/modelpb;modelpbb\x06proto3"
 
 var (
 	file_storyboard_proto_rawDescOnce sync.Once
@@ -596,30 +1019,42 @@ func file_storyboard_proto_rawDescGZIP() []byte {
 	return file_storyboard_proto_rawDescData
 }
 
-var file_storyboard_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_storyboard_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_storyboard_proto_goTypes = []any{
 	(*ShotResult)(nil),                  // 0: storyboard.v1.ShotResult
 	(*CreateStoryboardTaskRequest)(nil), // 1: storyboard.v1.CreateStoryboardTaskRequest
 	(*StoryboardReply)(nil),             // 2: storyboard.v1.StoryboardReply
 	(*RegenerateShotRequest)(nil),       // 3: storyboard.v1.RegenerateShotRequest
 	(*RegenerateShotReply)(nil),         // 4: storyboard.v1.RegenerateShotReply
-	(*RenderVideoRequest)(nil),          // 5: storyboard.v1.RenderVideoRequest
-	(*RenderVideoReply)(nil),            // 6: storyboard.v1.RenderVideoReply
+	(*GenerateShotImageRequest)(nil),    // 5: storyboard.v1.GenerateShotImageRequest
+	(*GenerateShotImageReply)(nil),      // 6: storyboard.v1.GenerateShotImageReply
+	(*SynthesizeShotAudioRequest)(nil),  // 7: storyboard.v1.SynthesizeShotAudioRequest
+	(*SynthesizeShotAudioReply)(nil),    // 8: storyboard.v1.SynthesizeShotAudioReply
+	(*RenderShotAudio)(nil),             // 9: storyboard.v1.RenderShotAudio
+	(*RenderVideoRequest)(nil),          // 10: storyboard.v1.RenderVideoRequest
+	(*RenderVideoReply)(nil),            // 11: storyboard.v1.RenderVideoReply
 }
 var file_storyboard_proto_depIdxs = []int32{
-	0, // 0: storyboard.v1.StoryboardReply.shots:type_name -> storyboard.v1.ShotResult
-	0, // 1: storyboard.v1.RegenerateShotReply.shot:type_name -> storyboard.v1.ShotResult
-	1, // 2: storyboard.v1.StoryboardService.CreateStoryboardTask:input_type -> storyboard.v1.CreateStoryboardTaskRequest
-	3, // 3: storyboard.v1.StoryboardService.RegenerateShot:input_type -> storyboard.v1.RegenerateShotRequest
-	5, // 4: storyboard.v1.StoryboardService.RenderVideo:input_type -> storyboard.v1.RenderVideoRequest
-	2, // 5: storyboard.v1.StoryboardService.CreateStoryboardTask:output_type -> storyboard.v1.StoryboardReply
-	4, // 6: storyboard.v1.StoryboardService.RegenerateShot:output_type -> storyboard.v1.RegenerateShotReply
-	6, // 7: storyboard.v1.StoryboardService.RenderVideo:output_type -> storyboard.v1.RenderVideoReply
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: storyboard.v1.StoryboardReply.shots:type_name -> storyboard.v1.ShotResult
+	0,  // 1: storyboard.v1.RegenerateShotReply.shot:type_name -> storyboard.v1.ShotResult
+	9,  // 2: storyboard.v1.RenderVideoRequest.shots:type_name -> storyboard.v1.RenderShotAudio
+	1,  // 3: storyboard.v1.StoryboardService.CreateStoryboardTask:input_type -> storyboard.v1.CreateStoryboardTaskRequest
+	3,  // 4: storyboard.v1.StoryboardService.RegenerateShot:input_type -> storyboard.v1.RegenerateShotRequest
+	10, // 5: storyboard.v1.StoryboardService.RenderVideo:input_type -> storyboard.v1.RenderVideoRequest
+	1,  // 6: storyboard.v1.StoryboardService.GenerateShots:input_type -> storyboard.v1.CreateStoryboardTaskRequest
+	5,  // 7: storyboard.v1.StoryboardService.GenerateShotImage:input_type -> storyboard.v1.GenerateShotImageRequest
+	7,  // 8: storyboard.v1.StoryboardService.SynthesizeShotAudio:input_type -> storyboard.v1.SynthesizeShotAudioRequest
+	2,  // 9: storyboard.v1.StoryboardService.CreateStoryboardTask:output_type -> storyboard.v1.StoryboardReply
+	4,  // 10: storyboard.v1.StoryboardService.RegenerateShot:output_type -> storyboard.v1.RegenerateShotReply
+	11, // 11: storyboard.v1.StoryboardService.RenderVideo:output_type -> storyboard.v1.RenderVideoReply
+	2,  // 12: storyboard.v1.StoryboardService.GenerateShots:output_type -> storyboard.v1.StoryboardReply
+	6,  // 13: storyboard.v1.StoryboardService.GenerateShotImage:output_type -> storyboard.v1.GenerateShotImageReply
+	8,  // 14: storyboard.v1.StoryboardService.SynthesizeShotAudio:output_type -> storyboard.v1.SynthesizeShotAudioReply
+	9,  // [9:15] is the sub-list for method output_type
+	3,  // [3:9] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_storyboard_proto_init() }
@@ -633,7 +1068,7 @@ func file_storyboard_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_storyboard_proto_rawDesc), len(file_storyboard_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
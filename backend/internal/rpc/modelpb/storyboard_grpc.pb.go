@@ -22,6 +22,9 @@ const (
 	StoryboardService_CreateStoryboardTask_FullMethodName = "/storyboard.v1.StoryboardService/CreateStoryboardTask"
 	StoryboardService_RegenerateShot_FullMethodName       = "/storyboard.v1.StoryboardService/RegenerateShot"
 	StoryboardService_RenderVideo_FullMethodName          = "/storyboard.v1.StoryboardService/RenderVideo"
+	StoryboardService_GenerateShots_FullMethodName        = "/storyboard.v1.StoryboardService/GenerateShots"
+	StoryboardService_GenerateShotImage_FullMethodName    = "/storyboard.v1.StoryboardService/GenerateShotImage"
+	StoryboardService_SynthesizeShotAudio_FullMethodName  = "/storyboard.v1.StoryboardService/SynthesizeShotAudio"
 )
 
 // StoryboardServiceClient is the client API for StoryboardService service.
@@ -31,6 +34,9 @@ type StoryboardServiceClient interface {
 	CreateStoryboardTask(ctx context.Context, in *CreateStoryboardTaskRequest, opts ...grpc.CallOption) (*StoryboardReply, error)
 	RegenerateShot(ctx context.Context, in *RegenerateShotRequest, opts ...grpc.CallOption) (*RegenerateShotReply, error)
 	RenderVideo(ctx context.Context, in *RenderVideoRequest, opts ...grpc.CallOption) (*RenderVideoReply, error)
+	GenerateShots(ctx context.Context, in *CreateStoryboardTaskRequest, opts ...grpc.CallOption) (*StoryboardReply, error)
+	GenerateShotImage(ctx context.Context, in *GenerateShotImageRequest, opts ...grpc.CallOption) (*GenerateShotImageReply, error)
+	SynthesizeShotAudio(ctx context.Context, in *SynthesizeShotAudioRequest, opts ...grpc.CallOption) (*SynthesizeShotAudioReply, error)
 }
 
 type storyboardServiceClient struct {
@@ -71,6 +77,36 @@ func (c *storyboardServiceClient) RenderVideo(ctx context.Context, in *RenderVid
 	return out, nil
 }
 
+func (c *storyboardServiceClient) GenerateShots(ctx context.Context, in *CreateStoryboardTaskRequest, opts ...grpc.CallOption) (*StoryboardReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoryboardReply)
+	err := c.cc.Invoke(ctx, StoryboardService_GenerateShots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storyboardServiceClient) GenerateShotImage(ctx context.Context, in *GenerateShotImageRequest, opts ...grpc.CallOption) (*GenerateShotImageReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateShotImageReply)
+	err := c.cc.Invoke(ctx, StoryboardService_GenerateShotImage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storyboardServiceClient) SynthesizeShotAudio(ctx context.Context, in *SynthesizeShotAudioRequest, opts ...grpc.CallOption) (*SynthesizeShotAudioReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SynthesizeShotAudioReply)
+	err := c.cc.Invoke(ctx, StoryboardService_SynthesizeShotAudio_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoryboardServiceServer is the server API for StoryboardService service.
 // All implementations must embed UnimplementedStoryboardServiceServer
 // for forward compatibility.
@@ -78,6 +114,9 @@ type StoryboardServiceServer interface {
 	CreateStoryboardTask(context.Context, *CreateStoryboardTaskRequest) (*StoryboardReply, error)
 	RegenerateShot(context.Context, *RegenerateShotRequest) (*RegenerateShotReply, error)
 	RenderVideo(context.Context, *RenderVideoRequest) (*RenderVideoReply, error)
+	GenerateShots(context.Context, *CreateStoryboardTaskRequest) (*StoryboardReply, error)
+	GenerateShotImage(context.Context, *GenerateShotImageRequest) (*GenerateShotImageReply, error)
+	SynthesizeShotAudio(context.Context, *SynthesizeShotAudioRequest) (*SynthesizeShotAudioReply, error)
 	mustEmbedUnimplementedStoryboardServiceServer()
 }
 
@@ -97,6 +136,15 @@ func (UnimplementedStoryboardServiceServer) RegenerateShot(context.Context, *Reg
 func (UnimplementedStoryboardServiceServer) RenderVideo(context.Context, *RenderVideoRequest) (*RenderVideoReply, error) {
 	return nil, status.Error(codes.Unimplemented, "method RenderVideo not implemented")
 }
+func (UnimplementedStoryboardServiceServer) GenerateShots(context.Context, *CreateStoryboardTaskRequest) (*StoryboardReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateShots not implemented")
+}
+func (UnimplementedStoryboardServiceServer) GenerateShotImage(context.Context, *GenerateShotImageRequest) (*GenerateShotImageReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method GenerateShotImage not implemented")
+}
+func (UnimplementedStoryboardServiceServer) SynthesizeShotAudio(context.Context, *SynthesizeShotAudioRequest) (*SynthesizeShotAudioReply, error) {
+	return nil, status.Error(codes.Unimplemented, "method SynthesizeShotAudio not implemented")
+}
 func (UnimplementedStoryboardServiceServer) mustEmbedUnimplementedStoryboardServiceServer() {}
 func (UnimplementedStoryboardServiceServer) testEmbeddedByValue()                           {}
 
@@ -172,6 +220,60 @@ func _StoryboardService_RenderVideo_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StoryboardService_GenerateShots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStoryboardTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoryboardServiceServer).GenerateShots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoryboardService_GenerateShots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoryboardServiceServer).GenerateShots(ctx, req.(*CreateStoryboardTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoryboardService_GenerateShotImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateShotImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoryboardServiceServer).GenerateShotImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoryboardService_GenerateShotImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoryboardServiceServer).GenerateShotImage(ctx, req.(*GenerateShotImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoryboardService_SynthesizeShotAudio_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SynthesizeShotAudioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoryboardServiceServer).SynthesizeShotAudio(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoryboardService_SynthesizeShotAudio_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoryboardServiceServer).SynthesizeShotAudio(ctx, req.(*SynthesizeShotAudioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StoryboardService_ServiceDesc is the grpc.ServiceDesc for StoryboardService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +293,18 @@ var StoryboardService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RenderVideo",
 			Handler:    _StoryboardService_RenderVideo_Handler,
 		},
+		{
+			MethodName: "GenerateShots",
+			Handler:    _StoryboardService_GenerateShots_Handler,
+		},
+		{
+			MethodName: "GenerateShotImage",
+			Handler:    _StoryboardService_GenerateShotImage_Handler,
+		},
+		{
+			MethodName: "SynthesizeShotAudio",
+			Handler:    _StoryboardService_SynthesizeShotAudio_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "storyboard.proto",
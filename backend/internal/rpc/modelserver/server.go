@@ -12,12 +12,18 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"story2video-backend/internal/conf"
+	"story2video-backend/internal/metrics"
 	"story2video-backend/internal/rpc/modelpb"
+	"story2video-backend/internal/tracing"
 )
 
 type Server struct {
@@ -91,11 +97,26 @@ func (s *Server) RegenerateShot(ctx context.Context, req *modelpb.RegenerateShot
 }
 
 func (s *Server) RenderVideo(ctx context.Context, req *modelpb.RenderVideoRequest) (*modelpb.RenderVideoReply, error) {
-	payload := map[string]string{
+	payload := map[string]any{
 		"operation_id": req.OperationId,
 		"story_id":     req.StoryId,
 		"user_id":      req.UserId,
 	}
+	if req.SubtitlesSrt != "" {
+		payload["subtitles_srt"] = req.SubtitlesSrt
+	}
+	if len(req.Shots) > 0 {
+		shots := make([]map[string]any, 0, len(req.Shots))
+		for _, shot := range req.Shots {
+			shots = append(shots, map[string]any{
+				"shot_id":   shot.ShotId,
+				"sequence":  sequenceNumber(shot.Sequence),
+				"narration": shot.Narration,
+				"audio_url": shot.AudioUrl,
+			})
+		}
+		payload["shots"] = shots
+	}
 
 	var resp renderVideoResponse
 	if err := s.post(ctx, "/api/v1/video/render", payload, &resp); err != nil {
@@ -110,23 +131,111 @@ func (s *Server) RenderVideo(ctx context.Context, req *modelpb.RenderVideoReques
 	}, nil
 }
 
+func (s *Server) GenerateShots(ctx context.Context, req *modelpb.CreateStoryboardTaskRequest) (*modelpb.StoryboardReply, error) {
+	payload := map[string]string{
+		"operation_id":   req.OperationId,
+		"story_id":       req.StoryId,
+		"user_id":        req.UserId,
+		"display_name":   req.DisplayName,
+		"script_content": req.ScriptContent,
+		"style":          req.Style,
+	}
+
+	var resp storyboardCreateResponse
+	if err := s.post(ctx, "/api/v1/storyboard/shots", payload, &resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "generate shots: %v", err)
+	}
+
+	shots := make([]*modelpb.ShotResult, 0, len(resp.Shots))
+	for _, shot := range resp.Shots {
+		shots = append(shots, convertShot(shot, s.logger))
+	}
+
+	return &modelpb.StoryboardReply{
+		Shots: shots,
+	}, nil
+}
+
+func (s *Server) GenerateShotImage(ctx context.Context, req *modelpb.GenerateShotImageRequest) (*modelpb.GenerateShotImageReply, error) {
+	payload := map[string]any{
+		"operation_id": req.OperationId,
+		"story_id":     req.StoryId,
+		"user_id":      req.UserId,
+		"shot_id":      req.ShotId,
+		"sequence":     sequenceNumber(req.Sequence),
+		"subject":      req.Title,
+		"prompt":       req.Prompt,
+		"style":        req.Style,
+	}
+
+	var resp shotImageResponse
+	if err := s.post(ctx, "/api/v1/shot/image", payload, &resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "generate shot image: %v", err)
+	}
+
+	return &modelpb.GenerateShotImageReply{
+		ImageUrl:  resp.ImageURL,
+		ImageData: decodeBase64(resp.ImageData),
+	}, nil
+}
+
+func (s *Server) SynthesizeShotAudio(ctx context.Context, req *modelpb.SynthesizeShotAudioRequest) (*modelpb.SynthesizeShotAudioReply, error) {
+	payload := map[string]any{
+		"operation_id": req.OperationId,
+		"story_id":     req.StoryId,
+		"user_id":      req.UserId,
+		"shot_id":      req.ShotId,
+		"sequence":     sequenceNumber(req.Sequence),
+		"text":         req.Text,
+		"voice":        req.Voice,
+	}
+
+	var resp shotAudioResponse
+	if err := s.post(ctx, "/api/v1/shot/audio", payload, &resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "synthesize shot audio: %v", err)
+	}
+
+	return &modelpb.SynthesizeShotAudioReply{
+		AudioUrl:        resp.AudioURL,
+		AudioDurationMs: int64(resp.AudioDuration * 1000),
+	}, nil
+}
+
 func (s *Server) post(ctx context.Context, path string, payload any, out any) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("encode payload: %w", err)
 	}
 
+	ctx, span := tracing.Tracer().Start(ctx, http.MethodPost+" "+path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodPost),
+			attribute.String("url.full", s.baseURL+path),
+		),
+	)
+	defer span.End()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	res, err := s.client.Do(req)
 	if err != nil {
+		metrics.ModelServiceResponses.WithLabelValues(path, metrics.ResultError).Inc()
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
 		return fmt.Errorf("request model service: %w", err)
 	}
 	defer res.Body.Close()
+	metrics.ModelServiceResponses.WithLabelValues(path, strconv.Itoa(res.StatusCode)).Inc()
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(otelcodes.Error, http.StatusText(res.StatusCode))
+	}
 
 	if res.StatusCode >= http.StatusBadRequest {
 		content, _ := io.ReadAll(res.Body)
@@ -160,6 +269,18 @@ type renderVideoResponse struct {
 	VideoData string       `json:"video_data"`
 }
 
+type shotImageResponse struct {
+	Operation apiOperation `json:"operation"`
+	ImageURL  string       `json:"image_url"`
+	ImageData string       `json:"image_data"`
+}
+
+type shotAudioResponse struct {
+	Operation     apiOperation `json:"operation"`
+	AudioURL      string       `json:"audio_url"`
+	AudioDuration float64      `json:"audio_duration"`
+}
+
 type apiOperation struct {
 	OperationID string `json:"operation_id"`
 	Status      string `json:"status"`
@@ -167,26 +288,27 @@ type apiOperation struct {
 }
 
 type apiShot struct {
-	ID          string      `json:"id"`
-	ShotID      string      `json:"shot_id"`
-	Sequence    interface{} `json:"sequence"`
-	Subject     string      `json:"subject"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Script      string      `json:"script"`
-	Detail      string      `json:"detail"`
-	Details     string      `json:"details"`
-	Camera      string      `json:"camera"`
-	Type        string      `json:"type"`
-	Transition  string      `json:"transition"`
-	Voice       string      `json:"voice"`
-	Tone        string      `json:"tone"`
-	Narration   string      `json:"narration"`
-	BGM         string      `json:"bgm"`
-	ImageURL    string      `json:"image_url"`
-	ImagePath   string      `json:"image_path"`
-	ImageBase64 string      `json:"image_base64"`
-	ImageData   string      `json:"image_data"`
+	ID            string      `json:"id"`
+	ShotID        string      `json:"shot_id"`
+	Sequence      interface{} `json:"sequence"`
+	Subject       string      `json:"subject"`
+	Title         string      `json:"title"`
+	Description   string      `json:"description"`
+	Script        string      `json:"script"`
+	Detail        string      `json:"detail"`
+	Details       string      `json:"details"`
+	Camera        string      `json:"camera"`
+	Type          string      `json:"type"`
+	Transition    string      `json:"transition"`
+	Voice         string      `json:"voice"`
+	Tone          string      `json:"tone"`
+	Narration     string      `json:"narration"`
+	BGM           string      `json:"bgm"`
+	ImageURL      string      `json:"image_url"`
+	ImagePath     string      `json:"image_path"`
+	ImageBase64   string      `json:"image_base64"`
+	ImageData     string      `json:"image_data"`
+	AudioDuration float64     `json:"audio_duration"`
 }
 
 func convertShot(shot apiShot, logger *zap.Logger) *modelpb.ShotResult {
@@ -208,18 +330,19 @@ func convertShot(shot apiShot, logger *zap.Logger) *modelpb.ShotResult {
 	imageURL := firstNonEmpty(shot.ImageURL, shot.ImagePath)
 
 	return &modelpb.ShotResult{
-		ShotId:      shotID,
-		Sequence:    sequence,
-		Title:       title,
-		Description: description,
-		Script:      firstNonEmpty(shot.Script, detail),
-		Details:     detail,
-		Narration:   narration,
-		Type:        shotType,
-		Transition:  shot.Transition,
-		Voice:       voice,
-		ImageUrl:    imageURL,
-		Bgm:         shot.BGM,
+		ShotId:          shotID,
+		Sequence:        sequence,
+		Title:           title,
+		Description:     description,
+		Script:          firstNonEmpty(shot.Script, detail),
+		Details:         detail,
+		Narration:       narration,
+		Type:            shotType,
+		Transition:      shot.Transition,
+		Voice:           voice,
+		ImageUrl:        imageURL,
+		Bgm:             shot.BGM,
+		AudioDurationMs: int64(shot.AudioDuration * 1000),
 	}
 }
 
@@ -239,6 +362,14 @@ func normalizeSequence(seq interface{}) string {
 	return ""
 }
 
+func sequenceNumber(sequence string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(sequence))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
 		if strings.TrimSpace(v) != "" {
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"story2video-backend/internal/conf"
+)
+
+const maxAssetRedirects = 3
+
+var (
+	errAssetHostNotAllowed = errors.New("asset host not allowed")
+	errAssetAddrNotAllowed = errors.New("asset address not allowed")
+	sharedAddressSpace     = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+)
+
+type assetHostPolicy struct {
+	hosts map[string]struct{}
+}
+
+func newAssetHostPolicy(cfg *conf.Config) *assetHostPolicy {
+	policy := &assetHostPolicy{hosts: make(map[string]struct{})}
+	if cfg == nil {
+		return policy
+	}
+	for _, host := range cfg.Export.AllowedAssetHosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			policy.hosts[host] = struct{}{}
+		}
+	}
+	return policy
+}
+
+func (p *assetHostPolicy) Allows(raw string) (*url.URL, bool) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User != nil {
+		return nil, false
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, false
+	}
+	if _, ok := p.hosts[strings.ToLower(parsed.Hostname())]; !ok {
+		return nil, false
+	}
+	return parsed, true
+}
+
+func newGuardedAssetClient(policy *assetHostPolicy, timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicAssetIP(ip) {
+				return fmt.Errorf("%w: %s", errAssetAddrNotAllowed, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxAssetRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxAssetRedirects)
+			}
+			if _, ok := policy.Allows(req.URL.String()); !ok {
+				return fmt.Errorf("%w: %s", errAssetHostNotAllowed, req.URL.Host)
+			}
+			return nil
+		},
+	}
+}
+
+func isPublicAssetIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
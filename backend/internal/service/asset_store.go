@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"story2video-backend/internal/conf"
+)
+
+const (
+	defaultAssetDir       = "data/assets"
+	defaultAssetURLPrefix = "/assets"
+	defaultAssetMaxBytes  = 10 << 20
+)
+
+type AssetStore struct {
+	dir       string
+	urlPrefix string
+	maxBytes  int64
+}
+
+func NewAssetStore(cfg *conf.Config) *AssetStore {
+	store := &AssetStore{
+		dir:       defaultAssetDir,
+		urlPrefix: defaultAssetURLPrefix,
+		maxBytes:  defaultAssetMaxBytes,
+	}
+	if cfg != nil {
+		if strings.TrimSpace(cfg.Storage.AssetDir) != "" {
+			store.dir = cfg.Storage.AssetDir
+		}
+		if prefix := strings.TrimRight(strings.TrimSpace(cfg.Storage.AssetURLPrefix), "/"); prefix != "" {
+			store.urlPrefix = prefix
+		}
+		if cfg.Storage.MaxAssetBytes > 0 {
+			store.maxBytes = cfg.Storage.MaxAssetBytes
+		}
+	}
+	return store
+}
+
+func (s *AssetStore) Dir() string {
+	return s.dir
+}
+
+func (s *AssetStore) URLPrefix() string {
+	return s.urlPrefix
+}
+
+func (s *AssetStore) SaveStoryImage(storyID, shotID uuid.UUID, encoded string) (string, error) {
+	content, err := decodeInlineImage(encoded)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(content)) > s.maxBytes {
+		return "", NewLocalizedError(ErrCodeInvalidRequest, MsgInlineImageTooLarge, MessageParams{"limit": s.maxBytes})
+	}
+	ext := assetExtension(http.DetectContentType(content), "", content)
+	if ext == ".bin" {
+		return "", NewServiceError(ErrCodeInvalidRequest, "内联图片格式不受支持")
+	}
+	dir := filepath.Join(s.dir, "stories", storyID.String())
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", WrapServiceError(ErrCodeDatabaseActionFailed, "创建素材目录失败", err)
+	}
+	name := shotID.String() + ext
+	if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+		return "", WrapServiceError(ErrCodeDatabaseActionFailed, "写入素材文件失败", err)
+	}
+	return path.Join(s.urlPrefix, "stories", storyID.String(), name), nil
+}
+
+func (s *AssetStore) RemoveStory(storyID uuid.UUID) error {
+	return os.RemoveAll(filepath.Join(s.dir, "stories", storyID.String()))
+}
+
+func (s *AssetStore) IsLocal(ref string) bool {
+	return strings.HasPrefix(ref, s.urlPrefix+"/")
+}
+
+func (s *AssetStore) StoryAssetID(ref string) (uuid.UUID, bool) {
+	storyID, _, ok := s.splitStoryAsset(ref)
+	return storyID, ok
+}
+
+func (s *AssetStore) StoryPath(ref string, storyID uuid.UUID) (string, bool) {
+	owner, name, ok := s.splitStoryAsset(ref)
+	if !ok || owner != storyID {
+		return "", false
+	}
+	return filepath.Join(s.dir, "stories", owner.String(), name), true
+}
+
+func (s *AssetStore) splitStoryAsset(ref string) (uuid.UUID, string, bool) {
+	prefix := s.urlPrefix + "/stories/"
+	if !strings.HasPrefix(ref, prefix) {
+		return uuid.Nil, "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(ref, prefix), "/")
+	if len(parts) != 2 {
+		return uuid.Nil, "", false
+	}
+	storyID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+	name := parts[1]
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `\`) {
+		return uuid.Nil, "", false
+	}
+	return storyID, name, true
+}
+
+func decodeInlineImage(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	if strings.HasPrefix(encoded, "data:") {
+		idx := strings.Index(encoded, ",")
+		if idx == -1 {
+			return nil, NewServiceError(ErrCodeInvalidRequest, "内联图片 data URL 格式错误")
+		}
+		encoded = encoded[idx+1:]
+	}
+	content, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, WrapServiceError(ErrCodeInvalidRequest, "内联图片 base64 解码失败", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+var activeOperationStatuses = []string{global.OpScheduled, global.OpQueued, global.OpRunning}
+
+type BatchCounts struct {
+	Scheduled int `json:"scheduled"`
+	Queued    int `json:"queued"`
+	Running   int `json:"running"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Cancelled int `json:"cancelled"`
+}
+
+type BatchView struct {
+	Name           string      `json:"name"`
+	BatchID        uuid.UUID   `json:"batch_id"`
+	State          string      `json:"state"`
+	Total          int         `json:"total"`
+	Counts         BatchCounts `json:"counts"`
+	OperationNames []string    `json:"operation_names"`
+	CreateTime     time.Time   `json:"create_time"`
+	UpdateTime     time.Time   `json:"update_time"`
+	CompleteTime   *time.Time  `json:"complete_time,omitempty"`
+}
+
+type BatchService struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	tracker    *BatchTracker
+	logger     *zap.Logger
+}
+
+func NewBatchService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *BatchService {
+	return &BatchService{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, newKafkaProducer(cfg, logger)),
+		tracker:    NewBatchTracker(cfg, d, logger),
+		logger:     logger,
+	}
+}
+
+func (s *BatchService) Close() error {
+	if s == nil {
+		return nil
+	}
+	return errors.Join(s.dispatcher.Close(), s.tracker.Close())
+}
+
+func (s *BatchService) Get(ctx context.Context, userID, batchID uuid.UUID) (*BatchView, error) {
+	batch, err := s.find(ctx, userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, batch)
+}
+
+func (s *BatchService) Cancel(ctx context.Context, userID, batchID uuid.UUID) (*BatchView, error) {
+	batch, err := s.find(ctx, userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ops []model.Operation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("batch_id = ? AND status IN ?", batch.ID, []string{global.OpScheduled, global.OpQueued}).
+			Find(&ops).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询批量子任务失败", err)
+		}
+		now := time.Now()
+		for idx := range ops {
+			op := &ops[idx]
+			if err := tx.Model(&model.Operation{}).
+				Where("id = ?", op.ID).
+				Updates(map[string]interface{}{
+					"status":      global.OpCancel,
+					"finished_at": now,
+				}).Error; err != nil {
+				return WrapServiceError(ErrCodeOperationUpdateFailed, "取消任务失败", err)
+			}
+			if err := releaseCancelledTarget(tx, op); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	InvalidateStoryListCache(ctx, s.data, userID)
+	s.tracker.Finalize(ctx, batch.ID)
+	return s.Get(ctx, userID, batch.ID)
+}
+
+func (s *BatchService) Retry(ctx context.Context, userID, batchID uuid.UUID) (*BatchView, error) {
+	batch, err := s.find(ctx, userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	var ops []model.Operation
+	err = s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("batch_id = ? AND status = ? AND type IN ?", batch.ID, global.OpFail, dispatchableOperationTypes()).
+			Order("created_at ASC").
+			Find(&ops).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询批量子任务失败", err)
+		}
+		if len(ops) == 0 {
+			return nil
+		}
+		for idx := range ops {
+			if err := resetFailedOperation(tx, &ops[idx]); err != nil {
+				return err
+			}
+		}
+		return reopenBatch(tx, batch.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) > 0 {
+		InvalidateStoryListCache(ctx, s.data, userID)
+		for idx := range ops {
+			op := &ops[idx]
+			if err := redispatchOperation(ctx, s.data, s.dispatcher, op); err != nil {
+				s.logger.Error("dispatch retried batch operation", zap.String(string(LogKeyOperationID), op.ID.String()), zap.Error(err))
+			}
+		}
+		s.tracker.Finalize(ctx, batch.ID)
+	}
+	return s.Get(ctx, userID, batch.ID)
+}
+
+func (s *BatchService) find(ctx context.Context, userID, batchID uuid.UUID) (*model.Batch, error) {
+	var batch model.Batch
+	if err := s.data.DB.WithContext(ctx).First(&batch, "id = ? AND user_id = ?", batchID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeBatchNotFound, "批量任务不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询批量任务失败", err)
+	}
+	return &batch, nil
+}
+
+func (s *BatchService) view(ctx context.Context, batch *model.Batch) (*BatchView, error) {
+	counts, err := countBatchOperations(s.data.DB.WithContext(ctx), batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	var opIDs []uuid.UUID
+	if err := s.data.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Where("batch_id = ?", batch.ID).
+		Order("created_at ASC").
+		Pluck("id", &opIDs).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询批量子任务失败", err)
+	}
+	names := make([]string, 0, len(opIDs))
+	for _, id := range opIDs {
+		names = append(names, fmt.Sprintf("operations/%s", id))
+	}
+	return &BatchView{
+		Name:           fmt.Sprintf("batches/%s", batch.ID),
+		BatchID:        batch.ID,
+		State:          batch.Status,
+		Total:          batch.Total,
+		Counts:         counts,
+		OperationNames: names,
+		CreateTime:     batch.CreatedAt,
+		UpdateTime:     batch.UpdatedAt,
+		CompleteTime:   batch.CompletedAt,
+	}, nil
+}
+
+func countBatchOperations(db *gorm.DB, batchID uuid.UUID) (BatchCounts, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := db.Model(&model.Operation{}).
+		Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return BatchCounts{}, WrapServiceError(ErrCodeDatabaseActionFailed, "统计批量子任务失败", err)
+	}
+	var counts BatchCounts
+	for _, row := range rows {
+		switch row.Status {
+		case global.OpScheduled:
+			counts.Scheduled = row.Count
+		case global.OpQueued:
+			counts.Queued = row.Count
+		case global.OpRunning:
+			counts.Running = row.Count
+		case global.OpSuccess:
+			counts.Succeeded = row.Count
+		case global.OpFail:
+			counts.Failed = row.Count
+		case global.OpCancel:
+			counts.Cancelled = row.Count
+		}
+	}
+	return counts, nil
+}
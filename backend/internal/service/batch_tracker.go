@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+	"story2video-backend/internal/tracing"
+)
+
+type BatchCompletedEvent struct {
+	BatchID     string      `json:"batch_id"`
+	UserID      string      `json:"user_id"`
+	Total       int         `json:"total"`
+	Counts      BatchCounts `json:"counts"`
+	CompletedAt time.Time   `json:"completed_at"`
+}
+
+type BatchTracker struct {
+	data   *data.Data
+	writer *kafka.Writer
+	logger *zap.Logger
+}
+
+func NewBatchTracker(cfg *conf.Config, d *data.Data, logger *zap.Logger) *BatchTracker {
+	t := &BatchTracker{data: d, logger: logger}
+	if len(cfg.Kafka.Brokers) > 0 && cfg.Kafka.BatchEventTopic != "" {
+		t.writer = &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Kafka.Brokers...),
+			Topic:        cfg.Kafka.BatchEventTopic,
+			Balancer:     &kafka.Hash{},
+			WriteTimeout: kafkaPublishTimeout,
+		}
+		if cfg.Kafka.MaxAttempts > 0 {
+			t.writer.MaxAttempts = cfg.Kafka.MaxAttempts
+		}
+	}
+	return t
+}
+
+func (t *BatchTracker) Close() error {
+	if t == nil || t.writer == nil {
+		return nil
+	}
+	return t.writer.Close()
+}
+
+func (t *BatchTracker) Finalize(ctx context.Context, batchID uuid.UUID) {
+	if t == nil || t.data == nil || t.data.DB == nil {
+		return
+	}
+	db := t.data.DB.WithContext(ctx)
+	now := time.Now()
+	res := db.Model(&model.Batch{}).
+		Where("id = ? AND status = ?", batchID, global.BatchRunning).
+		Where("NOT EXISTS (?)", db.Model(&model.Operation{}).
+			Select("1").
+			Where("batch_id = ? AND status IN ?", batchID, activeOperationStatuses)).
+		Updates(map[string]interface{}{
+			"status":       global.BatchCompleted,
+			"completed_at": now,
+		})
+	if res.Error != nil {
+		t.logger.Warn("finalize batch", zap.String("batch_id", batchID.String()), zap.Error(res.Error))
+		return
+	}
+	if res.RowsAffected == 0 {
+		return
+	}
+	if err := t.publish(ctx, db, batchID, now); err != nil {
+		t.logger.Warn("publish batch completed event", zap.String("batch_id", batchID.String()), zap.Error(err))
+	}
+}
+
+func (t *BatchTracker) publish(ctx context.Context, db *gorm.DB, batchID uuid.UUID, completedAt time.Time) error {
+	var batch model.Batch
+	if err := db.First(&batch, "id = ?", batchID).Error; err != nil {
+		return err
+	}
+	counts, err := countBatchOperations(db, batchID)
+	if err != nil {
+		return err
+	}
+	event := BatchCompletedEvent{
+		BatchID:     batch.ID.String(),
+		UserID:      batch.UserID.String(),
+		Total:       batch.Total,
+		Counts:      counts,
+		CompletedAt: completedAt,
+	}
+	t.logger.Info("batch completed",
+		zap.String("batch_id", event.BatchID),
+		zap.String(string(LogKeyUserID), event.UserID),
+		zap.Int("succeeded", counts.Succeeded),
+		zap.Int("failed", counts.Failed),
+		zap.Int("cancelled", counts.Cancelled),
+	)
+	if t.writer == nil {
+		return nil
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var headers []kafka.Header
+	tracing.Inject(ctx, tracing.KafkaHeaderCarrier{Headers: &headers})
+	return t.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(event.BatchID),
+		Value:   value,
+		Time:    completedAt,
+		Headers: headers,
+	})
+}
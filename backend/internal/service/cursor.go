@@ -0,0 +1,147 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/conf"
+)
+
+const cursorSignatureBytes = 16
+
+var (
+	ephemeralCursorSecret     []byte
+	ephemeralCursorSecretOnce sync.Once
+)
+
+type PageCursor struct {
+	Sort   string    `json:"s"`
+	Desc   bool      `json:"d,omitempty"`
+	Values []string  `json:"v"`
+	ID     uuid.UUID `json:"i"`
+	Filter string    `json:"f,omitempty"`
+}
+
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(cfg *conf.Config, logger *zap.Logger) *CursorCodec {
+	if cfg != nil && strings.TrimSpace(cfg.Pagination.CursorSecret) != "" {
+		return &CursorCodec{secret: []byte(cfg.Pagination.CursorSecret)}
+	}
+	ephemeralCursorSecretOnce.Do(func() {
+		secret := make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Errorf("generate cursor secret: %w", err))
+		}
+		ephemeralCursorSecret = secret
+		if logger != nil {
+			logger.Warn("pagination.cursor_secret is not configured; page tokens are signed with a random per-process key and will not survive restarts or span replicas")
+		}
+	})
+	return &CursorCodec{secret: ephemeralCursorSecret}
+}
+
+func (c *CursorCodec) Encode(cur PageCursor) string {
+	raw, err := json.Marshal(cur)
+	if err != nil {
+		return ""
+	}
+	body := base64.RawURLEncoding.EncodeToString(raw)
+	return body + "." + base64.RawURLEncoding.EncodeToString(c.sign(body))
+}
+
+func (c *CursorCodec) Decode(token string) (*PageCursor, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenInvalid, nil)
+	}
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, c.sign(body)) {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenSignature, nil)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenInvalid, nil)
+	}
+	var cur PageCursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenInvalid, nil)
+	}
+	return &cur, nil
+}
+
+func (c *CursorCodec) sign(body string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)[:cursorSignatureBytes]
+}
+
+func LegacyPageOffset(token string) (int, bool) {
+	if token == "" {
+		return 0, false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+	}
+	offset, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, false
+	}
+	return offset, true
+}
+
+func (cur *PageCursor) expect(sort string, desc bool, filter string) error {
+	if cur.Sort != sort || cur.Desc != desc || cur.Filter != filter || len(cur.Values) == 0 {
+		return NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenMismatch, nil)
+	}
+	return nil
+}
+
+func (cur *PageCursor) timeValue(idx int) (time.Time, error) {
+	if idx >= len(cur.Values) {
+		return time.Time{}, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenInvalid, nil)
+	}
+	t, err := time.Parse(time.RFC3339Nano, cur.Values[idx])
+	if err != nil {
+		return time.Time{}, NewLocalizedError(ErrCodeInvalidRequest, MsgPageTokenInvalid, nil)
+	}
+	return t, nil
+}
+
+func formatCursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func applyKeyset(query *gorm.DB, columns []string, desc bool, values ...interface{}) *gorm.DB {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return query.Where(fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, placeholders), values...)
+}
+
+func listFingerprint(v interface{}) string {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:6])
+}
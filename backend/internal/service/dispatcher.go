@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+
+	"story2video-backend/internal/metrics"
 )
 
 const kafkaPublishTimeout = 10 * time.Second
@@ -21,10 +23,22 @@ func newJobDispatcher(logger *zap.Logger, producer producer) *jobDispatcher {
 	}
 }
 
-func (d *jobDispatcher) Dispatch(job StoryJobMessage) error {
-	ctx, cancel := context.WithTimeout(context.Background(), kafkaPublishTimeout)
+func (d *jobDispatcher) Dispatch(ctx context.Context, job StoryJobMessage) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), kafkaPublishTimeout)
 	defer cancel()
-	if err := d.producer.Publish(ctx, job); err != nil {
+	start := time.Now()
+	err := d.producer.Publish(ctx, job)
+	result := metrics.ResultOK
+	if err != nil {
+		result = metrics.ResultError
+	}
+	metrics.KafkaPublishDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	if err != nil {
+		code := ErrCodeJobEnqueueFailed
+		if svcErr, ok := AsServiceError(err); ok {
+			code = svcErr.Code
+		}
+		metrics.KafkaPublishFailures.WithLabelValues(string(code)).Inc()
 		if d.logger != nil {
 			d.logger.Error(string(LogMsgKafkaPublishFailed), d.kafkaLogFields(job, err)...)
 		}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"story2video-backend/pkg/document"
+)
+
+const DefaultChapterMaxRunes = 2000
+
+type ScriptChapter struct {
+	Title   string
+	Content string
+}
+
+type ParsedScript struct {
+	Title    string
+	Format   string
+	Chapters []ScriptChapter
+}
+
+func ParseScriptDocument(filename string, data []byte, maxChapterRunes int, maxExtractedBytes int64) (*ParsedScript, error) {
+	if maxChapterRunes <= 0 {
+		maxChapterRunes = DefaultChapterMaxRunes
+	}
+	doc, err := document.Parse(filename, data, document.Options{MaxChapterRunes: maxChapterRunes, MaxExtractedBytes: maxExtractedBytes})
+	if err != nil {
+		if errors.Is(err, document.ErrUnsupportedFormat) {
+			return nil, NewLocalizedError(ErrCodeUnsupportedDocument, MsgDocumentFormatUnsupported, MessageParams{"filename": filename})
+		}
+		if errors.Is(err, document.ErrArchiveTooLarge) {
+			return nil, WrapServiceError(ErrCodePayloadTooLarge, "文档解压后的内容超过大小限制", err)
+		}
+		return nil, WrapServiceError(ErrCodeDocumentParseFailed, "解析上传文档失败", err)
+	}
+	parsed := &ParsedScript{
+		Title:    strings.TrimSpace(doc.Title),
+		Format:   doc.Format,
+		Chapters: make([]ScriptChapter, 0, len(doc.Chapters)),
+	}
+	for _, ch := range doc.Chapters {
+		if !utf8.ValidString(ch.Text) {
+			return nil, NewServiceError(ErrCodeDocumentParseFailed, "文档包含无法识别的字符编码")
+		}
+		parsed.Chapters = append(parsed.Chapters, ScriptChapter{Title: ch.Title, Content: ch.Text})
+	}
+	if len(parsed.Chapters) == 0 {
+		return nil, NewServiceError(ErrCodeDocumentParseFailed, "文档中没有可用的文本内容")
+	}
+	return parsed, nil
+}
+
+func ChapterDisplayName(base string, idx int, chapter ScriptChapter, total int) string {
+	if total <= 1 {
+		return base
+	}
+	if chapter.Title == "" {
+		return fmt.Sprintf("%s - %d", base, idx+1)
+	}
+	return fmt.Sprintf("%s - %s", base, chapter.Title)
+}
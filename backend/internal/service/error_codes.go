@@ -5,47 +5,76 @@ import "errors"
 type ErrorCode string
 
 const (
-	ErrCodeInvalidRequest        ErrorCode = "SVC1000"
-	ErrCodeInvalidStyle          ErrorCode = "SVC1001"
-	ErrCodeInvalidShotDetails    ErrorCode = "SVC1002"
-	ErrCodeStoryNotFound         ErrorCode = "SVC1101"
-	ErrCodeShotNotFound          ErrorCode = "SVC1102"
-	ErrCodeOperationCreateFailed ErrorCode = "SVC2001"
-	ErrCodeOperationUpdateFailed ErrorCode = "SVC2002"
-	ErrCodeOperationTimeout      ErrorCode = "SVC2003"
-	ErrCodeKafkaConfigInvalid    ErrorCode = "SVC3001"
-	ErrCodeJobEnqueueFailed      ErrorCode = "SVC3002"
-	ErrCodeWorkerExecutionFailed ErrorCode = "SVC4001"
-	ErrCodeResultDataMissing     ErrorCode = "SVC4002"
-	ErrCodeShotMissingPartial    ErrorCode = "SVC4101"
-	ErrCodeShotContentMissing    ErrorCode = "SVC4102"
-	ErrCodeShotAssetMissing      ErrorCode = "SVC4103"
-	ErrCodeDatabaseActionFailed  ErrorCode = "SVC5001"
+	ErrCodeInvalidRequest          ErrorCode = "SVC1000"
+	ErrCodeInvalidStyle            ErrorCode = "SVC1001"
+	ErrCodeInvalidShotDetails      ErrorCode = "SVC1002"
+	ErrCodeUnsupportedDocument     ErrorCode = "SVC1003"
+	ErrCodeDocumentParseFailed     ErrorCode = "SVC1004"
+	ErrCodeScriptEmpty             ErrorCode = "SVC1005"
+	ErrCodeScriptTooShort          ErrorCode = "SVC1006"
+	ErrCodeScriptTooLong           ErrorCode = "SVC1007"
+	ErrCodeScriptLanguage          ErrorCode = "SVC1008"
+	ErrCodeScriptBannedContent     ErrorCode = "SVC1009"
+	ErrCodeScriptTooManyShots      ErrorCode = "SVC1010"
+	ErrCodeContentFlagged          ErrorCode = "SVC1011"
+	ErrCodePayloadTooLarge         ErrorCode = "SVC1012"
+	ErrCodeStoryNotFound           ErrorCode = "SVC1101"
+	ErrCodeShotNotFound            ErrorCode = "SVC1102"
+	ErrCodeOperationNotFound       ErrorCode = "SVC1103"
+	ErrCodeModerationNotFound      ErrorCode = "SVC1104"
+	ErrCodeMethodNotFound          ErrorCode = "SVC1105"
+	ErrCodeBatchNotFound           ErrorCode = "SVC1106"
+	ErrCodeUnauthenticated         ErrorCode = "SVC1201"
+	ErrCodePermissionDenied        ErrorCode = "SVC1202"
+	ErrCodeOperationCreateFailed   ErrorCode = "SVC2001"
+	ErrCodeOperationUpdateFailed   ErrorCode = "SVC2002"
+	ErrCodeOperationTimeout        ErrorCode = "SVC2003"
+	ErrCodeExportNotReady          ErrorCode = "SVC2004"
+	ErrCodeOperationNotCancellable ErrorCode = "SVC2005"
+	ErrCodeOperationNotScheduled   ErrorCode = "SVC2006"
+	ErrCodeOperationNotRetryable   ErrorCode = "SVC2007"
+	ErrCodeKafkaConfigInvalid      ErrorCode = "SVC3001"
+	ErrCodeJobEnqueueFailed        ErrorCode = "SVC3002"
+	ErrCodeWorkerExecutionFailed   ErrorCode = "SVC4001"
+	ErrCodeResultDataMissing       ErrorCode = "SVC4002"
+	ErrCodeExportFailed            ErrorCode = "SVC4003"
+	ErrCodeModerationFailed        ErrorCode = "SVC4004"
+	ErrCodeShotMissingPartial      ErrorCode = "SVC4101"
+	ErrCodeShotContentMissing      ErrorCode = "SVC4102"
+	ErrCodeShotAssetMissing        ErrorCode = "SVC4103"
+	ErrCodeInternal                ErrorCode = "SVC5000"
+	ErrCodeDatabaseActionFailed    ErrorCode = "SVC5001"
 )
 
-var errorCodeMessages = map[ErrorCode]string{
-	ErrCodeInvalidRequest:        "请求参数不合法",
-	ErrCodeInvalidStyle:          "不支持的风格",
-	ErrCodeInvalidShotDetails:    "镜头脚本内容无效",
-	ErrCodeStoryNotFound:         "未找到对应故事",
-	ErrCodeShotNotFound:          "未找到对应镜头",
-	ErrCodeOperationCreateFailed: "创建任务失败",
-	ErrCodeOperationUpdateFailed: "更新任务状态失败",
-	ErrCodeOperationTimeout:      "任务执行超时",
-	ErrCodeKafkaConfigInvalid:    "Kafka 配置错误",
-	ErrCodeJobEnqueueFailed:      "任务投递失败",
-	ErrCodeWorkerExecutionFailed: "工作节点执行失败",
-	ErrCodeResultDataMissing:     "任务结果缺失",
-	ErrCodeShotMissingPartial:    "部分镜头缺失",
-	ErrCodeShotContentMissing:    "镜头内容缺失或不完整",
-	ErrCodeShotAssetMissing:      "镜头素材缺失或损坏",
-	ErrCodeDatabaseActionFailed:  "数据库操作失败",
-}
+const (
+	ErrorDetailShotID          = "shot_id"
+	ErrorDetailStoryID         = "story_id"
+	ErrorDetailRPCCode         = "rpc_code"
+	ErrorDetailModelHTTPStatus = "model_http_status"
+)
+
+var (
+	retryableErrorCodes = map[ErrorCode]struct{}{
+		ErrCodeOperationTimeout:      {},
+		ErrCodeJobEnqueueFailed:      {},
+		ErrCodeWorkerExecutionFailed: {},
+		ErrCodeModerationFailed:      {},
+		ErrCodeDatabaseActionFailed:  {},
+	}
+	publicErrorDetails = map[string]struct{}{
+		ErrorDetailShotID:  {},
+		ErrorDetailStoryID: {},
+	}
+)
 
 type ServiceError struct {
-	Code    ErrorCode
-	Message string
-	Err     error
+	Code      ErrorCode
+	Message   string
+	Err       error
+	Key       MessageKey
+	Params    MessageParams
+	Retryable *bool
+	Details   map[string]string
 }
 
 func (e *ServiceError) Error() string {
@@ -79,6 +108,65 @@ func WrapServiceError(code ErrorCode, msg string, cause error) *ServiceError {
 	return &ServiceError{Code: code, Message: msg, Err: cause}
 }
 
+func (e *ServiceError) WithDetail(key, value string) *ServiceError {
+	if e == nil || key == "" || value == "" {
+		return e
+	}
+	if e.Details == nil {
+		e.Details = make(map[string]string)
+	}
+	e.Details[key] = value
+	return e
+}
+
+func (e *ServiceError) WithRetryable(retryable bool) *ServiceError {
+	if e == nil {
+		return e
+	}
+	e.Retryable = &retryable
+	return e
+}
+
+func (e *ServiceError) IsRetryable() bool {
+	if e == nil {
+		return false
+	}
+	if e.Retryable != nil {
+		return *e.Retryable
+	}
+	return e.Code.Retryable()
+}
+
+func (e *ServiceError) UserMessage() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Code.DefaultMessage()
+}
+
+func ErrorDetails(err error) map[string]string {
+	details := make(map[string]string)
+	for err != nil {
+		if svcErr, ok := err.(*ServiceError); ok && svcErr != nil {
+			for k, v := range svcErr.Details {
+				if _, exists := details[k]; !exists {
+					details[k] = v
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return details
+}
+
+func IsPublicErrorDetail(key string) bool {
+	_, ok := publicErrorDetails[key]
+	return ok
+}
+
 func AsServiceError(err error) (*ServiceError, bool) {
 	var svcErr *ServiceError
 	if errors.As(err, &svcErr) {
@@ -88,10 +176,12 @@ func AsServiceError(err error) (*ServiceError, bool) {
 }
 
 func (c ErrorCode) DefaultMessage() string {
-	if msg, ok := errorCodeMessages[c]; ok {
-		return msg
-	}
-	return ""
+	return c.LocalizedMessage(DefaultLocale)
+}
+
+func (c ErrorCode) Retryable() bool {
+	_, ok := retryableErrorCodes[c]
+	return ok
 }
 
 type LogKey string
@@ -121,6 +211,9 @@ const (
 	LogMsgOperationTimeout     LogMsg = "任务执行超时"
 	LogMsgValidationFailed     LogMsg = "请求参数校验失败"
 	LogMsgDatabaseActionFailed LogMsg = "数据库操作失败"
+	LogMsgExportFailed         LogMsg = "故事导出失败"
+	LogMsgContentFlagged       LogMsg = "内容未通过安全审核"
+	LogMsgModerationFailed     LogMsg = "内容审核调用失败"
 )
 
 var (
@@ -146,6 +239,9 @@ var (
 		LogMsgOperationTimeout,
 		LogMsgValidationFailed,
 		LogMsgDatabaseActionFailed,
+		LogMsgExportFailed,
+		LogMsgContentFlagged,
+		LogMsgModerationFailed,
 	}
 )
 
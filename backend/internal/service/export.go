@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const defaultExportAsyncShotThreshold = 12
+
+type ExportService struct {
+	data           *data.Data
+	dispatcher     *jobDispatcher
+	exporter       *StoryExporter
+	asyncThreshold int
+}
+
+type ExportResult struct {
+	File      *ExportFile
+	Operation *model.Operation
+}
+
+func NewExportService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ExportService {
+	threshold := defaultExportAsyncShotThreshold
+	if cfg != nil && cfg.Export.AsyncShotThreshold > 0 {
+		threshold = cfg.Export.AsyncShotThreshold
+	}
+	prod := newKafkaProducer(cfg, logger)
+	return &ExportService{
+		data:           d,
+		dispatcher:     newJobDispatcher(logger, prod),
+		exporter:       NewStoryExporter(cfg, d, logger),
+		asyncThreshold: threshold,
+	}
+}
+
+func (s *ExportService) Close() error {
+	if s == nil || s.dispatcher == nil {
+		return nil
+	}
+	return s.dispatcher.Close()
+}
+
+func (s *ExportService) Export(ctx context.Context, userID, storyID uuid.UUID, format string, async bool) (*ExportResult, error) {
+	format, err := NormalizeExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	var story model.Story
+	if err := s.data.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", storyID, userID).
+		First(&story).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeStoryNotFound, "故事不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事失败", err)
+	}
+	if err := ensureStoryNotHeld(s.data.DB.WithContext(ctx), &story); err != nil {
+		return nil, err
+	}
+	var shots []model.Shot
+	if err := s.data.DB.WithContext(ctx).
+		Where("story_id = ?", storyID).
+		Order(ShotSequenceOrderClause).
+		Find(&shots).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+	}
+
+	if async || len(shots) > s.asyncThreshold {
+		op, err := s.enqueue(ctx, &story, format)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportResult{Operation: op}, nil
+	}
+	file, err := s.exporter.Build(ctx, &story, shots, format)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportResult{File: file}, nil
+}
+
+func (s *ExportService) enqueue(ctx context.Context, story *model.Story, format string) (*model.Operation, error) {
+	payload := StoryJobPayload{
+		DisplayName:  story.Title,
+		Style:        story.Style,
+		Action:       "export_story",
+		ExportFormat: format,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "序列化导出参数失败", err)
+	}
+
+	op := model.NewOperation(uuid.New(), story.UserID, story.ID, uuid.Nil, global.OpExport, datatypes.JSON(payloadBytes))
+	if err := s.data.DB.WithContext(ctx).Create(op).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "创建导出任务失败", err)
+	}
+
+	job := StoryJobMessage{
+		OperationID: op.ID.String(),
+		StoryID:     story.ID.String(),
+		UserID:      story.UserID.String(),
+		Payload:     payload,
+		CreatedAt:   op.CreatedAt,
+	}
+
+	if err := s.dispatcher.Dispatch(ctx, job); err != nil {
+		_ = UpdateOperationFailure(ctx, s.data, op.ID, err)
+		if svcErr, ok := AsServiceError(err); ok {
+			return nil, svcErr
+		}
+		return nil, WrapServiceError(ErrCodeJobEnqueueFailed, "投递导出任务失败", err)
+	}
+	return op, nil
+}
+
+func (s *ExportService) Download(ctx context.Context, userID, opID uuid.UUID) (*ExportArtifact, string, error) {
+	var op model.Operation
+	if err := s.data.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", opID, userID).
+		First(&op).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", NewServiceError(ErrCodeOperationNotFound, "任务不存在")
+		}
+		return nil, "", WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+	}
+	if op.Type != global.OpExport {
+		return nil, "", NewServiceError(ErrCodeInvalidRequest, "该任务没有可下载的导出文件")
+	}
+	if op.Status != global.OpSuccess || len(op.Result) == 0 {
+		return nil, "", NewServiceError(ErrCodeExportNotReady, "导出文件尚未生成")
+	}
+	var artifact ExportArtifact
+	if err := json.Unmarshal(op.Result, &artifact); err != nil || artifact.StoredName == "" {
+		return nil, "", NewServiceError(ErrCodeResultDataMissing, "导出结果缺失")
+	}
+	path := s.exporter.ArtifactPath(&artifact)
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", WrapServiceError(ErrCodeResultDataMissing, "导出文件不存在或已过期", err)
+	}
+	return &artifact, path, nil
+}
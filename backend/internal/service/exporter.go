@@ -0,0 +1,470 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/model"
+	"story2video-backend/pkg/pdf"
+)
+
+const (
+	ExportFormatPDF = "pdf"
+	ExportFormatZIP = "zip"
+)
+
+const (
+	defaultExportDir           = "data/exports"
+	defaultExportFetchTimeout  = 30 * time.Second
+	defaultExportMaxAssetBytes = 20 << 20
+	exportPageMargin           = 48.0
+)
+
+var exportFileNameSanitizer = regexp.MustCompile(`[\\/:*?"<>|\s]+`)
+
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type ExportArtifact struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	StoredName  string `json:"stored_name"`
+	DownloadURL string `json:"download_url"`
+}
+
+type StoryExporter struct {
+	data          *data.Data
+	dir           string
+	assetBaseURL  string
+	client        *http.Client
+	remote        *http.Client
+	hosts         *assetHostPolicy
+	maxAssetBytes int64
+	assets        *AssetStore
+	logger        *zap.Logger
+}
+
+type exportAsset struct {
+	data []byte
+	ext  string
+}
+
+func NewStoryExporter(cfg *conf.Config, d *data.Data, logger *zap.Logger) *StoryExporter {
+	dir := defaultExportDir
+	timeout := defaultExportFetchTimeout
+	maxBytes := int64(defaultExportMaxAssetBytes)
+	baseURL := ""
+	if cfg != nil {
+		if strings.TrimSpace(cfg.Export.Dir) != "" {
+			dir = cfg.Export.Dir
+		}
+		if cfg.Export.FetchTimeoutSeconds > 0 {
+			timeout = time.Duration(cfg.Export.FetchTimeoutSeconds) * time.Second
+		}
+		if cfg.Export.MaxAssetBytes > 0 {
+			maxBytes = cfg.Export.MaxAssetBytes
+		}
+		baseURL = strings.TrimRight(strings.TrimSpace(cfg.ModelService.BaseURL), "/")
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	hosts := newAssetHostPolicy(cfg)
+	return &StoryExporter{
+		data:          d,
+		dir:           dir,
+		assetBaseURL:  baseURL,
+		client:        &http.Client{Timeout: timeout},
+		remote:        newGuardedAssetClient(hosts, timeout),
+		hosts:         hosts,
+		maxAssetBytes: maxBytes,
+		assets:        NewAssetStore(cfg),
+		logger:        logger,
+	}
+}
+
+func NormalizeExportFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", ExportFormatZIP:
+		return ExportFormatZIP, nil
+	case ExportFormatPDF:
+		return ExportFormatPDF, nil
+	default:
+		return "", NewLocalizedError(ErrCodeInvalidRequest, MsgExportFormatUnsupported, MessageParams{"format": format})
+	}
+}
+
+func (e *StoryExporter) Build(ctx context.Context, story *model.Story, shots []model.Shot, format string) (*ExportFile, error) {
+	format, err := NormalizeExportFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	assets := e.fetchShotAssets(ctx, story, shots)
+	file := &ExportFile{FileName: exportFileName(story, format)}
+	switch format {
+	case ExportFormatPDF:
+		file.ContentType = "application/pdf"
+		file.Content = buildStoryboardPDF(story, shots, assets)
+	default:
+		content, err := buildStoryboardZIP(story, shots, assets)
+		if err != nil {
+			return nil, WrapServiceError(ErrCodeExportFailed, "打包故事导出文件失败", err)
+		}
+		file.ContentType = "application/zip"
+		file.Content = content
+	}
+	return file, nil
+}
+
+func (e *StoryExporter) Save(opID uuid.UUID, file *ExportFile) (*ExportArtifact, error) {
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return nil, WrapServiceError(ErrCodeExportFailed, "创建导出目录失败", err)
+	}
+	stored := opID.String() + path.Ext(file.FileName)
+	if err := os.WriteFile(filepath.Join(e.dir, stored), file.Content, 0o644); err != nil {
+		return nil, WrapServiceError(ErrCodeExportFailed, "写入导出文件失败", err)
+	}
+	return &ExportArtifact{
+		FileName:    file.FileName,
+		ContentType: file.ContentType,
+		Size:        int64(len(file.Content)),
+		StoredName:  stored,
+		DownloadURL: fmt.Sprintf("/v1/operations/%s/download", opID),
+	}, nil
+}
+
+func (e *StoryExporter) ArtifactPath(artifact *ExportArtifact) string {
+	return filepath.Join(e.dir, filepath.Base(artifact.StoredName))
+}
+
+func (e *StoryExporter) fetchShotAssets(ctx context.Context, story *model.Story, shots []model.Shot) map[int]*exportAsset {
+	owned := e.ownedAssetStories(ctx, story, shots)
+	assets := make(map[int]*exportAsset, len(shots))
+	for idx, shot := range shots {
+		if strings.TrimSpace(shot.ImageURL) == "" {
+			continue
+		}
+		asset, err := e.FetchAsset(ctx, shot.ImageURL, owned)
+		if err != nil {
+			e.logger.Warn(string(LogMsgShotAssetMissing),
+				zap.String(string(LogKeyShotID), shot.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		assets[idx] = asset
+	}
+	return assets
+}
+
+func (e *StoryExporter) ownedAssetStories(ctx context.Context, story *model.Story, shots []model.Shot) map[uuid.UUID]bool {
+	owned := map[uuid.UUID]bool{story.ID: true}
+	var candidates []uuid.UUID
+	for _, shot := range shots {
+		if storyID, ok := e.assets.StoryAssetID(strings.TrimSpace(shot.ImageURL)); ok && !owned[storyID] {
+			candidates = append(candidates, storyID)
+		}
+	}
+	if len(candidates) == 0 || e.data == nil {
+		return owned
+	}
+	var ids []uuid.UUID
+	if err := e.data.DB.WithContext(ctx).
+		Model(&model.Story{}).
+		Where("id IN ? AND user_id = ?", candidates, story.UserID).
+		Pluck("id", &ids).Error; err != nil {
+		e.logger.Warn("query asset owner stories", zap.String(string(LogKeyStoryID), story.ID.String()), zap.Error(err))
+		return owned
+	}
+	for _, id := range ids {
+		owned[id] = true
+	}
+	return owned
+}
+
+func (e *StoryExporter) FetchAsset(ctx context.Context, ref string, owned map[uuid.UUID]bool) (*exportAsset, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "data:") {
+		return decodeDataURL(ref)
+	}
+	if e.assets.IsLocal(ref) {
+		storyID, ok := e.assets.StoryAssetID(ref)
+		if !ok || !owned[storyID] {
+			return nil, fmt.Errorf("local asset outside story owner prefix: %s", ref)
+		}
+		local, _ := e.assets.StoryPath(ref, storyID)
+		content, err := readLimitedFile(local, e.maxAssetBytes)
+		if err != nil {
+			return nil, err
+		}
+		return &exportAsset{data: content, ext: assetExtension("", local, content)}, nil
+	}
+	client := e.client
+	target := ref
+	switch {
+	case strings.HasPrefix(ref, "/"):
+		if e.assetBaseURL == "" {
+			return nil, fmt.Errorf("relative asset url without model_service.base_url: %s", ref)
+		}
+		target = e.assetBaseURL + ref
+	case e.assetBaseURL != "" && strings.HasPrefix(ref, e.assetBaseURL+"/"):
+	default:
+		if _, ok := e.hosts.Allows(ref); !ok {
+			return nil, fmt.Errorf("%w: %s", errAssetHostNotAllowed, ref)
+		}
+		client = e.remote
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build asset request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch asset: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("fetch asset %s status=%d", target, res.StatusCode)
+	}
+	if res.ContentLength > e.maxAssetBytes {
+		return nil, fmt.Errorf("asset %s exceeds %d bytes", target, e.maxAssetBytes)
+	}
+	content, err := io.ReadAll(io.LimitReader(res.Body, e.maxAssetBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read asset: %w", err)
+	}
+	if int64(len(content)) > e.maxAssetBytes {
+		return nil, fmt.Errorf("asset %s exceeds %d bytes", target, e.maxAssetBytes)
+	}
+	ext := assetExtension(res.Header.Get("Content-Type"), req.URL.Path, content)
+	return &exportAsset{data: content, ext: ext}, nil
+}
+
+func readLimitedFile(name string, limit int64) ([]byte, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("read local asset: %w", err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read local asset: %w", err)
+	}
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("asset %s exceeds %d bytes", name, limit)
+	}
+	return content, nil
+}
+
+func decodeDataURL(ref string) (*exportAsset, error) {
+	idx := strings.Index(ref, ",")
+	if idx == -1 {
+		return nil, fmt.Errorf("invalid data url")
+	}
+	meta := ref[len("data:"):idx]
+	content, err := decodeInlineImage(ref)
+	if err != nil {
+		return nil, err
+	}
+	mediaType := strings.TrimSuffix(meta, ";base64")
+	return &exportAsset{data: content, ext: assetExtension(mediaType, "", content)}, nil
+}
+
+func assetExtension(contentType, urlPath string, content []byte) string {
+	if ext := strings.ToLower(path.Ext(urlPath)); ext != "" && len(ext) <= 5 {
+		return ext
+	}
+	if contentType == "" && len(content) > 0 {
+		contentType = http.DetectContentType(content)
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "image/jpeg":
+			return ".jpg"
+		case "image/png":
+			return ".png"
+		case "image/webp":
+			return ".webp"
+		case "image/gif":
+			return ".gif"
+		}
+	}
+	return ".bin"
+}
+
+func exportFileName(story *model.Story, format string) string {
+	name := strings.Trim(exportFileNameSanitizer.ReplaceAllString(story.Title, "_"), "_.")
+	if name == "" {
+		name = story.ID.String()
+	}
+	return fmt.Sprintf("%s.%s", name, format)
+}
+
+func shotFileStem(idx int, shot model.Shot) string {
+	seq := strings.Trim(exportFileNameSanitizer.ReplaceAllString(shot.Sequence, "_"), "_.")
+	if seq == "" {
+		seq = fmt.Sprintf("%d", idx+1)
+	}
+	return fmt.Sprintf("shot_%03d_%s", idx+1, seq)
+}
+
+func buildStoryboardZIP(story *model.Story, shots []model.Shot, assets map[int]*exportAsset) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	modified := time.Now()
+	write := func(name string, content []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	manifest := NewStoryManifest(story, shots)
+	var narration strings.Builder
+	for idx, shot := range shots {
+		stem := shotFileStem(idx, shot)
+		if asset, ok := assets[idx]; ok {
+			name := "images/" + stem + asset.ext
+			if err := write(name, asset.data); err != nil {
+				return nil, err
+			}
+			manifest.Shots[idx].ImageFile = name
+		}
+		text := strings.TrimSpace(shot.Narration)
+		if text == "" {
+			continue
+		}
+		if err := write("narration/"+stem+".txt", []byte(text+"\n")); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&narration, "[%d] %s\n%s\n\n", idx+1, shot.Title, text)
+	}
+	if err := write("narration.txt", []byte(narration.String())); err != nil {
+		return nil, err
+	}
+
+	cues := BuildSubtitleCues(story, shots)
+	if err := write("subtitles.srt", RenderSRT(cues)); err != nil {
+		return nil, err
+	}
+	if err := write("subtitles.vtt", RenderWebVTT(cues)); err != nil {
+		return nil, err
+	}
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := write("manifest.json", manifestBytes); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func buildStoryboardPDF(story *model.Story, shots []model.Shot, assets map[int]*exportAsset) []byte {
+	doc := pdf.New()
+	contentWidth := pdf.PageWidth - 2*exportPageMargin
+	y := exportPageMargin
+
+	newPage := func() {
+		doc.AddPage()
+		y = exportPageMargin
+	}
+	ensure := func(height float64) {
+		if y+height > pdf.PageHeight-exportPageMargin {
+			newPage()
+		}
+	}
+	paragraph := func(text string, size float64) {
+		lineHeight := size * 1.5
+		for _, line := range pdf.WrapText(text, size, contentWidth) {
+			ensure(lineHeight)
+			y += lineHeight
+			doc.Text(exportPageMargin, y, size, line)
+		}
+	}
+	section := func(label, text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		ensure(40)
+		y += 10
+		paragraph(label, 11)
+		paragraph(text, 10)
+	}
+
+	newPage()
+	title := story.Title
+	if title == "" {
+		title = story.ID.String()
+	}
+	paragraph(title, 22)
+	y += 6
+	paragraph(fmt.Sprintf("风格: %s    镜头数: %d    创建时间: %s", story.Style, len(shots), story.CreatedAt.Format("2006-01-02 15:04")), 10)
+	doc.Line(exportPageMargin, y+8, pdf.PageWidth-exportPageMargin, y+8)
+	y += 16
+	section("剧本", story.Content)
+
+	for idx, shot := range shots {
+		newPage()
+		header := fmt.Sprintf("镜头 %d / %d", idx+1, len(shots))
+		if shot.Title != "" {
+			header += "  " + shot.Title
+		}
+		paragraph(header, 16)
+		y += 8
+		if asset, ok := assets[idx]; ok {
+			if img, err := doc.LoadImage(asset.data); err == nil && img.Width > 0 && img.Height > 0 {
+				w := contentWidth
+				h := w * float64(img.Height) / float64(img.Width)
+				if maxH := pdf.PageHeight * 0.42; h > maxH {
+					h = maxH
+					w = h * float64(img.Width) / float64(img.Height)
+				}
+				doc.DrawImage(img, exportPageMargin+(contentWidth-w)/2, y, w, h)
+				y += h + 8
+			}
+		}
+		section("画面描述", shot.Description)
+		section("镜头细节", shot.Details)
+		section("旁白", shot.Narration)
+		meta := make([]string, 0, 3)
+		if shot.Type != "" {
+			meta = append(meta, "景别: "+shot.Type)
+		}
+		if shot.Transition != "" {
+			meta = append(meta, "转场: "+shot.Transition)
+		}
+		if shot.Voice != "" {
+			meta = append(meta, "配音: "+shot.Voice)
+		}
+		section("参数", strings.Join(meta, "    "))
+	}
+	return doc.Bytes()
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+type ForkService struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	logger     *zap.Logger
+}
+
+type DuplicateStoryParams struct {
+	DisplayName     string
+	Style           string
+	RegenerateShots []uuid.UUID
+	RegenerateAll   bool
+}
+
+type DuplicateStoryResult struct {
+	Story      *model.Story
+	Shots      []model.Shot
+	Operations []*model.Operation
+}
+
+type StoryLineageNode struct {
+	StoryID     uuid.UUID `json:"story_id"`
+	DisplayName string    `json:"display_name"`
+	Style       string    `json:"style"`
+	CreateTime  time.Time `json:"create_time"`
+}
+
+type StoryLineage struct {
+	Ancestors []StoryLineageNode `json:"ancestors"`
+	Forks     []StoryLineageNode `json:"forks"`
+}
+
+func NewForkService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ForkService {
+	prod := newKafkaProducer(cfg, logger)
+	return &ForkService{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, prod),
+		logger:     logger,
+	}
+}
+
+func (s *ForkService) Close() error {
+	if s == nil || s.dispatcher == nil {
+		return nil
+	}
+	return s.dispatcher.Close()
+}
+
+func (s *ForkService) ensureForkable(ctx context.Context, source *model.Story) error {
+	held := source.Status == global.StoryFlag
+	if !held {
+		var flaggedShots int64
+		if err := s.data.DB.WithContext(ctx).
+			Model(&model.Shot{}).
+			Where("story_id = ? AND status = ?", source.ID, global.ShotFlag).
+			Count(&flaggedShots).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+		}
+		held = flaggedShots > 0
+	}
+	if !held {
+		var records int64
+		if err := s.data.DB.WithContext(ctx).
+			Model(&model.ModerationRecord{}).
+			Where("story_id = ? AND status IN ?", source.ID, []string{global.ModerationPending, global.ModerationRejected}).
+			Count(&records).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询审核记录失败", err)
+		}
+		held = records > 0
+	}
+	if held {
+		return NewLocalizedError(ErrCodeContentFlagged, MsgStoryForkHeldForReview, MessageParams{"story_id": source.ID})
+	}
+	return nil
+}
+
+func (s *ForkService) Duplicate(ctx context.Context, userID, storyID uuid.UUID, params DuplicateStoryParams) (*DuplicateStoryResult, error) {
+	var source model.Story
+	if err := s.data.DB.WithContext(ctx).
+		Where("id = ? AND user_id = ?", storyID, userID).
+		First(&source).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeStoryNotFound, "故事不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事失败", err)
+	}
+	if source.Status == global.StoryGen || source.Status == global.StoryDraft {
+		return nil, NewServiceError(ErrCodeInvalidRequest, "故事仍在生成中，暂不能复制")
+	}
+	if err := s.ensureForkable(ctx, &source); err != nil {
+		return nil, err
+	}
+	style := source.Style
+	if params.Style != "" {
+		if err := validateStyle(params.Style); err != nil {
+			return nil, err
+		}
+		style = params.Style
+	}
+
+	var sourceShots []model.Shot
+	if err := s.data.DB.WithContext(ctx).
+		Where("story_id = ?", storyID).
+		Order(ShotSequenceOrderClause).
+		Find(&sourceShots).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+	}
+
+	regenerate := make(map[uuid.UUID]struct{}, len(params.RegenerateShots))
+	for _, id := range params.RegenerateShots {
+		regenerate[id] = struct{}{}
+	}
+	for id := range regenerate {
+		found := false
+		for _, shot := range sourceShots {
+			if shot.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, NewLocalizedError(ErrCodeShotNotFound, MsgShotNotInStory, MessageParams{"shot_id": id})
+		}
+	}
+
+	fork := model.NewStory(uuid.New(), userID, source.Content)
+	fork.Title = params.DisplayName
+	if strings.TrimSpace(fork.Title) == "" {
+		fork.Title = source.Title + " (副本)"
+	}
+	fork.Style = style
+	fork.Duration = source.Duration
+	fork.Status = global.StoryReady
+	fork.CoverURL = source.CoverURL
+	fork.ParentStoryID = &source.ID
+
+	shots := make([]model.Shot, 0, len(sourceShots))
+	regenIdx := make([]int, 0, len(regenerate))
+	for _, src := range sourceShots {
+		shot := src
+		shot.BaseModel = model.BaseModel{ID: uuid.New(), UserID: userID}
+		shot.StoryID = fork.ID
+		if _, ok := regenerate[src.ID]; ok || params.RegenerateAll {
+			if strings.TrimSpace(shot.Details) == "" {
+				return nil, NewLocalizedError(ErrCodeInvalidShotDetails, MsgShotScriptMissing, MessageParams{"shot_id": src.ID})
+			}
+			shot.Status = global.ShotRender
+			regenIdx = append(regenIdx, len(shots))
+		} else if shot.Status == global.ShotFail {
+			fork.Status = global.StoryFail
+		}
+		shots = append(shots, shot)
+	}
+	if len(regenIdx) == 0 && style == source.Style {
+		fork.VideoURL = source.VideoURL
+		fork.Timeline = source.Timeline
+	}
+
+	var (
+		ops  []*model.Operation
+		jobs []StoryJobMessage
+	)
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fork).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建故事副本失败", err)
+		}
+		if len(shots) > 0 {
+			if err := tx.Create(&shots).Error; err != nil {
+				return WrapServiceError(ErrCodeDatabaseActionFailed, "复制镜头失败", err)
+			}
+		}
+		for _, idx := range regenIdx {
+			op, job, err := createShotRegenJob(tx, fork, &shots[idx])
+			if err != nil {
+				return err
+			}
+			ops = append(ops, op)
+			jobs = append(jobs, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	InvalidateStoryListCache(ctx, s.data, userID)
+	dispatchShotRegenJobs(ctx, s.data, s.dispatcher, s.logger, ops, jobs, shots)
+
+	return &DuplicateStoryResult{Story: fork, Shots: shots, Operations: ops}, nil
+}
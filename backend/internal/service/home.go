@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -20,8 +21,13 @@ import (
 )
 
 type HomeService struct {
+	schedulePolicy
 	data       *data.Data
 	dispatcher *jobDispatcher
+	scripts    *ScriptChecker
+	moderator  Moderator
+	batches    *BatchTracker
+	logger     *zap.Logger
 }
 
 type BatchCreateItemResult struct {
@@ -33,6 +39,8 @@ type StoryJobMessage struct {
 	OperationID string          `json:"operation_id"`
 	StoryID     string          `json:"story_id"`
 	UserID      string          `json:"user_id"`
+	BatchID     string          `json:"batch_id,omitempty"`
+	ParentID    string          `json:"parent_id,omitempty"`
 	Payload     StoryJobPayload `json:"payload"`
 	CreatedAt   time.Time       `json:"created_at"`
 }
@@ -44,18 +52,31 @@ type StoryJobPayload struct {
 	ShotID        string `json:"shot_id,omitempty"`
 	ShotDetails   string `json:"shot_details,omitempty"`
 	Action        string `json:"action,omitempty"`
+	ExportFormat  string `json:"export_format,omitempty"`
 }
 
 type CreateHomeParams struct {
 	DisplayName   string
 	ScriptContent string
 	Style         string
+	ScheduledAt   *time.Time
 }
 
 type CreateHomeResult struct {
-	OperationName string    `json:"operation_name"`
-	State         string    `json:"state"`
-	CreateTime    time.Time `json:"create_time"`
+	OperationName string     `json:"operation_name"`
+	State         string     `json:"state"`
+	CreateTime    time.Time  `json:"create_time"`
+	ScheduleTime  *time.Time `json:"schedule_time,omitempty"`
+}
+
+type storyboardJobOptions struct {
+	ScheduledAt *time.Time
+	BatchID     *uuid.UUID
+}
+
+type preparedStory struct {
+	story       *model.Story
+	scheduledAt *time.Time
 }
 
 var (
@@ -69,8 +90,13 @@ var (
 func NewHomeService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *HomeService {
 	prod := newKafkaProducer(cfg, logger)
 	return &HomeService{
-		data:       d,
-		dispatcher: newJobDispatcher(logger, prod),
+		schedulePolicy: newSchedulePolicy(cfg),
+		data:           d,
+		dispatcher:     newJobDispatcher(logger, prod),
+		scripts:        NewScriptChecker(cfg),
+		moderator:      NewModerator(cfg, logger),
+		batches:        NewBatchTracker(cfg, d, logger),
+		logger:         logger,
 	}
 }
 
@@ -78,71 +104,81 @@ func (s *HomeService) Close() error {
 	if s == nil || s.dispatcher == nil {
 		return nil
 	}
-	return s.dispatcher.Close()
+	return errors.Join(s.dispatcher.Close(), s.batches.Close())
 }
 
 func (s *HomeService) Create(ctx context.Context, userID uuid.UUID, params CreateHomeParams) (*CreateHomeResult, error) {
-	if err := validateStyle(params.Style); err != nil {
+	prepared, err := s.prepare(ctx, userID, params)
+	if err != nil {
 		return nil, err
 	}
 
 	var (
-		story *model.Story
-		op    *model.Operation
-		job   StoryJobMessage
+		op  *model.Operation
+		job StoryJobMessage
 	)
-
-	err := s.data.DB.WithContext(ctx).Transaction(func(txCtx *gorm.DB) error {
-		story = model.NewStory(uuid.New(), userID, params.ScriptContent)
-		story.Title = params.DisplayName
-		story.Style = params.Style
-		story.Status = global.StoryGen
-
-		if err := txCtx.Create(story).Error; err != nil {
+	err = s.data.DB.WithContext(ctx).Transaction(func(txCtx *gorm.DB) error {
+		if err := txCtx.Create(prepared.story).Error; err != nil {
 			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建故事记录失败", err)
 		}
+		var err error
+		op, job, err = createStoryboardJob(txCtx, prepared.story, storyboardJobOptions{ScheduledAt: prepared.scheduledAt})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		payload := StoryJobPayload{
-			DisplayName:   params.DisplayName,
-			ScriptContent: params.ScriptContent,
-			Style:         params.Style,
-		}
-		payloadBytes, err := json.Marshal(payload)
-		if err != nil {
-			return WrapServiceError(ErrCodeOperationCreateFailed, "序列化任务参数失败", err)
-		}
-
-		op = model.NewOperation(uuid.New(), userID, story.ID, uuid.Nil, global.OpStoryboard, datatypes.JSON(payloadBytes))
-		if err := txCtx.Create(op).Error; err != nil {
-			return WrapServiceError(ErrCodeOperationCreateFailed, "创建任务记录失败", err)
-		}
+	InvalidateStoryListCache(ctx, s.data, userID)
 
-		job = StoryJobMessage{
-			OperationID: op.ID.String(),
-			StoryID:     story.ID.String(),
-			UserID:      userID.String(),
-			Payload:     payload,
-			CreatedAt:   op.CreatedAt,
-		}
+	return s.launch(ctx, prepared.story, op, job)
+}
 
-		return nil
+func (s *HomeService) prepare(ctx context.Context, userID uuid.UUID, params CreateHomeParams) (*preparedStory, error) {
+	if err := validateStyle(params.Style); err != nil {
+		return nil, err
+	}
+	if svcErr := s.scripts.Check(params.ScriptContent).Err(); svcErr != nil {
+		return nil, svcErr
+	}
+	verdict, err := s.moderator.Moderate(ctx, ModerationInput{
+		Target: global.ModerationTargetScript,
+		Text:   params.DisplayName + "\n" + params.ScriptContent,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	InvalidateStoryListCache(ctx, s.data, userID)
+	story := model.NewStory(uuid.New(), userID, params.ScriptContent)
+	story.Title = params.DisplayName
+	story.Style = params.Style
+	story.Status = global.StoryGen
+	scheduledAt := params.ScheduledAt
+	if scheduledAt != nil && !scheduledAt.After(time.Now()) {
+		scheduledAt = nil
+	}
+	if scheduledAt != nil {
+		story.Status = global.StoryDraft
+	}
 
-	if err := s.dispatcher.Dispatch(job); err != nil {
-		_ = UpdateOperationFailure(ctx, s.data, op.ID, err)
-		_ = s.data.DB.WithContext(ctx).
-			Model(&model.Story{}).
-			Where("id = ?", story.ID).
-			Update("status", global.StoryFail).Error
-		if svcErr, ok := AsServiceError(err); ok {
-			return nil, svcErr
-		}
-		return nil, WrapServiceError(ErrCodeJobEnqueueFailed, "派发故事生成任务失败", err)
+	if verdict.Flagged {
+		return nil, s.holdFlaggedStory(ctx, story, verdict)
+	}
+	return &preparedStory{story: story, scheduledAt: scheduledAt}, nil
+}
+
+func (s *HomeService) launch(ctx context.Context, story *model.Story, op *model.Operation, job StoryJobMessage) (*CreateHomeResult, error) {
+	if op.Status == global.OpScheduled {
+		return &CreateHomeResult{
+			OperationName: fmt.Sprintf("operations/%s", op.ID),
+			State:         op.Status,
+			CreateTime:    op.CreatedAt,
+			ScheduleTime:  op.ScheduledAt,
+		}, nil
+	}
+
+	if err := dispatchStoryboardJob(ctx, s.data, s.dispatcher, story, op, job); err != nil {
+		return nil, err
 	}
 
 	return &CreateHomeResult{
@@ -152,9 +188,101 @@ func (s *HomeService) Create(ctx context.Context, userID uuid.UUID, params Creat
 	}, nil
 }
 
-func (s *HomeService) CreateBatch(ctx context.Context, userID uuid.UUID, items []CreateHomeParams, maxConcurrency int) ([]BatchCreateItemResult, error) {
+func (s *HomeService) holdFlaggedStory(ctx context.Context, story *model.Story, verdict *ModerationVerdict) error {
+	story.Status = global.StoryFlag
+	record, err := BuildModerationRecord(story.UserID, story.ID, nil, global.ModerationTargetScript, s.moderator.Name(), verdict, nil)
+	if err != nil {
+		return err
+	}
+	err = s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(story).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建故事记录失败", err)
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建审核记录失败", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	InvalidateStoryListCache(ctx, s.data, story.UserID)
+	s.logger.Warn(string(LogMsgContentFlagged),
+		zap.String(string(LogKeyStoryID), story.ID.String()),
+		zap.String(string(LogKeyUserID), story.UserID.String()),
+		zap.Strings("categories", verdict.Categories),
+	)
+	return NewLocalizedError(ErrCodeContentFlagged, MsgScriptHeldForReview, MessageParams{"story_id": story.ID})
+}
+
+func createStoryboardJob(tx *gorm.DB, story *model.Story, opts storyboardJobOptions) (*model.Operation, StoryJobMessage, error) {
+	payload := StoryJobPayload{
+		DisplayName:   story.Title,
+		ScriptContent: story.Content,
+		Style:         story.Style,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, StoryJobMessage{}, WrapServiceError(ErrCodeOperationCreateFailed, "序列化任务参数失败", err)
+	}
+
+	op := model.NewOperation(uuid.New(), story.UserID, story.ID, uuid.Nil, global.OpStoryboard, datatypes.JSON(payloadBytes))
+	op.BatchID = opts.BatchID
+	if opts.ScheduledAt != nil {
+		op.Status = global.OpScheduled
+		op.ScheduledAt = opts.ScheduledAt
+	}
+	if err := tx.Create(op).Error; err != nil {
+		return nil, StoryJobMessage{}, WrapServiceError(ErrCodeOperationCreateFailed, "创建任务记录失败", err)
+	}
+
+	job := StoryJobMessage{
+		OperationID: op.ID.String(),
+		StoryID:     story.ID.String(),
+		UserID:      story.UserID.String(),
+		Payload:     payload,
+		CreatedAt:   op.CreatedAt,
+	}
+	if opts.BatchID != nil {
+		job.BatchID = opts.BatchID.String()
+	}
+	return op, job, nil
+}
+
+func dispatchStoryboardJob(ctx context.Context, d *data.Data, dispatcher *jobDispatcher, story *model.Story, op *model.Operation, job StoryJobMessage) error {
+	err := dispatcher.Dispatch(ctx, job)
+	if err == nil {
+		return nil
+	}
+	_ = UpdateOperationFailure(ctx, d, op.ID, err)
+	_ = d.DB.WithContext(ctx).
+		Model(&model.Story{}).
+		Where("id = ?", story.ID).
+		Update("status", global.StoryFail).Error
+	if svcErr, ok := AsServiceError(err); ok {
+		return svcErr
+	}
+	return WrapServiceError(ErrCodeJobEnqueueFailed, "派发故事生成任务失败", err)
+}
+
+// MaxChapterRunes bounds the chapter size of uploaded documents so every
+// chapter can pass the script check on its own.
+func (s *HomeService) MaxChapterRunes() int {
+	return s.scripts.MaxChapterRunes()
+}
+
+func (s *HomeService) Estimate(style, scriptContent string) (*ScriptCheckResult, error) {
+	if style != "" {
+		if err := validateStyle(style); err != nil {
+			return nil, err
+		}
+	}
+	return s.scripts.Check(scriptContent), nil
+}
+
+func (s *HomeService) CreateBatch(ctx context.Context, userID uuid.UUID, items []CreateHomeParams, maxConcurrency int) (*model.Batch, []BatchCreateItemResult, error) {
 	if len(items) == 0 {
-		return nil, NewServiceError(ErrCodeInvalidRequest, "没有可生成的故事任务")
+		return nil, nil, NewServiceError(ErrCodeInvalidRequest, "没有可生成的故事任务")
 	}
 	if maxConcurrency <= 0 {
 		maxConcurrency = 1
@@ -163,44 +291,105 @@ func (s *HomeService) CreateBatch(ctx context.Context, userID uuid.UUID, items [
 		maxConcurrency = len(items)
 	}
 
-	results := make([]BatchCreateItemResult, len(items))
-	var wg sync.WaitGroup
 	pool, err := ants.NewPool(maxConcurrency)
 	if err != nil {
-		return nil, WrapServiceError(ErrCodeJobEnqueueFailed, "初始化批量任务协程池失败", err)
+		return nil, nil, WrapServiceError(ErrCodeJobEnqueueFailed, "初始化批量任务协程池失败", err)
 	}
 	defer pool.Release()
 
-	for idx, item := range items {
+	results := make([]BatchCreateItemResult, len(items))
+	prepared := make([]*preparedStory, len(items))
+	var accepted []int
+	for idx, err := range runPooled(ctx, pool, len(items), func(idx int) error {
+		var err error
+		prepared[idx], err = s.prepare(ctx, userID, items[idx])
+		return err
+	}) {
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+		accepted = append(accepted, idx)
+	}
+	if len(accepted) == 0 {
+		return nil, results, nil
+	}
+
+	batch := model.NewBatch(uuid.New(), userID, len(accepted))
+	ops := make([]*model.Operation, len(items))
+	jobs := make([]StoryJobMessage, len(items))
+	err = s.data.DB.WithContext(ctx).Transaction(func(txCtx *gorm.DB) error {
+		if err := txCtx.Create(batch).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建批量任务记录失败", err)
+		}
+		for _, idx := range accepted {
+			story := prepared[idx].story
+			if err := txCtx.Create(story).Error; err != nil {
+				return WrapServiceError(ErrCodeDatabaseActionFailed, "创建故事记录失败", err)
+			}
+			var err error
+			ops[idx], jobs[idx], err = createStoryboardJob(txCtx, story, storyboardJobOptions{
+				ScheduledAt: prepared[idx].scheduledAt,
+				BatchID:     &batch.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	InvalidateStoryListCache(ctx, s.data, userID)
+
+	launchCtx := context.WithoutCancel(ctx)
+	for pos, err := range runPooled(launchCtx, pool, len(accepted), func(pos int) error {
+		idx := accepted[pos]
+		var err error
+		results[idx].Result, err = s.launch(launchCtx, prepared[idx].story, ops[idx], jobs[idx])
+		return err
+	}) {
+		if err != nil {
+			results[accepted[pos]].Err = err
+		}
+	}
+	s.batches.Finalize(launchCtx, batch.ID)
+	return batch, results, nil
+}
+
+func runPooled(ctx context.Context, pool *ants.Pool, n int, fn func(int) error) []error {
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for idx := 0; idx < n; idx++ {
 		wg.Add(1)
 		index := idx
-		params := item
 		task := func() {
 			defer wg.Done()
 			if err := ctx.Err(); err != nil {
-				results[index].Err = err
+				errs[index] = err
 				return
 			}
-			res, err := s.Create(ctx, userID, params)
-			if err != nil {
-				results[index].Err = err
-				return
-			}
-			results[index].Result = res
+			errs[index] = fn(index)
 		}
 		if err := pool.Submit(task); err != nil {
 			wg.Done()
-			results[index].Err = err
+			errs[index] = err
 		}
 	}
-
 	wg.Wait()
-	return results, nil
+	return errs
+}
+
+func IsSupportedStyle(style string) bool {
+	_, ok := allowedStyles[style]
+	return ok
 }
 
 func validateStyle(style string) error {
 	if _, ok := allowedStyles[style]; !ok {
-		return WrapServiceError(ErrCodeInvalidStyle, fmt.Sprintf("不支持的风格: %s", style), nil)
+		return NewLocalizedError(ErrCodeInvalidStyle, MsgStyleUnsupported, MessageParams{"style": style})
 	}
 	return nil
 }
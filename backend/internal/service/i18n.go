@@ -0,0 +1,324 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	LocaleZhCN    = "zh-CN"
+	LocaleEnUS    = "en-US"
+	DefaultLocale = LocaleZhCN
+)
+
+type MessageKey string
+
+type MessageParams map[string]interface{}
+
+const (
+	MsgPageTokenInvalid              MessageKey = "page_token.invalid"
+	MsgPageTokenSignature            MessageKey = "page_token.signature"
+	MsgPageTokenMismatch             MessageKey = "page_token.mismatch"
+	MsgSortFieldUnsupported          MessageKey = "story.sort_field_unsupported"
+	MsgSortOrderUnsupported          MessageKey = "story.sort_order_unsupported"
+	MsgStyleUnsupported              MessageKey = "story.style_unsupported"
+	MsgScriptEmpty                   MessageKey = "script.empty"
+	MsgScriptTooShort                MessageKey = "script.too_short"
+	MsgScriptTooLong                 MessageKey = "script.too_long"
+	MsgScriptLanguage                MessageKey = "script.language"
+	MsgScriptBannedWord              MessageKey = "script.banned_word"
+	MsgScriptTooManyShots            MessageKey = "script.too_many_shots"
+	MsgScriptHeldForReview           MessageKey = "script.held_for_review"
+	MsgShotHeldForReview             MessageKey = "shot.held_for_review"
+	MsgShotsHeldForReview            MessageKey = "shot.many_held_for_review"
+	MsgShotsRejected                 MessageKey = "shot.rejected"
+	MsgStoryForkHeldForReview        MessageKey = "story.fork_held_for_review"
+	MsgStoryHeldForReview            MessageKey = "story.held_for_review"
+	MsgImportScriptFlagged           MessageKey = "script.import_flagged"
+	MsgShotNotInStory                MessageKey = "shot.not_in_story"
+	MsgShotScriptMissing             MessageKey = "shot.script_missing"
+	MsgShotSequenceDuplicate         MessageKey = "shot.sequence_duplicate"
+	MsgShotsPending                  MessageKey = "shot.pending"
+	MsgShotsContentMissing           MessageKey = "shot.content_missing"
+	MsgShotsAssetMissing             MessageKey = "shot.asset_missing"
+	MsgInlineImageTooLarge           MessageKey = "asset.inline_image_too_large"
+	MsgAssetURLNotAllowed            MessageKey = "asset.url_not_allowed"
+	MsgDocumentFormatUnsupported     MessageKey = "document.format_unsupported"
+	MsgSubtitleFormatUnsupported     MessageKey = "subtitle.format_unsupported"
+	MsgExportFormatUnsupported       MessageKey = "export.format_unsupported"
+	MsgManifestVersionUnsupported    MessageKey = "import.manifest_version_unsupported"
+	MsgOperationTypeUnsupported      MessageKey = "operation.type_unsupported"
+	MsgOperationStatusUnsupported    MessageKey = "operation.status_unsupported"
+	MsgModerationDecisionUnsupported MessageKey = "moderation.decision_unsupported"
+	MsgModerationTargetUnknown       MessageKey = "moderation.target_unknown"
+	MsgFieldRequired                 MessageKey = "field.required"
+	MsgFieldInvalid                  MessageKey = "field.invalid"
+	MsgFieldOneOf                    MessageKey = "field.oneof"
+	MsgFieldMin                      MessageKey = "field.min"
+	MsgFieldMax                      MessageKey = "field.max"
+	MsgFieldType                     MessageKey = "field.type"
+	MsgFieldConflict                 MessageKey = "field.conflict"
+	MsgFieldFuture                   MessageKey = "field.future"
+	MsgFileTooLarge                  MessageKey = "field.file_too_large"
+	MsgBodyMalformed                 MessageKey = "body.malformed"
+	MsgBodyEmpty                     MessageKey = "body.empty"
+	MsgUploadTooManyChapters         MessageKey = "upload.too_many_chapters"
+)
+
+var (
+	errorCodeCatalog = map[string]map[ErrorCode]string{
+		LocaleZhCN: {
+			ErrCodeInvalidRequest:          "请求参数不合法",
+			ErrCodeInvalidStyle:            "不支持的风格",
+			ErrCodeInvalidShotDetails:      "镜头脚本内容无效",
+			ErrCodeUnsupportedDocument:     "不支持的文档格式",
+			ErrCodeDocumentParseFailed:     "文档解析失败",
+			ErrCodeScriptEmpty:             "剧本内容为空",
+			ErrCodeScriptTooShort:          "剧本内容过短",
+			ErrCodeScriptTooLong:           "剧本内容过长",
+			ErrCodeScriptLanguage:          "剧本语言不受支持",
+			ErrCodeScriptBannedContent:     "剧本包含违禁内容",
+			ErrCodeScriptTooManyShots:      "预计镜头数超出上限",
+			ErrCodeContentFlagged:          "内容未通过安全审核",
+			ErrCodePayloadTooLarge:         "请求内容过大",
+			ErrCodeStoryNotFound:           "未找到对应故事",
+			ErrCodeShotNotFound:            "未找到对应镜头",
+			ErrCodeOperationNotFound:       "未找到对应任务",
+			ErrCodeModerationNotFound:      "未找到对应审核记录",
+			ErrCodeBatchNotFound:           "未找到对应批量任务",
+			ErrCodeMethodNotFound:          "未找到对应接口",
+			ErrCodeUnauthenticated:         "缺少或无效的用户身份",
+			ErrCodePermissionDenied:        "没有访问权限",
+			ErrCodeOperationCreateFailed:   "创建任务失败",
+			ErrCodeOperationUpdateFailed:   "更新任务状态失败",
+			ErrCodeOperationTimeout:        "任务执行超时",
+			ErrCodeExportNotReady:          "导出文件尚未生成",
+			ErrCodeOperationNotCancellable: "任务已开始执行，无法取消",
+			ErrCodeOperationNotScheduled:   "任务不处于定时等待状态，无法调整执行时间",
+			ErrCodeOperationNotRetryable:   "仅失败的任务可以重试",
+			ErrCodeKafkaConfigInvalid:      "Kafka 配置错误",
+			ErrCodeJobEnqueueFailed:        "任务投递失败",
+			ErrCodeWorkerExecutionFailed:   "工作节点执行失败",
+			ErrCodeResultDataMissing:       "任务结果缺失",
+			ErrCodeExportFailed:            "故事导出失败",
+			ErrCodeModerationFailed:        "内容审核服务不可用",
+			ErrCodeShotMissingPartial:      "部分镜头缺失",
+			ErrCodeShotContentMissing:      "镜头内容缺失或不完整",
+			ErrCodeShotAssetMissing:        "镜头素材缺失或损坏",
+			ErrCodeInternal:                "服务内部错误",
+			ErrCodeDatabaseActionFailed:    "数据库操作失败",
+		},
+		LocaleEnUS: {
+			ErrCodeInvalidRequest:          "Invalid request parameters",
+			ErrCodeInvalidStyle:            "Unsupported style",
+			ErrCodeInvalidShotDetails:      "Invalid shot script",
+			ErrCodeUnsupportedDocument:     "Unsupported document format",
+			ErrCodeDocumentParseFailed:     "Failed to parse document",
+			ErrCodeScriptEmpty:             "Script is empty",
+			ErrCodeScriptTooShort:          "Script is too short",
+			ErrCodeScriptTooLong:           "Script is too long",
+			ErrCodeScriptLanguage:          "Script language is not supported",
+			ErrCodeScriptBannedContent:     "Script contains prohibited content",
+			ErrCodeScriptTooManyShots:      "Estimated shot count exceeds the limit",
+			ErrCodeContentFlagged:          "Content did not pass safety review",
+			ErrCodePayloadTooLarge:         "Request payload is too large",
+			ErrCodeStoryNotFound:           "Story not found",
+			ErrCodeShotNotFound:            "Shot not found",
+			ErrCodeOperationNotFound:       "Operation not found",
+			ErrCodeModerationNotFound:      "Moderation record not found",
+			ErrCodeBatchNotFound:           "Batch not found",
+			ErrCodeMethodNotFound:          "API method not found",
+			ErrCodeUnauthenticated:         "Missing or invalid user identity",
+			ErrCodePermissionDenied:        "Permission denied",
+			ErrCodeOperationCreateFailed:   "Failed to create operation",
+			ErrCodeOperationUpdateFailed:   "Failed to update operation status",
+			ErrCodeOperationTimeout:        "Operation timed out",
+			ErrCodeExportNotReady:          "Export file is not ready yet",
+			ErrCodeOperationNotCancellable: "Operation has already started and cannot be cancelled",
+			ErrCodeOperationNotScheduled:   "Operation is not scheduled and cannot be rescheduled",
+			ErrCodeOperationNotRetryable:   "Only failed operations can be retried",
+			ErrCodeKafkaConfigInvalid:      "Invalid Kafka configuration",
+			ErrCodeJobEnqueueFailed:        "Failed to enqueue job",
+			ErrCodeWorkerExecutionFailed:   "Worker execution failed",
+			ErrCodeResultDataMissing:       "Job result is missing",
+			ErrCodeExportFailed:            "Failed to export story",
+			ErrCodeModerationFailed:        "Content moderation service is unavailable",
+			ErrCodeShotMissingPartial:      "Some shots are missing",
+			ErrCodeShotContentMissing:      "Shot content is missing or incomplete",
+			ErrCodeShotAssetMissing:        "Shot assets are missing or corrupted",
+			ErrCodeInternal:                "Internal server error",
+			ErrCodeDatabaseActionFailed:    "Database operation failed",
+		},
+	}
+	messageCatalog = map[string]map[MessageKey]string{
+		LocaleZhCN: {
+			MsgPageTokenInvalid:              "page_token 无效",
+			MsgPageTokenSignature:            "page_token 校验失败",
+			MsgPageTokenMismatch:             "page_token 与当前查询条件不匹配",
+			MsgSortFieldUnsupported:          "不支持的排序字段: {sort_by}",
+			MsgSortOrderUnsupported:          "不支持的排序方向: {order}",
+			MsgStyleUnsupported:              "不支持的风格: {style}",
+			MsgScriptEmpty:                   "剧本内容不能为空",
+			MsgScriptTooShort:                "剧本至少需要 {min} 个字符，当前 {count} 个",
+			MsgScriptTooLong:                 "剧本最多 {max} 个字符，当前 {count} 个",
+			MsgScriptLanguage:                "暂不支持该语言的剧本: {language}",
+			MsgScriptBannedWord:              "剧本包含禁止使用的词语「{word}」",
+			MsgScriptTooManyShots:            "预计生成 {shots} 个镜头，超过上限 {max}，请精简剧本或拆分章节",
+			MsgScriptHeldForReview:           "剧本内容需人工审核，故事 {story_id} 已暂停生成",
+			MsgShotHeldForReview:             "镜头 {sequence} 内容需人工审核",
+			MsgShotsHeldForReview:            "{count} 个镜头内容需人工审核",
+			MsgShotsRejected:                 "{count} 个镜头未通过人工审核",
+			MsgStoryForkHeldForReview:        "故事 {story_id} 含有待审核或已驳回的内容，暂不能复制",
+			MsgStoryHeldForReview:            "故事 {story_id} 含有待审核的内容，审核通过前不能执行该操作",
+			MsgImportScriptFlagged:           "导入的剧本未通过内容审核，请修改后重试",
+			MsgShotNotInStory:                "镜头 {shot_id} 不属于该故事",
+			MsgShotScriptMissing:             "镜头 {shot_id} 缺少脚本，无法重新生成",
+			MsgShotSequenceDuplicate:         "shots[{index}] 序号重复: {sequence}",
+			MsgShotsPending:                  "仍有 {count} 个镜头未生成完成",
+			MsgShotsContentMissing:           "{count} 个镜头内容缺失",
+			MsgShotsAssetMissing:             "{count} 个镜头素材缺失",
+			MsgInlineImageTooLarge:           "内联图片超过 {limit} 字节限制",
+			MsgAssetURLNotAllowed:            "{field} 不是允许的素材地址",
+			MsgDocumentFormatUnsupported:     "不支持的文档格式: {filename}，仅支持 txt/md/docx/epub",
+			MsgSubtitleFormatUnsupported:     "不支持的字幕格式: {format}",
+			MsgExportFormatUnsupported:       "不支持的导出格式: {format}",
+			MsgManifestVersionUnsupported:    "不支持的清单版本: {version}",
+			MsgOperationTypeUnsupported:      "不支持的任务类型: {type}",
+			MsgOperationStatusUnsupported:    "不支持的任务状态: {status}",
+			MsgModerationDecisionUnsupported: "不支持的审核结论: {decision}",
+			MsgModerationTargetUnknown:       "未知的审核对象: {target}",
+			MsgFieldRequired:                 "不能为空",
+			MsgFieldInvalid:                  "取值不合法",
+			MsgFieldOneOf:                    "必须是以下取值之一: {values}",
+			MsgFieldMin:                      "不能小于 {limit}",
+			MsgFieldMax:                      "不能大于 {limit}",
+			MsgFieldType:                     "类型错误，应为 {type}",
+			MsgFieldConflict:                 "与 {other} 无交集",
+			MsgFieldFuture:                   "必须晚于当前时间",
+			MsgFileTooLarge:                  "文件不能超过 {limit} MB",
+			MsgBodyMalformed:                 "请求体不是合法的 JSON",
+			MsgBodyEmpty:                     "请求体不能为空",
+			MsgUploadTooManyChapters:         "文档被拆分为 {count} 个章节，超过单次上限 {max}，请调大 max_chapter_chars 或拆分文件",
+		},
+		LocaleEnUS: {
+			MsgPageTokenInvalid:              "Invalid page_token",
+			MsgPageTokenSignature:            "page_token signature check failed",
+			MsgPageTokenMismatch:             "page_token does not match the current query",
+			MsgSortFieldUnsupported:          "Unsupported sort field: {sort_by}",
+			MsgSortOrderUnsupported:          "Unsupported sort order: {order}",
+			MsgStyleUnsupported:              "Unsupported style: {style}",
+			MsgScriptEmpty:                   "Script content must not be empty",
+			MsgScriptTooShort:                "Script needs at least {min} characters, got {count}",
+			MsgScriptTooLong:                 "Script allows at most {max} characters, got {count}",
+			MsgScriptLanguage:                "Scripts in this language are not supported yet: {language}",
+			MsgScriptBannedWord:              "Script contains the prohibited word \"{word}\"",
+			MsgScriptTooManyShots:            "Estimated {shots} shots exceeds the limit of {max}; shorten the script or split it into chapters",
+			MsgScriptHeldForReview:           "Script is pending manual review; generation of story {story_id} is paused",
+			MsgShotHeldForReview:             "Shot {sequence} is pending manual review",
+			MsgShotsHeldForReview:            "{count} shots are pending manual review",
+			MsgShotsRejected:                 "{count} shots were rejected by manual review",
+			MsgStoryForkHeldForReview:        "Story {story_id} has content under review or rejected by review and cannot be duplicated",
+			MsgStoryHeldForReview:            "Story {story_id} has content under review; this action is unavailable until it is approved",
+			MsgImportScriptFlagged:           "The imported script did not pass content moderation; revise it and try again",
+			MsgShotNotInStory:                "Shot {shot_id} does not belong to this story",
+			MsgShotScriptMissing:             "Shot {shot_id} has no script and cannot be regenerated",
+			MsgShotSequenceDuplicate:         "shots[{index}] has a duplicate sequence: {sequence}",
+			MsgShotsPending:                  "{count} shots are still being generated",
+			MsgShotsContentMissing:           "{count} shots are missing content",
+			MsgShotsAssetMissing:             "{count} shots are missing assets",
+			MsgInlineImageTooLarge:           "Inline image exceeds the {limit} byte limit",
+			MsgAssetURLNotAllowed:            "{field} is not an allowed asset URL",
+			MsgDocumentFormatUnsupported:     "Unsupported document format: {filename}; only txt/md/docx/epub are supported",
+			MsgSubtitleFormatUnsupported:     "Unsupported subtitle format: {format}",
+			MsgExportFormatUnsupported:       "Unsupported export format: {format}",
+			MsgManifestVersionUnsupported:    "Unsupported manifest version: {version}",
+			MsgOperationTypeUnsupported:      "Unsupported operation type: {type}",
+			MsgOperationStatusUnsupported:    "Unsupported operation status: {status}",
+			MsgModerationDecisionUnsupported: "Unsupported review decision: {decision}",
+			MsgModerationTargetUnknown:       "Unknown moderation target: {target}",
+			MsgFieldRequired:                 "is required",
+			MsgFieldInvalid:                  "has an invalid value",
+			MsgFieldOneOf:                    "must be one of: {values}",
+			MsgFieldMin:                      "must be at least {limit}",
+			MsgFieldMax:                      "must be at most {limit}",
+			MsgFieldType:                     "must be of type {type}",
+			MsgFieldConflict:                 "does not overlap with {other}",
+			MsgFieldFuture:                   "must be in the future",
+			MsgFileTooLarge:                  "file must not exceed {limit} MB",
+			MsgBodyMalformed:                 "request body is not valid JSON",
+			MsgBodyEmpty:                     "request body must not be empty",
+			MsgUploadTooManyChapters:         "Document was split into {count} chapters, exceeding the limit of {max}; increase max_chapter_chars or split the file",
+		},
+	}
+	messageParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+)
+
+func SupportedLocales() []string {
+	return []string{LocaleZhCN, LocaleEnUS}
+}
+
+func NormalizeLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	primary, _, _ := strings.Cut(tag, "-")
+	switch primary {
+	case "zh":
+		return LocaleZhCN, true
+	case "en":
+		return LocaleEnUS, true
+	}
+	return "", false
+}
+
+func NewLocalizedError(code ErrorCode, key MessageKey, params MessageParams) *ServiceError {
+	return &ServiceError{
+		Code:    code,
+		Message: Localize(DefaultLocale, key, params),
+		Key:     key,
+		Params:  params,
+	}
+}
+
+func Localize(locale string, key MessageKey, params MessageParams) string {
+	tpl, ok := messageCatalog[locale][key]
+	if !ok {
+		if tpl, ok = messageCatalog[DefaultLocale][key]; !ok {
+			return string(key)
+		}
+	}
+	return interpolate(tpl, params)
+}
+
+func (c ErrorCode) LocalizedMessage(locale string) string {
+	if msg, ok := errorCodeCatalog[locale][c]; ok {
+		return msg
+	}
+	return errorCodeCatalog[DefaultLocale][c]
+}
+
+func (e *ServiceError) LocalizedMessage(locale string) string {
+	if e == nil {
+		return ""
+	}
+	if locale == "" || locale == DefaultLocale {
+		if msg := e.UserMessage(); msg != "" {
+			return msg
+		}
+	}
+	if e.Key != "" {
+		return Localize(locale, e.Key, e.Params)
+	}
+	return e.Code.LocalizedMessage(locale)
+}
+
+func interpolate(tpl string, params MessageParams) string {
+	if len(params) == 0 {
+		return tpl
+	}
+	return messageParamPattern.ReplaceAllStringFunc(tpl, func(m string) string {
+		if v, ok := params[m[1:len(m)-1]]; ok {
+			return fmt.Sprint(v)
+		}
+		return m
+	})
+}
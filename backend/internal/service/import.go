@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const defaultImportMaxBytes = 64 << 20
+
+type ImportService struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	assets     *AssetStore
+	hosts      *assetHostPolicy
+	scripts    *ScriptChecker
+	moderator  Moderator
+	maxBytes   int64
+	logger     *zap.Logger
+}
+
+type ImportOptions struct {
+	RegenerateMissingAssets bool
+}
+
+type ImportResult struct {
+	Story      *model.Story
+	Shots      []model.Shot
+	Operations []*model.Operation
+}
+
+func NewImportService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ImportService {
+	prod := newKafkaProducer(cfg, logger)
+	maxBytes := cfg.Storage.MaxImportBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultImportMaxBytes
+	}
+	return &ImportService{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, prod),
+		assets:     NewAssetStore(cfg),
+		hosts:      newAssetHostPolicy(cfg),
+		scripts:    NewScriptChecker(cfg),
+		moderator:  NewModerator(cfg, logger),
+		maxBytes:   maxBytes,
+		logger:     logger,
+	}
+}
+
+// MaxManifestBytes bounds the request body of an import, inline images
+// included.
+func (s *ImportService) MaxManifestBytes() int64 {
+	return s.maxBytes
+}
+
+func (s *ImportService) Close() error {
+	if s == nil || s.dispatcher == nil {
+		return nil
+	}
+	return s.dispatcher.Close()
+}
+
+func (s *ImportService) Import(ctx context.Context, userID uuid.UUID, manifest *StoryManifest, opts ImportOptions) (*ImportResult, error) {
+	if manifest == nil {
+		return nil, NewServiceError(ErrCodeInvalidRequest, "导入清单不能为空")
+	}
+	if manifest.Version > StoryManifestVersion {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgManifestVersionUnsupported, MessageParams{"version": manifest.Version})
+	}
+	if strings.TrimSpace(manifest.Story.DisplayName) == "" {
+		return nil, NewServiceError(ErrCodeInvalidRequest, "story.display_name 不能为空")
+	}
+	if strings.TrimSpace(manifest.Story.ScriptContent) == "" {
+		return nil, NewServiceError(ErrCodeInvalidRequest, "story.script_content 不能为空")
+	}
+	if err := validateStyle(manifest.Story.Style); err != nil {
+		return nil, err
+	}
+	if len(manifest.Story.Timeline) > 0 && !json.Valid(manifest.Story.Timeline) {
+		return nil, NewServiceError(ErrCodeInvalidRequest, "story.timeline 不是合法的 JSON")
+	}
+	if max := s.scripts.MaxShots(); len(manifest.Shots) > max {
+		return nil, NewLocalizedError(ErrCodeScriptTooManyShots, MsgScriptTooManyShots, MessageParams{"shots": len(manifest.Shots), "max": max})
+	}
+
+	refs := []importAssetRef{
+		{field: "story.cover_url", url: strings.TrimSpace(manifest.Story.CoverURL)},
+		{field: "story.video_url", url: strings.TrimSpace(manifest.Story.VideoURL)},
+	}
+	for idx, item := range manifest.Shots {
+		refs = append(refs, importAssetRef{field: fmt.Sprintf("shots[%d].image_url", idx), url: strings.TrimSpace(item.ImageURL)})
+	}
+	if err := s.validateAssetURLs(ctx, userID, refs); err != nil {
+		return nil, err
+	}
+	if svcErr := s.scripts.Check(manifest.Story.ScriptContent).Err(); svcErr != nil {
+		return nil, svcErr
+	}
+	verdict, err := s.moderator.Moderate(ctx, ModerationInput{
+		Target: global.ModerationTargetScript,
+		Text:   manifest.Story.DisplayName + "\n" + manifest.Story.ScriptContent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Flagged {
+		s.logger.Warn(string(LogMsgContentFlagged),
+			zap.String(string(LogKeyUserID), userID.String()),
+			zap.Strings("categories", verdict.Categories),
+		)
+		return nil, NewLocalizedError(ErrCodeContentFlagged, MsgImportScriptFlagged, nil)
+	}
+
+	story := model.NewStory(uuid.New(), userID, manifest.Story.ScriptContent)
+	story.Title = manifest.Story.DisplayName
+	story.Style = manifest.Story.Style
+	story.Duration = manifest.Story.Duration
+	story.Status = global.StoryReady
+	story.CoverURL = strings.TrimSpace(manifest.Story.CoverURL)
+	story.VideoURL = strings.TrimSpace(manifest.Story.VideoURL)
+	if len(manifest.Story.Timeline) > 0 {
+		story.Timeline = datatypes.JSON(manifest.Story.Timeline)
+	}
+
+	shots := make([]model.Shot, 0, len(manifest.Shots))
+	seen := make(map[string]struct{}, len(manifest.Shots))
+	for idx, item := range manifest.Shots {
+		shot := model.NewShot(uuid.New(), userID, story.ID)
+		shot.Sequence = strings.TrimSpace(item.Sequence)
+		if shot.Sequence == "" {
+			shot.Sequence = strconv.Itoa(idx + 1)
+		}
+		if _, dup := seen[shot.Sequence]; dup {
+			return nil, NewLocalizedError(ErrCodeInvalidShotDetails, MsgShotSequenceDuplicate, MessageParams{"index": idx, "sequence": shot.Sequence})
+		}
+		seen[shot.Sequence] = struct{}{}
+		shot.Title = item.Title
+		shot.Description = item.Description
+		shot.Details = item.Details
+		shot.Narration = item.Narration
+		shot.Type = item.Type
+		if item.Transition != "" {
+			shot.Transition = item.Transition
+		}
+		shot.Voice = item.Voice
+		shot.BGM = item.BGM
+		shot.ImageURL = strings.TrimSpace(item.ImageURL)
+		shot.AudioDurationMs = item.AudioDurationMs
+		shot.Status = global.ShotDone
+		shots = append(shots, *shot)
+	}
+
+	for idx, item := range manifest.Shots {
+		if shots[idx].ImageURL != "" || strings.TrimSpace(item.ImageBase64) == "" {
+			continue
+		}
+		url, err := s.assets.SaveStoryImage(story.ID, shots[idx].ID, item.ImageBase64)
+		if err != nil {
+			_ = s.assets.RemoveStory(story.ID)
+			if svcErr, ok := AsServiceError(err); ok {
+				svcErr.Message = fmt.Sprintf("shots[%d] %s", idx, svcErr.Message)
+				return nil, svcErr
+			}
+			return nil, err
+		}
+		shots[idx].ImageURL = url
+	}
+	if svcErr := validateStoryShots(shots, !opts.RegenerateMissingAssets); svcErr != nil {
+		_ = s.assets.RemoveStory(story.ID)
+		return nil, svcErr
+	}
+	records, err := s.moderateShots(ctx, story, shots)
+	if err != nil {
+		_ = s.assets.RemoveStory(story.ID)
+		return nil, err
+	}
+	if story.CoverURL == "" {
+		for _, shot := range shots {
+			if shot.ImageURL != "" {
+				story.CoverURL = shot.ImageURL
+				break
+			}
+		}
+	}
+
+	var (
+		ops  []*model.Operation
+		jobs []StoryJobMessage
+	)
+	err = s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(story).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建故事记录失败", err)
+		}
+		for idx := range shots {
+			if shots[idx].ImageURL == "" && shots[idx].Status != global.ShotFlag {
+				shots[idx].Status = global.ShotRender
+			}
+		}
+		if err := tx.Create(&shots).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "创建镜头记录失败", err)
+		}
+		for _, record := range records {
+			if err := tx.Create(record).Error; err != nil {
+				return WrapServiceError(ErrCodeDatabaseActionFailed, "创建审核记录失败", err)
+			}
+		}
+		for idx := range shots {
+			if shots[idx].Status != global.ShotRender {
+				continue
+			}
+			op, job, err := createShotRegenJob(tx, story, &shots[idx])
+			if err != nil {
+				return err
+			}
+			ops = append(ops, op)
+			jobs = append(jobs, job)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = s.assets.RemoveStory(story.ID)
+		return nil, err
+	}
+
+	InvalidateStoryListCache(ctx, s.data, userID)
+
+	dispatchShotRegenJobs(ctx, s.data, s.dispatcher, s.logger, ops, jobs, shots)
+
+	return &ImportResult{Story: story, Shots: shots, Operations: ops}, nil
+}
+
+func (s *ImportService) moderateShots(ctx context.Context, story *model.Story, shots []model.Shot) ([]*model.ModerationRecord, error) {
+	var records []*model.ModerationRecord
+	for idx := range shots {
+		shot := &shots[idx]
+		content := ModerationShotContent{
+			Title:           shot.Title,
+			Description:     shot.Description,
+			Details:         shot.Details,
+			Narration:       shot.Narration,
+			Type:            shot.Type,
+			Transition:      shot.Transition,
+			Voice:           shot.Voice,
+			ImageURL:        shot.ImageURL,
+			BGM:             shot.BGM,
+			AudioDurationMs: shot.AudioDurationMs,
+		}
+		verdict, err := s.moderator.Moderate(ctx, content.Input())
+		if err != nil {
+			return nil, err
+		}
+		if !verdict.Flagged {
+			continue
+		}
+		record, err := BuildModerationRecord(story.UserID, story.ID, &shot.ID, global.ModerationTargetShot, s.moderator.Name(), verdict, content)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		if story.CoverURL != "" && story.CoverURL == shot.ImageURL {
+			story.CoverURL = ""
+		}
+		*shot = model.Shot{
+			BaseModel: shot.BaseModel,
+			StoryID:   shot.StoryID,
+			Sequence:  shot.Sequence,
+			Status:    global.ShotFlag,
+		}
+		s.logger.Warn(string(LogMsgContentFlagged),
+			zap.String(string(LogKeyStoryID), story.ID.String()),
+			zap.String(string(LogKeyShotID), shot.ID.String()),
+			zap.Strings("categories", verdict.Categories),
+		)
+	}
+	if len(records) > 0 {
+		story.Status = global.StoryFlag
+	}
+	return records, nil
+}
+
+type importAssetRef struct {
+	field string
+	url   string
+}
+
+func (s *ImportService) validateAssetURLs(ctx context.Context, userID uuid.UUID, refs []importAssetRef) error {
+	local := make(map[uuid.UUID]struct{}, len(refs))
+	for _, ref := range refs {
+		if ref.url == "" {
+			continue
+		}
+		if !s.assets.IsLocal(ref.url) {
+			if _, ok := s.hosts.Allows(ref.url); !ok {
+				return NewLocalizedError(ErrCodeInvalidRequest, MsgAssetURLNotAllowed, MessageParams{"field": ref.field})
+			}
+			continue
+		}
+		storyID, ok := s.assets.StoryAssetID(ref.url)
+		if !ok {
+			return NewLocalizedError(ErrCodeInvalidRequest, MsgAssetURLNotAllowed, MessageParams{"field": ref.field})
+		}
+		local[storyID] = struct{}{}
+	}
+	if len(local) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(local))
+	for id := range local {
+		ids = append(ids, id)
+	}
+	var owned []uuid.UUID
+	if err := s.data.DB.WithContext(ctx).
+		Model(&model.Story{}).
+		Where("id IN ? AND user_id = ?", ids, userID).
+		Pluck("id", &owned).Error; err != nil {
+		return WrapServiceError(ErrCodeDatabaseActionFailed, "查询素材所属故事失败", err)
+	}
+	for _, id := range owned {
+		delete(local, id)
+	}
+	for _, ref := range refs {
+		if storyID, ok := s.assets.StoryAssetID(ref.url); ok {
+			if _, missing := local[storyID]; missing {
+				return NewLocalizedError(ErrCodeInvalidRequest, MsgAssetURLNotAllowed, MessageParams{"field": ref.field})
+			}
+		}
+	}
+	return nil
+}
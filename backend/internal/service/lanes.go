@@ -0,0 +1,139 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/global"
+)
+
+const (
+	LaneDefault     = "default"
+	LaneStoryCreate = "story_create"
+	LaneShotRegen   = "shot_regen"
+	LaneVideoRender = "video_render"
+	LaneStoryExport = "story_export"
+	LanePriority    = "priority"
+)
+
+var actionLanes = map[string]string{
+	"":                 LaneStoryCreate,
+	"regen_shot":       LaneShotRegen,
+	"render_video":     LaneVideoRender,
+	"export_story":     LaneStoryExport,
+	StageActionScript:  LaneStoryCreate,
+	StageActionImage:   LaneShotRegen,
+	StageActionAudio:   LaneShotRegen,
+	StageActionCompose: LaneVideoRender,
+}
+
+var operationActions = map[string]string{
+	global.OpStoryboard:  "",
+	global.OpShotRegen:   "regen_shot",
+	global.OpVideoRender: "render_video",
+	global.OpExport:      "export_story",
+	global.OpLLM:         StageActionScript,
+	global.OpT2I:         StageActionImage,
+	global.OpTTS:         StageActionAudio,
+	global.OpCompose:     StageActionCompose,
+}
+
+func dispatchableOperationTypes() []string {
+	types := make([]string, 0, len(operationActions))
+	for opType := range operationActions {
+		types = append(types, opType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+type JobLane struct {
+	Name        string
+	Topic       string
+	Concurrency int
+}
+
+func JobLanes(cfg conf.Kafka) []JobLane {
+	if len(cfg.Lanes) == 0 {
+		return []JobLane{{Name: LaneDefault, Topic: cfg.Topic}}
+	}
+	lanes := make([]JobLane, 0, len(cfg.Lanes)+1)
+	coversDefault := false
+	for name, lane := range cfg.Lanes {
+		topic := lane.Topic
+		if topic == "" {
+			topic = cfg.Topic
+		}
+		coversDefault = coversDefault || topic == cfg.Topic
+		lanes = append(lanes, JobLane{Name: name, Topic: topic, Concurrency: lane.Concurrency})
+	}
+	if !coversDefault && cfg.Topic != "" {
+		if _, ok := cfg.Lanes[LaneDefault]; !ok {
+			lanes = append(lanes, JobLane{Name: LaneDefault, Topic: cfg.Topic})
+		}
+	}
+	sort.Slice(lanes, func(i, j int) bool { return lanes[i].Name < lanes[j].Name })
+	return lanes
+}
+
+func KafkaTopics(cfg conf.Kafka) []string {
+	seen := make(map[string]struct{})
+	var topics []string
+	for _, lane := range JobLanes(cfg) {
+		if lane.Topic == "" {
+			continue
+		}
+		if _, ok := seen[lane.Topic]; ok {
+			continue
+		}
+		seen[lane.Topic] = struct{}{}
+		topics = append(topics, lane.Topic)
+	}
+	if _, ok := seen[cfg.BatchEventTopic]; cfg.BatchEventTopic != "" && !ok {
+		topics = append(topics, cfg.BatchEventTopic)
+	}
+	return topics
+}
+
+type laneRouter struct {
+	defaultTopic  string
+	topics        map[string]string
+	priorityUsers map[string]struct{}
+}
+
+func newLaneRouter(cfg conf.Kafka) *laneRouter {
+	r := &laneRouter{
+		defaultTopic:  cfg.Topic,
+		topics:        make(map[string]string, len(cfg.Lanes)),
+		priorityUsers: make(map[string]struct{}, len(cfg.PriorityUserIDs)),
+	}
+	for name, lane := range cfg.Lanes {
+		if lane.Topic != "" {
+			r.topics[name] = lane.Topic
+		}
+	}
+	for _, id := range cfg.PriorityUserIDs {
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			r.priorityUsers[id] = struct{}{}
+		}
+	}
+	return r
+}
+
+func (r *laneRouter) Route(job StoryJobMessage) (string, string) {
+	if _, ok := r.priorityUsers[strings.ToLower(job.UserID)]; ok {
+		if topic, ok := r.topics[LanePriority]; ok {
+			return LanePriority, topic
+		}
+	}
+	if lane, ok := actionLanes[job.Payload.Action]; ok {
+		if topic, ok := r.topics[lane]; ok {
+			return lane, topic
+		}
+	}
+	if topic, ok := r.topics[LaneDefault]; ok {
+		return LaneDefault, topic
+	}
+	return LaneDefault, r.defaultTopic
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"story2video-backend/internal/model"
+)
+
+const StoryManifestVersion = 1
+
+type StoryManifest struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Story      ManifestStory  `json:"story"`
+	Shots      []ManifestShot `json:"shots"`
+}
+
+type ManifestStory struct {
+	ID            string          `json:"id,omitempty"`
+	DisplayName   string          `json:"display_name"`
+	ScriptContent string          `json:"script_content"`
+	Style         string          `json:"style"`
+	Duration      int             `json:"duration,omitempty"`
+	Status        string          `json:"status,omitempty"`
+	Timeline      json.RawMessage `json:"timeline,omitempty"`
+	CoverURL      string          `json:"cover_url,omitempty"`
+	VideoURL      string          `json:"video_url,omitempty"`
+	CreatedAt     *time.Time      `json:"created_at,omitempty"`
+}
+
+type ManifestShot struct {
+	ID              string `json:"id,omitempty"`
+	Sequence        string `json:"sequence"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	Details         string `json:"details"`
+	Narration       string `json:"narration"`
+	Type            string `json:"type,omitempty"`
+	Transition      string `json:"transition,omitempty"`
+	Voice           string `json:"voice,omitempty"`
+	BGM             string `json:"bgm,omitempty"`
+	Status          string `json:"status,omitempty"`
+	ImageURL        string `json:"image_url,omitempty"`
+	ImageFile       string `json:"image_file,omitempty"`
+	ImageBase64     string `json:"image_base64,omitempty"`
+	AudioDurationMs int64  `json:"audio_duration_ms,omitempty"`
+}
+
+func NewStoryManifest(story *model.Story, shots []model.Shot) *StoryManifest {
+	createdAt := story.CreatedAt
+	manifest := &StoryManifest{
+		Version:    StoryManifestVersion,
+		ExportedAt: time.Now(),
+		Story: ManifestStory{
+			ID:            story.ID.String(),
+			DisplayName:   story.Title,
+			ScriptContent: story.Content,
+			Style:         story.Style,
+			Duration:      story.Duration,
+			Status:        story.Status,
+			CoverURL:      story.CoverURL,
+			VideoURL:      story.VideoURL,
+			CreatedAt:     &createdAt,
+		},
+		Shots: make([]ManifestShot, 0, len(shots)),
+	}
+	if len(story.Timeline) > 0 && json.Valid(story.Timeline) {
+		manifest.Story.Timeline = json.RawMessage(story.Timeline)
+	}
+	for _, shot := range shots {
+		manifest.Shots = append(manifest.Shots, ManifestShot{
+			ID:              shot.ID.String(),
+			Sequence:        shot.Sequence,
+			Title:           shot.Title,
+			Description:     shot.Description,
+			Details:         shot.Details,
+			Narration:       shot.Narration,
+			Type:            shot.Type,
+			Transition:      shot.Transition,
+			Voice:           shot.Voice,
+			BGM:             shot.BGM,
+			Status:          shot.Status,
+			ImageURL:        shot.ImageURL,
+			AudioDurationMs: shot.AudioDurationMs,
+		})
+	}
+	return manifest
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"story2video-backend/internal/conf"
+)
+
+const (
+	ModerationBackendKeyword = "keyword"
+	ModerationBackendHTTP    = "http"
+	ModerationBackendNone    = "none"
+
+	defaultModerationTimeout  = 10 * time.Second
+	moderationResponseMaxSize = 1 << 20
+)
+
+type ModerationInput struct {
+	Target   string `json:"target"`
+	Text     string `json:"text"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type ModerationVerdict struct {
+	Flagged    bool     `json:"flagged"`
+	Categories []string `json:"categories"`
+	Reason     string   `json:"reason"`
+}
+
+type Moderator interface {
+	Name() string
+	Moderate(ctx context.Context, input ModerationInput) (*ModerationVerdict, error)
+}
+
+func NewModerator(cfg *conf.Config, logger *zap.Logger) Moderator {
+	if cfg == nil || !cfg.Moderation.Enabled {
+		return noopModerator{}
+	}
+	mc := cfg.Moderation
+	var inner Moderator
+	switch strings.ToLower(strings.TrimSpace(mc.Backend)) {
+	case ModerationBackendNone:
+		return noopModerator{}
+	case ModerationBackendHTTP:
+		if strings.TrimSpace(mc.HTTPURL) == "" {
+			logger.Warn(string(LogMsgModerationFailed), zap.String("reason", "moderation.http_url is empty, falling back to keyword backend"))
+			inner = newKeywordModerator(mc, logger)
+			break
+		}
+		inner = newHTTPModerator(mc)
+	default:
+		inner = newKeywordModerator(mc, logger)
+	}
+	return &guardedModerator{inner: inner, failOpen: mc.FailOpen, logger: logger}
+}
+
+type noopModerator struct{}
+
+func (noopModerator) Name() string { return ModerationBackendNone }
+
+func (noopModerator) Moderate(context.Context, ModerationInput) (*ModerationVerdict, error) {
+	return &ModerationVerdict{}, nil
+}
+
+type keywordRule struct {
+	category string
+	keyword  string
+	pattern  *regexp.Regexp
+}
+
+type keywordModerator struct {
+	rules []keywordRule
+}
+
+func newKeywordModerator(mc conf.Moderation, logger *zap.Logger) *keywordModerator {
+	m := &keywordModerator{}
+	for _, entry := range mc.Keywords {
+		category, word := splitModerationRule(entry)
+		if word = strings.ToLower(word); word != "" {
+			m.rules = append(m.rules, keywordRule{category: category, keyword: word})
+		}
+	}
+	for _, entry := range mc.Patterns {
+		category, expr := splitModerationRule(entry)
+		if expr == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			logger.Warn(string(LogMsgModerationFailed), zap.String("pattern", expr), zap.Error(err))
+			continue
+		}
+		m.rules = append(m.rules, keywordRule{category: category, pattern: re})
+	}
+	return m
+}
+
+func splitModerationRule(entry string) (string, string) {
+	entry = strings.TrimSpace(entry)
+	if idx := strings.Index(entry, "::"); idx > 0 {
+		return strings.TrimSpace(entry[:idx]), strings.TrimSpace(entry[idx+2:])
+	}
+	return "keyword", entry
+}
+
+func (m *keywordModerator) Name() string { return ModerationBackendKeyword }
+
+func (m *keywordModerator) Moderate(_ context.Context, input ModerationInput) (*ModerationVerdict, error) {
+	verdict := &ModerationVerdict{}
+	if len(m.rules) == 0 {
+		return verdict, nil
+	}
+	text := input.Text
+	if input.ImageURL != "" && !strings.HasPrefix(input.ImageURL, "data:") {
+		text += "\n" + input.ImageURL
+	}
+	lower := strings.ToLower(text)
+	seen := make(map[string]struct{})
+	var matches []string
+	for _, rule := range m.rules {
+		var hit string
+		if rule.pattern != nil {
+			hit = rule.pattern.FindString(text)
+		} else if strings.Contains(lower, rule.keyword) {
+			hit = rule.keyword
+		}
+		if hit == "" {
+			continue
+		}
+		if _, ok := seen[rule.category]; !ok {
+			seen[rule.category] = struct{}{}
+			verdict.Categories = append(verdict.Categories, rule.category)
+		}
+		matches = append(matches, hit)
+	}
+	if len(matches) > 0 {
+		verdict.Flagged = true
+		verdict.Reason = fmt.Sprintf("命中敏感词规则: %s", strings.Join(matches, ", "))
+	}
+	return verdict, nil
+}
+
+type httpModerator struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newHTTPModerator(mc conf.Moderation) *httpModerator {
+	timeout := time.Duration(mc.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultModerationTimeout
+	}
+	return &httpModerator{
+		url:    strings.TrimSpace(mc.HTTPURL),
+		token:  strings.TrimSpace(mc.HTTPToken),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (m *httpModerator) Name() string { return ModerationBackendHTTP }
+
+func (m *httpModerator) Moderate(ctx context.Context, input ModerationInput) (*ModerationVerdict, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("marshal moderation request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.token != "" {
+		req.Header.Set("Authorization", "Bearer "+m.token)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call moderation service: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, moderationResponseMaxSize))
+	if err != nil {
+		return nil, fmt.Errorf("read moderation response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	var verdict ModerationVerdict
+	if err := json.Unmarshal(raw, &verdict); err != nil {
+		return nil, fmt.Errorf("decode moderation response: %w", err)
+	}
+	return &verdict, nil
+}
+
+type guardedModerator struct {
+	inner    Moderator
+	failOpen bool
+	logger   *zap.Logger
+}
+
+func (m *guardedModerator) Name() string { return m.inner.Name() }
+
+func (m *guardedModerator) Moderate(ctx context.Context, input ModerationInput) (*ModerationVerdict, error) {
+	if strings.TrimSpace(input.Text) == "" && input.ImageURL == "" {
+		return &ModerationVerdict{}, nil
+	}
+	verdict, err := m.inner.Moderate(ctx, input)
+	if err == nil {
+		if verdict == nil {
+			verdict = &ModerationVerdict{}
+		}
+		return verdict, nil
+	}
+	m.logger.Warn(string(LogMsgModerationFailed),
+		zap.String("backend", m.inner.Name()),
+		zap.String("target", input.Target),
+		zap.Bool("fail_open", m.failOpen),
+		zap.Error(err),
+	)
+	if m.failOpen {
+		return &ModerationVerdict{}, nil
+	}
+	return nil, WrapServiceError(ErrCodeModerationFailed, "内容审核服务调用失败", err)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	ModerationDecisionApprove = "approve"
+	ModerationDecisionReject  = "reject"
+)
+
+type ModerationShotContent struct {
+	Title           string `json:"title,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Details         string `json:"details,omitempty"`
+	Narration       string `json:"narration,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Transition      string `json:"transition,omitempty"`
+	Voice           string `json:"voice,omitempty"`
+	ImageURL        string `json:"image_url,omitempty"`
+	BGM             string `json:"bgm,omitempty"`
+	AudioDurationMs int64  `json:"audio_duration_ms,omitempty"`
+}
+
+func (c ModerationShotContent) Input() ModerationInput {
+	parts := make([]string, 0, 4)
+	for _, v := range []string{c.Title, c.Description, c.Details, c.Narration} {
+		if strings.TrimSpace(v) != "" {
+			parts = append(parts, v)
+		}
+	}
+	return ModerationInput{
+		Target:   global.ModerationTargetShot,
+		Text:     strings.Join(parts, "\n"),
+		ImageURL: c.ImageURL,
+	}
+}
+
+func (c ModerationShotContent) Updates() map[string]interface{} {
+	updates := make(map[string]interface{})
+	setIfNotEmpty := func(key, value string) {
+		if strings.TrimSpace(value) != "" {
+			updates[key] = value
+		}
+	}
+	setIfNotEmpty("title", c.Title)
+	setIfNotEmpty("description", c.Description)
+	setIfNotEmpty("details", c.Details)
+	setIfNotEmpty("narration", c.Narration)
+	setIfNotEmpty("type", c.Type)
+	setIfNotEmpty("transition", c.Transition)
+	setIfNotEmpty("voice", c.Voice)
+	setIfNotEmpty("image_url", c.ImageURL)
+	setIfNotEmpty("bgm", c.BGM)
+	if c.AudioDurationMs > 0 {
+		updates["audio_duration_ms"] = c.AudioDurationMs
+	}
+	return updates
+}
+
+func BuildModerationRecord(userID, storyID uuid.UUID, shotID *uuid.UUID, target, backend string, verdict *ModerationVerdict, content interface{}) (*model.ModerationRecord, error) {
+	record := model.NewModerationRecord(userID, storyID, target)
+	record.ShotID = shotID
+	record.Backend = backend
+	if verdict != nil {
+		record.Reason = verdict.Reason
+		categories := verdict.Categories
+		if categories == nil {
+			categories = []string{}
+		}
+		raw, err := json.Marshal(categories)
+		if err != nil {
+			return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "序列化审核分类失败", err)
+		}
+		record.Categories = datatypes.JSON(raw)
+	}
+	if content != nil {
+		raw, err := json.Marshal(content)
+		if err != nil {
+			return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "序列化审核内容失败", err)
+		}
+		record.Content = datatypes.JSON(raw)
+	}
+	return record, nil
+}
+
+type ModerationService struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	pipeline   *Pipeline
+	logger     *zap.Logger
+}
+
+type ModerationListOptions struct {
+	Status string
+	Target string
+	Limit  int
+	Offset int
+}
+
+type ModerationReviewParams struct {
+	Decision string
+	Note     string
+}
+
+type ModerationReviewResult struct {
+	Record    *model.ModerationRecord
+	Operation *model.Operation
+}
+
+func NewModerationService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ModerationService {
+	prod := newKafkaProducer(cfg, logger)
+	return &ModerationService{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, prod),
+		pipeline:   NewPipeline(cfg, d, logger),
+		logger:     logger,
+	}
+}
+
+func (s *ModerationService) Close() error {
+	if s == nil || s.dispatcher == nil {
+		return nil
+	}
+	return errors.Join(s.dispatcher.Close(), s.pipeline.Close())
+}
+
+func (s *ModerationService) List(ctx context.Context, opts ModerationListOptions) ([]model.ModerationRecord, int64, error) {
+	query := s.data.DB.WithContext(ctx).Model(&model.ModerationRecord{})
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	if opts.Target != "" {
+		query = query.Where("target = ?", opts.Target)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, WrapServiceError(ErrCodeDatabaseActionFailed, "统计审核记录失败", err)
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit).Offset(opts.Offset)
+	}
+	var records []model.ModerationRecord
+	if err := query.Order("created_at ASC").Find(&records).Error; err != nil {
+		return nil, 0, WrapServiceError(ErrCodeDatabaseActionFailed, "查询审核记录失败", err)
+	}
+	return records, total, nil
+}
+
+func (s *ModerationService) Get(ctx context.Context, recordID uuid.UUID) (*model.ModerationRecord, error) {
+	var record model.ModerationRecord
+	if err := s.data.DB.WithContext(ctx).First(&record, "id = ?", recordID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeModerationNotFound, "审核记录不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询审核记录失败", err)
+	}
+	return &record, nil
+}
+
+func (s *ModerationService) Review(ctx context.Context, reviewerID, recordID uuid.UUID, params ModerationReviewParams) (*ModerationReviewResult, error) {
+	decision := strings.ToLower(strings.TrimSpace(params.Decision))
+	if decision != ModerationDecisionApprove && decision != ModerationDecisionReject {
+		return nil, NewLocalizedError(ErrCodeInvalidRequest, MsgModerationDecisionUnsupported, MessageParams{"decision": params.Decision})
+	}
+
+	var (
+		result   = &ModerationReviewResult{}
+		story    model.Story
+		job      StoryJobMessage
+		pipeline *uuid.UUID
+	)
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var record model.ModerationRecord
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&record, "id = ?", recordID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewServiceError(ErrCodeModerationNotFound, "审核记录不存在")
+			}
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询审核记录失败", err)
+		}
+		if record.Status != global.ModerationPending {
+			return NewServiceError(ErrCodeInvalidRequest, "审核记录已处理")
+		}
+		if err := tx.First(&story, "id = ?", record.StoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewServiceError(ErrCodeStoryNotFound, "故事不存在")
+			}
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事失败", err)
+		}
+
+		var err error
+		switch record.Target {
+		case global.ModerationTargetScript:
+			result.Operation, job, err = s.reviewScript(tx, &story, decision)
+		case global.ModerationTargetShot:
+			pipeline, err = s.reviewShot(tx, &story, &record, decision)
+		default:
+			err = NewLocalizedError(ErrCodeInvalidRequest, MsgModerationTargetUnknown, MessageParams{"target": record.Target})
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		record.Status = global.ModerationApproved
+		if decision == ModerationDecisionReject {
+			record.Status = global.ModerationRejected
+		}
+		record.ReviewerID = &reviewerID
+		record.ReviewNote = params.Note
+		record.ReviewedAt = &now
+		if err := tx.Model(&record).Updates(map[string]interface{}{
+			"status":      record.Status,
+			"reviewer_id": reviewerID,
+			"review_note": record.ReviewNote,
+			"reviewed_at": now,
+		}).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "更新审核记录失败", err)
+		}
+		result.Record = &record
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	InvalidateStoryListCache(ctx, s.data, story.UserID)
+	if pipeline != nil {
+		if err := s.pipeline.Advance(ctx, *pipeline); err != nil {
+			s.logger.Warn("advance pipeline after review", zap.String(string(LogKeyOperationID), pipeline.String()), zap.Error(err))
+		}
+	}
+	if result.Operation != nil {
+		if err := dispatchStoryboardJob(ctx, s.data, s.dispatcher, &story, result.Operation, job); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *ModerationService) reviewScript(tx *gorm.DB, story *model.Story, decision string) (*model.Operation, StoryJobMessage, error) {
+	if story.Status != global.StoryFlag {
+		return nil, StoryJobMessage{}, NewServiceError(ErrCodeInvalidRequest, "故事当前不处于待审核状态")
+	}
+	if decision == ModerationDecisionReject {
+		if err := tx.Model(story).Update("status", global.StoryFail).Error; err != nil {
+			return nil, StoryJobMessage{}, WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+		return nil, StoryJobMessage{}, nil
+	}
+	if err := tx.Model(story).Update("status", global.StoryGen).Error; err != nil {
+		return nil, StoryJobMessage{}, WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+	}
+	return createStoryboardJob(tx, story, storyboardJobOptions{})
+}
+
+// reviewShot applies the decision to a held shot and, when the story is
+// being generated by a staged pipeline, returns the parent operation so
+// the caller can advance it once the review is committed.
+func (s *ModerationService) reviewShot(tx *gorm.DB, story *model.Story, record *model.ModerationRecord, decision string) (*uuid.UUID, error) {
+	if record.ShotID == nil {
+		return nil, NewServiceError(ErrCodeShotNotFound, "审核记录未关联镜头")
+	}
+	var shot model.Shot
+	if err := tx.First(&shot, "id = ? AND story_id = ?", *record.ShotID, story.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeShotNotFound, "镜头不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询镜头失败", err)
+	}
+	pipeline, err := runningPipelineParent(tx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"status": global.ShotFail}
+	if decision == ModerationDecisionApprove {
+		var content ModerationShotContent
+		if len(record.Content) > 0 {
+			if err := json.Unmarshal(record.Content, &content); err != nil {
+				return nil, WrapServiceError(ErrCodeResultDataMissing, "解析待审核镜头内容失败", err)
+			}
+		}
+		updates = content.Updates()
+		ClearStaleAudio(&shot, updates)
+		updates["status"] = global.ShotDone
+		if _, ok := updates["image_url"]; !ok && shot.ImageURL == "" && pipeline != nil {
+			updates["status"] = global.ShotRender
+		}
+	}
+	if err := tx.Model(&shot).Updates(updates).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "更新镜头记录失败", err)
+	}
+
+	if story.Status != global.StoryFlag {
+		return pipeline, nil
+	}
+	counts, err := shotStatusCounts(tx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+	if counts[global.ShotFlag] > 0 {
+		return pipeline, nil
+	}
+	if counts[global.ShotFail] > 0 {
+		if err := tx.Model(story).Update("status", global.StoryFail).Error; err != nil {
+			return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+		return pipeline, nil
+	}
+	if pipeline != nil || counts[global.ShotRender] > 0 {
+		if err := tx.Model(story).Update("status", global.StoryGen).Error; err != nil {
+			return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+		return pipeline, nil
+	}
+	storyUpdates := map[string]interface{}{"status": global.StoryReady}
+	if story.CoverURL == "" {
+		if url, ok := updates["image_url"].(string); ok {
+			storyUpdates["cover_url"] = url
+		}
+	}
+	if err := tx.Model(story).Updates(storyUpdates).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+	}
+	return nil, nil
+}
+
+func shotStatusCounts(tx *gorm.DB, storyID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := tx.Model(&model.Shot{}).
+		Select("status, COUNT(*) AS count").
+		Where("story_id = ?", storyID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "统计镜头状态失败", err)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func runningPipelineParent(tx *gorm.DB, storyID uuid.UUID) (*uuid.UUID, error) {
+	var parents []model.Operation
+	if err := tx.Select("id").
+		Where("story_id = ? AND parent_id IS NULL AND status = ?", storyID, global.OpRunning).
+		Where("id IN (?)", tx.Model(&model.Operation{}).Select("parent_id").Where("story_id = ? AND parent_id IS NOT NULL", storyID)).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&parents).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+	}
+	if len(parents) == 0 {
+		return nil, nil
+	}
+	return &parents[0].ID, nil
+}
+
+// ensureStoryNotHeld refuses work that would rebuild or publish a story
+// while moderation holds the story or any of its shots for review.
+func ensureStoryNotHeld(db *gorm.DB, story *model.Story) error {
+	held := story.Status == global.StoryFlag
+	if !held {
+		var flagged int64
+		if err := db.Model(&model.Shot{}).
+			Where("story_id = ? AND status = ?", story.ID, global.ShotFlag).
+			Count(&flagged).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+		}
+		held = flagged > 0
+	}
+	if held {
+		return NewLocalizedError(ErrCodeContentFlagged, MsgStoryHeldForReview, MessageParams{"story_id": story.ID})
+	}
+	return nil
+}
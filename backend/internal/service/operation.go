@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	operationCursorSort    = "created_at"
+	DefaultOperationPage   = 20
+	MaxOperationPageSize   = 100
+	operationFallbackError = ErrCodeWorkerExecutionFailed
+)
+
+var (
+	operationTypes = map[string]struct{}{
+		global.OpStoryboard:  {},
+		global.OpShotRegen:   {},
+		global.OpVideoRender: {},
+		global.OpExport:      {},
+		global.OpLLM:         {},
+		global.OpT2I:         {},
+		global.OpTTS:         {},
+		global.OpCompose:     {},
+	}
+	operationStatuses = map[string]struct{}{
+		global.OpScheduled: {},
+		global.OpQueued:    {},
+		global.OpRunning:   {},
+		global.OpSuccess:   {},
+		global.OpFail:      {},
+		global.OpCancel:    {},
+	}
+	operationErrorPattern = regexp.MustCompile(`^(SVC\d{4})(?::|$)`)
+)
+
+type OperationError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+type OperationView struct {
+	OperationName string           `json:"operation_name"`
+	OperationID   uuid.UUID        `json:"operation_id"`
+	StoryID       uuid.UUID        `json:"story_id"`
+	ShotID        *uuid.UUID       `json:"shot_id,omitempty"`
+	BatchName     string           `json:"batch_name,omitempty"`
+	ParentName    string           `json:"parent_name,omitempty"`
+	Type          string           `json:"type"`
+	State         string           `json:"state"`
+	Retries       int              `json:"retries"`
+	Worker        string           `json:"worker,omitempty"`
+	CreateTime    time.Time        `json:"create_time"`
+	UpdateTime    time.Time        `json:"update_time"`
+	ScheduleTime  *time.Time       `json:"schedule_time,omitempty"`
+	StartTime     *time.Time       `json:"start_time,omitempty"`
+	FinishTime    *time.Time       `json:"finish_time,omitempty"`
+	QueuePosition *int             `json:"queue_position,omitempty"`
+	Error         *OperationStatus `json:"error,omitempty"`
+}
+
+type OperationListOptions struct {
+	StoryID   *uuid.UUID
+	Types     []string
+	Statuses  []string
+	StartTime *time.Time
+	EndTime   *time.Time
+	PageSize  int
+	PageToken string
+	Status    StatusOptions
+}
+
+type OperationService struct {
+	schedulePolicy
+	data       *data.Data
+	cursors    *CursorCodec
+	dispatcher *jobDispatcher
+	batches    *BatchTracker
+	positions  *QueuePositions
+	logger     *zap.Logger
+}
+
+func NewOperationService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *OperationService {
+	return &OperationService{
+		schedulePolicy: newSchedulePolicy(cfg),
+		data:           d,
+		cursors:        NewCursorCodec(cfg, logger),
+		dispatcher:     newJobDispatcher(logger, newKafkaProducer(cfg, logger)),
+		batches:        NewBatchTracker(cfg, d, logger),
+		positions:      NewQueuePositions(cfg, d, logger),
+		logger:         logger,
+	}
+}
+
+func (s *OperationService) Close() error {
+	if s == nil {
+		return nil
+	}
+	return errors.Join(s.dispatcher.Close(), s.batches.Close())
+}
+
+func NewOperationView(op *model.Operation, opts StatusOptions) OperationView {
+	view := OperationView{
+		OperationName: fmt.Sprintf("operations/%s", op.ID),
+		OperationID:   op.ID,
+		StoryID:       op.StoryID,
+		Type:          op.Type,
+		State:         op.Status,
+		Retries:       op.Retries,
+		Worker:        op.Worker,
+		CreateTime:    op.CreatedAt,
+		UpdateTime:    op.UpdatedAt,
+		ScheduleTime:  op.ScheduledAt,
+		StartTime:     op.StartedAt,
+		FinishTime:    op.FinishedAt,
+		QueuePosition: op.QueuePosition,
+	}
+	if op.BatchID != nil {
+		view.BatchName = fmt.Sprintf("batches/%s", *op.BatchID)
+	}
+	if op.ParentID != nil {
+		view.ParentName = fmt.Sprintf("operations/%s", *op.ParentID)
+	}
+	if op.ShotID != uuid.Nil {
+		shotID := op.ShotID
+		view.ShotID = &shotID
+	}
+	view.Error = NewOperationStatus(op, opts)
+	return view
+}
+
+func ParseOperationError(msg string) *OperationError {
+	code := operationFallbackError
+	if m := operationErrorPattern.FindStringSubmatch(msg); m != nil {
+		code = ErrorCode(m[1])
+	}
+	message := code.DefaultMessage()
+	if message == "" {
+		message = operationFallbackError.DefaultMessage()
+	}
+	return &OperationError{Code: code, Message: message}
+}
+
+func ValidateOperationFilters(types, statuses []string) error {
+	for _, t := range types {
+		if _, ok := operationTypes[t]; !ok {
+			return NewLocalizedError(ErrCodeInvalidRequest, MsgOperationTypeUnsupported, MessageParams{"type": t})
+		}
+	}
+	for _, st := range statuses {
+		if _, ok := operationStatuses[st]; !ok {
+			return NewLocalizedError(ErrCodeInvalidRequest, MsgOperationStatusUnsupported, MessageParams{"status": st})
+		}
+	}
+	return nil
+}
+
+func (s *OperationService) List(ctx context.Context, userID uuid.UUID, opts OperationListOptions) ([]OperationView, string, error) {
+	if err := ValidateOperationFilters(opts.Types, opts.Statuses); err != nil {
+		return nil, "", err
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultOperationPage
+	}
+	if pageSize > MaxOperationPageSize {
+		pageSize = MaxOperationPageSize
+	}
+
+	if opts.StoryID != nil {
+		var count int64
+		if err := s.data.DB.WithContext(ctx).
+			Model(&model.Story{}).
+			Where("id = ? AND user_id = ?", *opts.StoryID, userID).
+			Count(&count).Error; err != nil {
+			return nil, "", WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事失败", err)
+		}
+		if count == 0 {
+			return nil, "", NewServiceError(ErrCodeStoryNotFound, "故事不存在")
+		}
+	}
+
+	filter := operationListFingerprint(opts)
+	query := s.data.DB.WithContext(ctx).Model(&model.Operation{}).Where("user_id = ?", userID)
+	if opts.StoryID != nil {
+		query = query.Where("story_id = ?", *opts.StoryID)
+	}
+	if len(opts.Types) > 0 {
+		query = query.Where("type IN ?", opts.Types)
+	}
+	if len(opts.Statuses) > 0 {
+		query = query.Where("status IN ?", opts.Statuses)
+	}
+	if opts.StartTime != nil {
+		query = query.Where("created_at >= ?", *opts.StartTime)
+	}
+	if opts.EndTime != nil {
+		query = query.Where("created_at <= ?", *opts.EndTime)
+	}
+	if opts.PageToken != "" {
+		cur, err := s.cursors.Decode(opts.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := cur.expect(operationCursorSort, true, filter); err != nil {
+			return nil, "", err
+		}
+		createdAt, err := cur.timeValue(0)
+		if err != nil {
+			return nil, "", err
+		}
+		query = applyKeyset(query, []string{"created_at", "id"}, true, createdAt, cur.ID)
+	}
+
+	var ops []model.Operation
+	if err := query.Order("created_at DESC, id DESC").Limit(pageSize + 1).Find(&ops).Error; err != nil {
+		return nil, "", WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务列表失败", err)
+	}
+
+	next := ""
+	if len(ops) > pageSize {
+		ops = ops[:pageSize]
+		last := ops[len(ops)-1]
+		next = s.cursors.Encode(PageCursor{
+			Sort:   operationCursorSort,
+			Desc:   true,
+			Values: []string{formatCursorTime(last.CreatedAt)},
+			ID:     last.ID,
+			Filter: filter,
+		})
+	}
+	refs := make([]*model.Operation, 0, len(ops))
+	for idx := range ops {
+		refs = append(refs, &ops[idx])
+	}
+	s.positions.Attach(ctx, refs...)
+	views := make([]OperationView, 0, len(ops))
+	for idx := range ops {
+		views = append(views, NewOperationView(&ops[idx], opts.Status))
+	}
+	return views, next, nil
+}
+
+func operationListFingerprint(opts OperationListOptions) string {
+	return listFingerprint(struct {
+		StoryID  *uuid.UUID `json:"sid,omitempty"`
+		Types    []string   `json:"t,omitempty"`
+		Statuses []string   `json:"st,omitempty"`
+		Start    *time.Time `json:"from,omitempty"`
+		End      *time.Time `json:"to,omitempty"`
+	}{
+		StoryID:  opts.StoryID,
+		Types:    sortedCopy(opts.Types),
+		Statuses: sortedCopy(opts.Statuses),
+		Start:    opts.StartTime,
+		End:      opts.EndTime,
+	})
+}
+
+func (s *OperationService) Get(ctx context.Context, userID, opID uuid.UUID) (*model.Operation, error) {
+	var op model.Operation
+	if err := s.data.DB.WithContext(ctx).First(&op, "id = ? AND user_id = ?", opID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeOperationNotFound, "任务不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+	}
+	s.positions.Attach(ctx, &op)
+	return &op, nil
+}
+
+func (s *OperationService) Cancel(ctx context.Context, userID, opID uuid.UUID) (*model.Operation, error) {
+	var op model.Operation
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&op, "id = ? AND user_id = ?", opID, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewServiceError(ErrCodeOperationNotFound, "任务不存在")
+			}
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+		}
+		switch op.Status {
+		case global.OpSuccess, global.OpFail, global.OpCancel:
+			return nil
+		case global.OpRunning:
+			return NewServiceError(ErrCodeOperationNotCancellable, "任务已开始执行，无法取消")
+		}
+		if op.ParentID != nil {
+			return NewServiceError(ErrCodeOperationNotCancellable, "阶段任务由所属任务统一调度，无法单独取消")
+		}
+
+		now := time.Now()
+		res := tx.Model(&model.Operation{}).
+			Where("id = ? AND status IN ?", op.ID, []string{global.OpQueued, global.OpScheduled}).
+			Updates(map[string]interface{}{
+				"status":      global.OpCancel,
+				"finished_at": now,
+			})
+		if res.Error != nil {
+			return WrapServiceError(ErrCodeOperationUpdateFailed, "取消任务失败", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return NewServiceError(ErrCodeOperationNotCancellable, "任务已开始执行，无法取消")
+		}
+		op.Status = global.OpCancel
+		op.FinishedAt = &now
+		return releaseCancelledTarget(tx, &op)
+	})
+	if err != nil {
+		return nil, err
+	}
+	InvalidateStoryListCache(ctx, s.data, userID)
+	if op.BatchID != nil && op.Status == global.OpCancel {
+		s.batches.Finalize(ctx, *op.BatchID)
+	}
+	return &op, nil
+}
+
+func (s *OperationService) Reschedule(ctx context.Context, userID, opID uuid.UUID, at time.Time) (*model.Operation, error) {
+	op, err := s.Get(ctx, userID, opID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != global.OpScheduled {
+		return nil, NewServiceError(ErrCodeOperationNotScheduled, "任务不处于定时等待状态")
+	}
+	res := s.data.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Where("id = ? AND status = ?", op.ID, global.OpScheduled).
+		Update("scheduled_at", at)
+	if res.Error != nil {
+		return nil, WrapServiceError(ErrCodeOperationUpdateFailed, "调整任务执行时间失败", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, NewServiceError(ErrCodeOperationNotScheduled, "任务不处于定时等待状态")
+	}
+	op.ScheduledAt = &at
+	return op, nil
+}
+
+func (s *OperationService) Retry(ctx context.Context, userID, opID uuid.UUID) (*model.Operation, error) {
+	var op model.Operation
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&op, "id = ? AND user_id = ?", opID, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewServiceError(ErrCodeOperationNotFound, "任务不存在")
+			}
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+		}
+		if op.Status != global.OpFail || op.ParentID != nil {
+			return NewServiceError(ErrCodeOperationNotRetryable, "仅失败的任务可以重试")
+		}
+		if _, ok := operationActions[op.Type]; !ok {
+			return NewServiceError(ErrCodeOperationNotRetryable, "该类型的任务不支持重试")
+		}
+		if err := resetFailedOperation(tx, &op); err != nil {
+			return err
+		}
+		if op.BatchID != nil {
+			return reopenBatch(tx, *op.BatchID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	InvalidateStoryListCache(ctx, s.data, userID)
+	if err := redispatchOperation(ctx, s.data, s.dispatcher, &op); err != nil {
+		s.logger.Error("dispatch retried operation", zap.String(string(LogKeyOperationID), op.ID.String()), zap.Error(err))
+		if op.BatchID != nil {
+			s.batches.Finalize(ctx, *op.BatchID)
+		}
+	}
+	return s.Get(ctx, userID, op.ID)
+}
+
+func resetFailedOperation(tx *gorm.DB, op *model.Operation) error {
+	if err := tx.Model(&model.Operation{}).
+		Where("id = ?", op.ID).
+		Updates(map[string]interface{}{
+			"status":          global.OpQueued,
+			"retries":         gorm.Expr("retries + ?", 1),
+			"error_msg":       "",
+			"error_code":      "",
+			"error_retryable": false,
+			"error_details":   nil,
+			"started_at":      nil,
+			"finished_at":     nil,
+		}).Error; err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "重置失败任务失败", err)
+	}
+	switch op.Type {
+	case global.OpStoryboard:
+		if err := tx.Model(&model.Story{}).
+			Where("id = ?", op.StoryID).
+			Update("status", global.StoryGen).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+	case global.OpT2I, global.OpShotRegen:
+		if err := tx.Model(&model.Shot{}).
+			Where("id = ? AND story_id = ? AND status = ?", op.ShotID, op.StoryID, global.ShotFail).
+			Update("status", global.ShotRender).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "更新镜头状态失败", err)
+		}
+	}
+	op.Status = global.OpQueued
+	op.Retries++
+	op.StartedAt = nil
+	op.FinishedAt = nil
+	return nil
+}
+
+func reopenBatch(tx *gorm.DB, batchID uuid.UUID) error {
+	if err := tx.Model(&model.Batch{}).
+		Where("id = ?", batchID).
+		Updates(map[string]interface{}{
+			"status":       global.BatchRunning,
+			"completed_at": nil,
+		}).Error; err != nil {
+		return WrapServiceError(ErrCodeDatabaseActionFailed, "更新批量任务状态失败", err)
+	}
+	return nil
+}
+
+func redispatchOperation(ctx context.Context, d *data.Data, dispatcher *jobDispatcher, op *model.Operation) error {
+	job, err := operationJobMessage(op)
+	if err == nil {
+		err = dispatcher.Dispatch(ctx, job)
+	}
+	if err != nil {
+		_ = UpdateOperationFailure(ctx, d, op.ID, err)
+		_ = releaseCancelledTarget(d.DB.WithContext(ctx), op)
+	}
+	return err
+}
+
+// releaseCancelledTarget marks whatever an operation that will never run was
+// holding in a pending state. Only storyboard generation and shot regeneration
+// leave their target mid-way; renders and exports never touch the story status,
+// so a ready story stays ready when one of them is cancelled or fails to queue.
+func releaseCancelledTarget(tx *gorm.DB, op *model.Operation) error {
+	switch op.Type {
+	case global.OpShotRegen:
+		if err := tx.Model(&model.Shot{}).
+			Where("id = ? AND story_id = ?", op.ShotID, op.StoryID).
+			Update("status", global.ShotFail).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "更新镜头状态失败", err)
+		}
+	case global.OpStoryboard:
+		if err := tx.Model(&model.Story{}).
+			Where("id = ?", op.StoryID).
+			Update("status", global.StoryFail).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+		}
+	}
+	return nil
+}
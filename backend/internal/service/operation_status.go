@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	"story2video-backend/internal/data"
@@ -12,19 +15,44 @@ import (
 	"story2video-backend/internal/model"
 )
 
+// ErrOperationNotRunnable is returned when a job message arrives for an
+// operation that was cancelled or already finished, e.g. a message
+// redelivered after a restart because its offset was never committed.
+var ErrOperationNotRunnable = errors.New("operation not runnable")
+
 func UpdateOperationRunning(ctx context.Context, d *data.Data, opID uuid.UUID) error {
 	if d == nil || d.DB == nil {
 		return nil
 	}
 	now := time.Now()
-	if err := d.DB.WithContext(ctx).
+	res := d.DB.WithContext(ctx).
 		Model(&model.Operation{}).
-		Where("id = ?", opID).
+		Where("id = ? AND status IN ?", opID, []string{global.OpQueued, global.OpRunning}).
 		Updates(map[string]interface{}{
 			"status":     global.OpRunning,
 			"started_at": now,
+		})
+	if res.Error != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为执行中失败", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrOperationNotRunnable
+	}
+	return nil
+}
+
+func RequeueOperation(ctx context.Context, d *data.Data, opID uuid.UUID) error {
+	if d == nil || d.DB == nil {
+		return nil
+	}
+	if err := d.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Where("id = ? AND status = ?", opID, global.OpRunning).
+		Updates(map[string]interface{}{
+			"status":     global.OpQueued,
+			"started_at": nil,
 		}).Error; err != nil {
-		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为执行中失败", err)
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "重置任务为排队状态失败", err)
 	}
 	return nil
 }
@@ -38,10 +66,13 @@ func UpdateOperationSuccess(ctx context.Context, d *data.Data, opID uuid.UUID, w
 		Model(&model.Operation{}).
 		Where("id = ?", opID).
 		Updates(map[string]interface{}{
-			"status":      global.OpSuccess,
-			"finished_at": now,
-			"error_msg":   "",
-			"worker":      workerName,
+			"status":          global.OpSuccess,
+			"finished_at":     now,
+			"error_msg":       "",
+			"error_code":      "",
+			"error_retryable": false,
+			"error_details":   nil,
+			"worker":          workerName,
 		}).Error; err != nil {
 		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为成功状态失败", err)
 	}
@@ -52,24 +83,63 @@ func UpdateOperationFailure(ctx context.Context, d *data.Data, opID uuid.UUID, c
 	if d == nil || d.DB == nil {
 		return nil
 	}
-	now := time.Now()
-	msg := ""
-	if cause != nil {
-		msg = cause.Error()
+	updates, err := operationFailureUpdates(cause)
+	if err != nil {
+		return err
 	}
 	if err := d.DB.WithContext(ctx).
 		Model(&model.Operation{}).
 		Where("id = ?", opID).
-		Updates(map[string]interface{}{
-			"status":      global.OpFail,
-			"finished_at": now,
-			"error_msg":   msg,
-		}).Error; err != nil {
+		Updates(updates).Error; err != nil {
 		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为失败状态失败", err)
 	}
 	return nil
 }
 
+func operationFailureUpdates(cause error) (map[string]interface{}, error) {
+	svcErr, ok := AsServiceError(cause)
+	if !ok {
+		svcErr = WrapServiceError(operationFallbackError, "", cause)
+	}
+	details := OperationErrorDetails{
+		MessageKey: svcErr.Key,
+		Params:     svcErr.Params,
+		Metadata:   ErrorDetails(cause),
+	}
+	if cause != nil {
+		details.Debug = cause.Error()
+	}
+	rawDetails, err := json.Marshal(details)
+	if err != nil {
+		return nil, WrapServiceError(ErrCodeOperationUpdateFailed, "序列化任务错误详情失败", err)
+	}
+	return map[string]interface{}{
+		"status":          global.OpFail,
+		"finished_at":     time.Now(),
+		"error_msg":       svcErr.UserMessage(),
+		"error_code":      string(svcErr.Code),
+		"error_retryable": svcErr.IsRetryable(),
+		"error_details":   datatypes.JSON(rawDetails),
+	}, nil
+}
+
+func UpdateOperationResult(ctx context.Context, d *data.Data, opID uuid.UUID, result interface{}) error {
+	if d == nil || d.DB == nil {
+		return nil
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "序列化任务结果失败", err)
+	}
+	if err := d.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Where("id = ?", opID).
+		Update("result", datatypes.JSON(raw)).Error; err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务结果失败", err)
+	}
+	return nil
+}
+
 func IncrementOperationRetry(ctx context.Context, d *data.Data, opID uuid.UUID) error {
 	if d == nil || d.DB == nil {
 		return nil
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+)
+
+func TestCancelQueuedOperationStoryStatus(t *testing.T) {
+	cases := []struct {
+		opType string
+		want   string
+	}{
+		{global.OpVideoRender, global.StoryReady},
+		{global.OpExport, global.StoryReady},
+		{global.OpStoryboard, global.StoryFail},
+	}
+	for _, tc := range cases {
+		t.Run(tc.opType, func(t *testing.T) {
+			userID := uuid.New()
+			db := &cancelDB{
+				story: global.StoryReady,
+				op: map[string]driver.Value{
+					"id":       uuid.NewString(),
+					"user_id":  userID.String(),
+					"story_id": uuid.NewString(),
+					"shot_id":  uuid.Nil.String(),
+					"type":     tc.opType,
+					"status":   global.OpQueued,
+				},
+			}
+			name := "cancel-" + t.Name()
+			sql.Register(name, db)
+			gdb, err := gorm.Open(postgres.New(postgres.Config{DriverName: name, DSN: "cancel"}), &gorm.Config{
+				DisableAutomaticPing: true,
+				Logger:               logger.Discard,
+			})
+			if err != nil {
+				t.Fatalf("open db: %v", err)
+			}
+			svc := NewOperationService(&conf.Config{}, &data.Data{DB: gdb}, zap.NewNop())
+
+			op, err := svc.Cancel(context.Background(), userID, uuid.MustParse(db.op["id"].(string)))
+			if err != nil {
+				t.Fatalf("cancel: %v", err)
+			}
+			if op.Status != global.OpCancel {
+				t.Fatalf("operation status = %s, want %s", op.Status, global.OpCancel)
+			}
+			if db.story != tc.want {
+				t.Fatalf("story status = %s, want %s", db.story, tc.want)
+			}
+		})
+	}
+}
+
+// cancelDB serves a single operation row and tracks the status of the story
+// it belongs to, which is all Cancel reads and writes.
+type cancelDB struct {
+	mu    sync.Mutex
+	op    map[string]driver.Value
+	story string
+}
+
+func (d *cancelDB) Open(string) (driver.Conn, error) { return d, nil }
+
+func (d *cancelDB) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+
+func (d *cancelDB) Close() error { return nil }
+
+func (d *cancelDB) Begin() (driver.Tx, error) { return d, nil }
+
+func (d *cancelDB) Commit() error { return nil }
+
+func (d *cancelDB) Rollback() error { return nil }
+
+func (d *cancelDB) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if strings.HasPrefix(query, `UPDATE "stories" SET "status"=`) && len(args) > 0 {
+		d.story, _ = args[0].Value.(string)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (d *cancelDB) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !strings.HasPrefix(query, `SELECT * FROM "operations"`) {
+		return &cancelRows{}, nil
+	}
+	rows := &cancelRows{}
+	for column, value := range d.op {
+		rows.columns = append(rows.columns, column)
+		rows.values = append(rows.values, value)
+	}
+	rows.columns = append(rows.columns, "created_at")
+	rows.values = append(rows.values, time.Now())
+	return rows, nil
+}
+
+type cancelRows struct {
+	columns []string
+	values  []driver.Value
+	done    bool
+}
+
+func (r *cancelRows) Columns() []string { return r.columns }
+
+func (r *cancelRows) Close() error { return nil }
+
+func (r *cancelRows) Next(dest []driver.Value) error {
+	if r.done || r.values == nil {
+		return io.EOF
+	}
+	copy(dest, r.values)
+	r.done = true
+	return nil
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	StageActionScript  = "generate_shots"
+	StageActionImage   = "generate_image"
+	StageActionAudio   = "synthesize_audio"
+	StageActionCompose = "compose_video"
+)
+
+var ErrOperationPending = errors.New("operation waiting on stage operations")
+
+type ScriptStageResult struct {
+	ShotCount     int `json:"shot_count"`
+	HeldForReview int `json:"held_for_review,omitempty"`
+}
+
+type ImageStageResult struct {
+	ImageURL      string `json:"image_url"`
+	HeldForReview bool   `json:"held_for_review,omitempty"`
+}
+
+type AudioStageResult struct {
+	AudioURL        string `json:"audio_url,omitempty"`
+	AudioDurationMs int64  `json:"audio_duration_ms,omitempty"`
+}
+
+type Pipeline struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	batches    *BatchTracker
+	enabled    bool
+	compose    bool
+	maxRetries int
+	logger     *zap.Logger
+}
+
+func NewPipeline(cfg *conf.Config, d *data.Data, logger *zap.Logger) *Pipeline {
+	return &Pipeline{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, newKafkaProducer(cfg, logger)),
+		batches:    NewBatchTracker(cfg, d, logger),
+		enabled:    cfg.Pipeline.Enabled,
+		compose:    cfg.Pipeline.Compose,
+		maxRetries: cfg.Pipeline.MaxStageRetries,
+		logger:     logger,
+	}
+}
+
+func (p *Pipeline) Close() error {
+	if p == nil {
+		return nil
+	}
+	return errors.Join(p.dispatcher.Close(), p.batches.Close())
+}
+
+func (p *Pipeline) Enabled() bool {
+	return p != nil && p.enabled
+}
+
+func (p *Pipeline) Start(ctx context.Context, job StoryJobMessage) error {
+	parentID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return NewServiceError(ErrCodeInvalidRequest, "operation_id 非法")
+	}
+	var stages []model.Operation
+	err = p.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parent, err := lockOperation(tx, parentID)
+		if err != nil {
+			return err
+		}
+		children, err := stageOperations(tx, parent.ID)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			payload := job.Payload
+			payload.Action = StageActionScript
+			stage, err := newStageOperation(parent, global.OpLLM, uuid.Nil, payload)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(stage).Error; err != nil {
+				return WrapServiceError(ErrCodeOperationCreateFailed, "创建阶段任务失败", err)
+			}
+			stages = append(stages, *stage)
+			return nil
+		}
+		for idx := range children {
+			child := &children[idx]
+			if child.Status != global.OpFail && child.Status != global.OpCancel {
+				continue
+			}
+			if err := resetFailedOperation(tx, child); err != nil {
+				return err
+			}
+			stages = append(stages, *child)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	p.launch(ctx, parentID, stages)
+	return p.Advance(ctx, parentID)
+}
+
+func (p *Pipeline) Advance(ctx context.Context, parentID uuid.UUID) error {
+	var (
+		parent   *model.Operation
+		stages   []model.Operation
+		finished bool
+	)
+	err := p.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		parent, err = lockOperation(tx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent.Status != global.OpRunning {
+			return nil
+		}
+		children, err := stageOperations(tx, parent.ID)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		var (
+			failed   *model.Operation
+			composed bool
+		)
+		staged := make(map[uuid.UUID]bool, len(children))
+		for idx := range children {
+			child := &children[idx]
+			switch child.Type {
+			case global.OpT2I, global.OpTTS:
+				staged[child.ShotID] = true
+			case global.OpCompose:
+				composed = true
+			}
+			switch child.Status {
+			case global.OpScheduled, global.OpQueued, global.OpRunning:
+				return nil
+			case global.OpFail, global.OpCancel:
+				if failed == nil {
+					failed = child
+				}
+			}
+		}
+		if failed != nil {
+			finished = true
+			return failParent(tx, parent, stageFailureUpdates(failed))
+		}
+		stages, err = createShotStages(tx, parent, staged)
+		if err != nil || len(stages) > 0 {
+			return err
+		}
+		// Shots held for review get their stages once approved; the
+		// parent stays running until every held shot is decided.
+		held, rejected, err := reviewedShotCounts(tx, parent.StoryID)
+		if err != nil || held > 0 {
+			return err
+		}
+		if rejected > 0 {
+			updates, err := operationFailureUpdates(NewLocalizedError(ErrCodeContentFlagged, MsgShotsRejected, MessageParams{"count": rejected}))
+			if err != nil {
+				return err
+			}
+			finished = true
+			return failParent(tx, parent, updates)
+		}
+		if p.compose && !composed {
+			stage, err := newStageOperation(parent, global.OpCompose, uuid.Nil, StoryJobPayload{Action: StageActionCompose})
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(stage).Error; err != nil {
+				return WrapServiceError(ErrCodeOperationCreateFailed, "创建阶段任务失败", err)
+			}
+			stages = append(stages, *stage)
+			return nil
+		}
+		finished = true
+		return completeParent(tx, parent)
+	})
+	if err != nil {
+		return err
+	}
+	p.launch(ctx, parentID, stages)
+	if finished && parent.BatchID != nil {
+		p.batches.Finalize(ctx, *parent.BatchID)
+	}
+	return nil
+}
+
+func (p *Pipeline) RetryStage(ctx context.Context, job StoryJobMessage, cause error) bool {
+	svcErr, ok := AsServiceError(cause)
+	if !ok || !svcErr.IsRetryable() {
+		return false
+	}
+	opID, err := uuid.Parse(job.OperationID)
+	if err != nil {
+		return false
+	}
+	res := p.data.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Where("id = ? AND status = ? AND retries < ?", opID, global.OpRunning, p.maxRetries).
+		Updates(map[string]interface{}{
+			"status":     global.OpQueued,
+			"retries":    gorm.Expr("retries + ?", 1),
+			"started_at": nil,
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false
+	}
+	job.CreatedAt = time.Now()
+	if err := p.dispatcher.Dispatch(ctx, job); err != nil {
+		p.logger.Warn("dispatch retried stage operation", zap.String(string(LogKeyOperationID), job.OperationID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func (p *Pipeline) launch(ctx context.Context, parentID uuid.UUID, stages []model.Operation) {
+	failed := false
+	for idx := range stages {
+		op := &stages[idx]
+		job, err := operationJobMessage(op)
+		if err == nil {
+			err = p.dispatcher.Dispatch(ctx, job)
+		}
+		if err != nil {
+			p.logger.Error("dispatch stage operation", zap.String(string(LogKeyOperationID), op.ID.String()), zap.Error(err))
+			_ = UpdateOperationFailure(ctx, p.data, op.ID, err)
+			failed = true
+		}
+	}
+	if !failed {
+		return
+	}
+	if err := p.Advance(ctx, parentID); err != nil {
+		p.logger.Warn("advance pipeline", zap.String(string(LogKeyOperationID), parentID.String()), zap.Error(err))
+	}
+}
+
+func lockOperation(tx *gorm.DB, opID uuid.UUID) (*model.Operation, error) {
+	var op model.Operation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&op, "id = ?", opID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError(ErrCodeOperationNotFound, "任务不存在")
+		}
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询任务失败", err)
+	}
+	return &op, nil
+}
+
+func stageOperations(tx *gorm.DB, parentID uuid.UUID) ([]model.Operation, error) {
+	var children []model.Operation
+	if err := tx.Where("parent_id = ?", parentID).Order("created_at ASC").Find(&children).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询阶段任务失败", err)
+	}
+	return children, nil
+}
+
+func newStageOperation(parent *model.Operation, opType string, shotID uuid.UUID, payload StoryJobPayload) (*model.Operation, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "序列化任务参数失败", err)
+	}
+	parentID := parent.ID
+	op := model.NewOperation(uuid.New(), parent.UserID, parent.StoryID, shotID, opType, raw)
+	op.ParentID = &parentID
+	return op, nil
+}
+
+func createShotStages(tx *gorm.DB, parent *model.Operation, staged map[uuid.UUID]bool) ([]model.Operation, error) {
+	var payload StoryJobPayload
+	if len(parent.Payload) > 0 {
+		if err := json.Unmarshal(parent.Payload, &payload); err != nil {
+			return nil, WrapServiceError(ErrCodeOperationCreateFailed, "解析任务参数失败", err)
+		}
+	}
+	var shots []model.Shot
+	if err := tx.Select("id").
+		Where("story_id = ? AND status NOT IN ?", parent.StoryID, []string{global.ShotFlag, global.ShotFail}).
+		Order(ShotSequenceOrderClause).
+		Find(&shots).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
+	}
+	stages := make([]model.Operation, 0, len(shots)*2)
+	for _, shot := range shots {
+		if staged[shot.ID] {
+			continue
+		}
+		image, err := newStageOperation(parent, global.OpT2I, shot.ID, StoryJobPayload{
+			Action: StageActionImage,
+			ShotID: shot.ID.String(),
+			Style:  payload.Style,
+		})
+		if err != nil {
+			return nil, err
+		}
+		audio, err := newStageOperation(parent, global.OpTTS, shot.ID, StoryJobPayload{
+			Action: StageActionAudio,
+			ShotID: shot.ID.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, *image, *audio)
+	}
+	if len(stages) == 0 {
+		return nil, nil
+	}
+	if err := tx.Create(&stages).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "创建阶段任务失败", err)
+	}
+	return stages, nil
+}
+
+func reviewedShotCounts(tx *gorm.DB, storyID uuid.UUID) (held, rejected int64, err error) {
+	if err := tx.Model(&model.Shot{}).
+		Where("story_id = ? AND status = ?", storyID, global.ShotFlag).
+		Count(&held).Error; err != nil {
+		return 0, 0, WrapServiceError(ErrCodeDatabaseActionFailed, "统计待审核镜头失败", err)
+	}
+	if err := tx.Model(&model.Shot{}).
+		Where("story_id = ? AND status = ?", storyID, global.ShotFail).
+		Count(&rejected).Error; err != nil {
+		return 0, 0, WrapServiceError(ErrCodeDatabaseActionFailed, "统计失败镜头失败", err)
+	}
+	return held, rejected, nil
+}
+
+func stageFailureUpdates(stage *model.Operation) map[string]interface{} {
+	return map[string]interface{}{
+		"status":          global.OpFail,
+		"finished_at":     time.Now(),
+		"error_msg":       stage.ErrorMsg,
+		"error_code":      stage.ErrorCode,
+		"error_retryable": stage.ErrorRetryable,
+		"error_details":   stage.ErrorDetails,
+	}
+}
+
+func failParent(tx *gorm.DB, parent *model.Operation, updates map[string]interface{}) error {
+	if err := tx.Model(&model.Operation{}).
+		Where("id = ?", parent.ID).
+		Updates(updates).Error; err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为失败状态失败", err)
+	}
+	if err := tx.Model(&model.Story{}).
+		Where("id = ? AND status <> ?", parent.StoryID, global.StoryFlag).
+		Update("status", global.StoryFail).Error; err != nil {
+		return WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+	}
+	return nil
+}
+
+func completeParent(tx *gorm.DB, parent *model.Operation) error {
+	if err := tx.Model(&model.Operation{}).
+		Where("id = ?", parent.ID).
+		Updates(map[string]interface{}{
+			"status":          global.OpSuccess,
+			"finished_at":     time.Now(),
+			"error_msg":       "",
+			"error_code":      "",
+			"error_retryable": false,
+			"error_details":   nil,
+		}).Error; err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为成功状态失败", err)
+	}
+	if err := tx.Model(&model.Story{}).
+		Where("id = ? AND status <> ?", parent.StoryID, global.StoryFlag).
+		Update("status", global.StoryReady).Error; err != nil {
+		return WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+	}
+	return nil
+}
@@ -8,9 +8,13 @@ import (
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"story2video-backend/internal/conf"
+	"story2video-backend/internal/tracing"
 )
 
 const kafkaEnsureTopicTimeout = 10 * time.Second
@@ -22,6 +26,7 @@ type producer interface {
 
 type kafkaProducer struct {
 	writer *kafka.Writer
+	lanes  *laneRouter
 	logger *zap.Logger
 }
 
@@ -35,14 +40,13 @@ func newKafkaProducer(cfg *conf.Config, logger *zap.Logger) producer {
 	}
 
 	if cfg.Kafka.AutoCreateTopic {
-		if err := ensureKafkaTopic(cfg.Kafka); err != nil && logger != nil {
+		if err := ensureKafkaTopics(cfg.Kafka, KafkaTopics(cfg.Kafka)); err != nil && logger != nil {
 			logger.Warn("ensure kafka topic", zap.Error(err))
 		}
 	}
 
 	w := &kafka.Writer{
 		Addr:     kafka.TCP(cfg.Kafka.Brokers...),
-		Topic:    cfg.Kafka.Topic,
 		Balancer: &kafka.Hash{},
 	}
 	if cfg.Kafka.WriteTimeoutSeconds > 0 {
@@ -59,20 +63,40 @@ func newKafkaProducer(cfg *conf.Config, logger *zap.Logger) producer {
 	}
 	return &kafkaProducer{
 		writer: w,
+		lanes:  newLaneRouter(cfg.Kafka),
 		logger: logger,
 	}
 }
 
 func (p *kafkaProducer) Publish(ctx context.Context, msg StoryJobMessage) error {
+	lane, topic := p.lanes.Route(msg)
+	ctx, span := tracing.Tracer().Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.String("job.lane", lane),
+			attribute.String(string(LogKeyOperationID), msg.OperationID),
+		),
+	)
+	defer span.End()
+
 	bytes, err := json.Marshal(msg)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return WrapServiceError(ErrCodeJobEnqueueFailed, "序列化 Kafka 消息失败", err)
 	}
+	var headers []kafka.Header
+	tracing.Inject(ctx, tracing.KafkaHeaderCarrier{Headers: &headers})
 	if err := p.writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(msg.OperationID),
-		Value: bytes,
-		Time:  msg.CreatedAt,
+		Topic:   topic,
+		Key:     []byte(msg.OperationID),
+		Value:   bytes,
+		Time:    msg.CreatedAt,
+		Headers: headers,
 	}); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return WrapServiceError(ErrCodeJobEnqueueFailed, "Kafka 写入失败", err)
 	}
 	return nil
@@ -100,11 +124,11 @@ func (f *failingProducer) Close() error {
 	return nil
 }
 
-func ensureKafkaTopic(cfg conf.Kafka) error {
+func ensureKafkaTopics(cfg conf.Kafka, topics []string) error {
 	if len(cfg.Brokers) == 0 {
 		return fmt.Errorf("ensure topic: empty brokers")
 	}
-	if cfg.Topic == "" {
+	if len(topics) == 0 {
 		return fmt.Errorf("ensure topic: empty topic")
 	}
 
@@ -125,13 +149,17 @@ func ensureKafkaTopic(cfg conf.Kafka) error {
 		Timeout: kafkaEnsureTopicTimeout,
 	}
 
-	req := &kafka.CreateTopicsRequest{
-		Addr: kafka.TCP(cfg.Brokers[0]),
-		Topics: []kafka.TopicConfig{{
-			Topic:             cfg.Topic,
+	configs := make([]kafka.TopicConfig, 0, len(topics))
+	for _, topic := range topics {
+		configs = append(configs, kafka.TopicConfig{
+			Topic:             topic,
 			NumPartitions:     partitions,
 			ReplicationFactor: replicas,
-		}},
+		})
+	}
+	req := &kafka.CreateTopicsRequest{
+		Addr:   kafka.TCP(cfg.Brokers[0]),
+		Topics: configs,
 	}
 	resp, err := client.CreateTopics(ctx, req)
 	if err != nil {
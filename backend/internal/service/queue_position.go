@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	queuePositionCacheTTL  = 2 * time.Second
+	queuePositionScanLimit = 10000
+)
+
+type QueuePositions struct {
+	data          *data.Data
+	lanes         *laneRouter
+	defaultWeight int
+	weights       map[string]int
+	logger        *zap.Logger
+
+	// mu guards snapshots only; lane scans run without it.
+	mu        sync.Mutex
+	snapshots map[string]laneSnapshot
+}
+
+type laneSnapshot struct {
+	positions map[uuid.UUID]int
+	takenAt   time.Time
+}
+
+type queuedOperation struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Type      string
+	CreatedAt time.Time
+}
+
+func NewQueuePositions(cfg *conf.Config, d *data.Data, logger *zap.Logger) *QueuePositions {
+	q := &QueuePositions{
+		data:          d,
+		lanes:         newLaneRouter(cfg.Kafka),
+		defaultWeight: cfg.Fairness.DefaultWeight,
+		weights:       make(map[string]int, len(cfg.Fairness.UserWeights)),
+		logger:        logger,
+		snapshots:     make(map[string]laneSnapshot),
+	}
+	if q.defaultWeight <= 0 {
+		q.defaultWeight = 1
+	}
+	for id, weight := range cfg.Fairness.UserWeights {
+		if weight > 0 {
+			q.weights[strings.ToLower(strings.TrimSpace(id))] = weight
+		}
+	}
+	return q
+}
+
+func (q *QueuePositions) Attach(ctx context.Context, ops ...*model.Operation) {
+	if q == nil || q.data == nil || q.data.DB == nil {
+		return
+	}
+	targets := make(map[uuid.UUID]*model.Operation)
+	lanes := make(map[string]struct{})
+	for _, op := range ops {
+		if op == nil || op.Status != global.OpQueued {
+			continue
+		}
+		targets[op.ID] = op
+		lanes[q.lane(op.UserID, op.Type)] = struct{}{}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	for lane := range lanes {
+		positions, ok := q.lanePositions(ctx, lane)
+		if !ok {
+			continue
+		}
+		for id, target := range targets {
+			if position, ok := positions[id]; ok {
+				target.QueuePosition = &position
+			}
+		}
+	}
+}
+
+func (q *QueuePositions) lanePositions(ctx context.Context, lane string) (map[uuid.UUID]int, bool) {
+	q.mu.Lock()
+	snap, ok := q.snapshots[lane]
+	q.mu.Unlock()
+	if ok && time.Since(snap.takenAt) < queuePositionCacheTTL {
+		return snap.positions, true
+	}
+	query := q.data.DB.WithContext(ctx).
+		Model(&model.Operation{}).
+		Select("id, user_id, type, created_at").
+		Where("status = ?", global.OpQueued)
+	priority := q.priorityUsers()
+	if lane == LanePriority {
+		query = query.Where("user_id IN ?", priority)
+	} else {
+		query = query.Where("type IN ?", q.laneTypes(lane))
+		if len(priority) > 0 {
+			query = query.Where("user_id NOT IN ?", priority)
+		}
+	}
+	var queued []queuedOperation
+	if err := query.
+		Order("created_at ASC, id ASC").
+		Limit(queuePositionScanLimit).
+		Scan(&queued).Error; err != nil {
+		q.logger.Warn("query queued operations", zap.String("lane", lane), zap.Error(err))
+		return nil, false
+	}
+	positions := make(map[uuid.UUID]int, len(queued))
+	for pos, op := range q.order(queued) {
+		positions[op.ID] = pos + 1
+	}
+	q.mu.Lock()
+	q.snapshots[lane] = laneSnapshot{positions: positions, takenAt: time.Now()}
+	q.mu.Unlock()
+	return positions, true
+}
+
+func (q *QueuePositions) laneTypes(lane string) []string {
+	var types []string
+	for opType, action := range operationActions {
+		if routed, _ := q.lanes.Route(StoryJobMessage{Payload: StoryJobPayload{Action: action}}); routed == lane {
+			types = append(types, opType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (q *QueuePositions) priorityUsers() []string {
+	if _, ok := q.lanes.topics[LanePriority]; !ok {
+		return nil
+	}
+	users := make([]string, 0, len(q.lanes.priorityUsers))
+	for id := range q.lanes.priorityUsers {
+		if _, err := uuid.Parse(id); err == nil {
+			users = append(users, id)
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+func (q *QueuePositions) lane(userID uuid.UUID, opType string) string {
+	lane, _ := q.lanes.Route(StoryJobMessage{
+		UserID:  userID.String(),
+		Payload: StoryJobPayload{Action: operationActions[opType]},
+	})
+	return lane
+}
+
+// order mirrors the worker's weighted round robin: each user contributes
+// up to its weight per round, rounds are served in order of arrival.
+func (q *QueuePositions) order(ops []queuedOperation) []queuedOperation {
+	type ranked struct {
+		queuedOperation
+		round int
+	}
+	seen := make(map[uuid.UUID]int, len(ops))
+	items := make([]ranked, 0, len(ops))
+	for _, op := range ops {
+		weight := q.defaultWeight
+		if w, ok := q.weights[strings.ToLower(op.UserID.String())]; ok {
+			weight = w
+		}
+		items = append(items, ranked{queuedOperation: op, round: seen[op.UserID] / weight})
+		seen[op.UserID]++
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].round < items[j].round
+	})
+	out := make([]queuedOperation, len(items))
+	for idx, item := range items {
+		out[idx] = item.queuedOperation
+	}
+	return out
+}
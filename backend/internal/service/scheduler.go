@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"story2video-backend/internal/conf"
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	defaultSchedulerInterval  = 15 * time.Second
+	defaultSchedulerBatchSize = 50
+	// dispatchLease is how long a claimed operation may stay unpublished
+	// before the poller assumes the claiming process died and reclaims it.
+	dispatchLease = 2 * time.Minute
+)
+
+type schedulePolicy struct {
+	maxAhead time.Duration
+}
+
+func newSchedulePolicy(cfg *conf.Config) schedulePolicy {
+	return schedulePolicy{maxAhead: time.Duration(cfg.Scheduler.MaxAheadDays) * 24 * time.Hour}
+}
+
+func (p schedulePolicy) CheckScheduleTime(at time.Time) (MessageKey, MessageParams, bool) {
+	now := time.Now()
+	if !at.After(now) {
+		return MsgFieldFuture, nil, false
+	}
+	if p.maxAhead > 0 {
+		if limit := now.Add(p.maxAhead); at.After(limit) {
+			return MsgFieldMax, MessageParams{"limit": limit.UTC().Format(time.RFC3339)}, false
+		}
+	}
+	return "", nil, true
+}
+
+type StoryScheduler struct {
+	data       *data.Data
+	dispatcher *jobDispatcher
+	batches    *BatchTracker
+	interval   time.Duration
+	batchSize  int
+	logger     *zap.Logger
+}
+
+func NewStoryScheduler(cfg *conf.Config, d *data.Data, logger *zap.Logger) *StoryScheduler {
+	interval := time.Duration(cfg.Scheduler.PollIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultSchedulerInterval
+	}
+	batchSize := cfg.Scheduler.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSchedulerBatchSize
+	}
+	return &StoryScheduler{
+		data:       d,
+		dispatcher: newJobDispatcher(logger, newKafkaProducer(cfg, logger)),
+		batches:    NewBatchTracker(cfg, d, logger),
+		interval:   interval,
+		batchSize:  batchSize,
+		logger:     logger,
+	}
+}
+
+func (s *StoryScheduler) Close() error {
+	if s == nil || s.dispatcher == nil {
+		return nil
+	}
+	return errors.Join(s.dispatcher.Close(), s.batches.Close())
+}
+
+func (s *StoryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := s.DispatchDue(ctx)
+			if err != nil {
+				s.logger.Error("dispatch scheduled operations", zap.Error(err))
+			}
+			if err != nil || n < s.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type claimedOperation struct {
+	op  model.Operation
+	job StoryJobMessage
+}
+
+// DispatchDue claims due scheduled operations and publishes their jobs.
+// A claim moves the operation to queued under a dispatch lease that is
+// cleared once Kafka accepts the message; operations whose lease expired
+// without being published are claimed again on a later poll.
+func (s *StoryScheduler) DispatchDue(ctx context.Context) (int, error) {
+	var claimed []claimedOperation
+	batches := make(map[uuid.UUID]struct{})
+	users := make(map[uuid.UUID]struct{})
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var ops []model.Operation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND scheduled_at <= ?) OR (status = ? AND dispatch_lease < ?)", global.OpScheduled, now, global.OpQueued, now).
+			Order("scheduled_at ASC").
+			Limit(s.batchSize).
+			Find(&ops).Error; err != nil {
+			return WrapServiceError(ErrCodeDatabaseActionFailed, "查询定时任务失败", err)
+		}
+		lease := now.Add(dispatchLease)
+		for idx := range ops {
+			op := ops[idx]
+			users[op.UserID] = struct{}{}
+			job, err := operationJobMessage(&op)
+			if err != nil {
+				s.logger.Error("decode scheduled operation", zap.String(string(LogKeyOperationID), op.ID.String()), zap.Error(err))
+				if err := failScheduledOperation(tx, &op, err); err != nil {
+					return err
+				}
+				if op.BatchID != nil {
+					batches[*op.BatchID] = struct{}{}
+				}
+				continue
+			}
+			if err := tx.Model(&model.Operation{}).
+				Where("id = ?", op.ID).
+				Updates(map[string]interface{}{
+					"status":         global.OpQueued,
+					"dispatch_lease": lease,
+				}).Error; err != nil {
+				return WrapServiceError(ErrCodeOperationUpdateFailed, "更新定时任务状态失败", err)
+			}
+			if op.Type == global.OpStoryboard {
+				if err := tx.Model(&model.Story{}).
+					Where("id = ?", op.StoryID).
+					Update("status", global.StoryGen).Error; err != nil {
+					return WrapServiceError(ErrCodeDatabaseActionFailed, "更新故事状态失败", err)
+				}
+			}
+			claimed = append(claimed, claimedOperation{op: op, job: job})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		published   []uuid.UUID
+		dispatchErr error
+	)
+	for idx := range claimed {
+		item := &claimed[idx]
+		if dispatchErr == nil {
+			dispatchErr = s.dispatcher.Dispatch(ctx, item.job)
+			if dispatchErr == nil {
+				published = append(published, item.op.ID)
+				continue
+			}
+		}
+		s.releaseClaim(ctx, &item.op)
+	}
+	if len(published) > 0 {
+		if err := s.data.DB.WithContext(ctx).
+			Model(&model.Operation{}).
+			Where("id IN ?", published).
+			Update("dispatch_lease", nil).Error; err != nil {
+			s.logger.Warn("clear dispatch lease", zap.Int("count", len(published)), zap.Error(err))
+		}
+	}
+	for batchID := range batches {
+		s.batches.Finalize(ctx, batchID)
+	}
+	for userID := range users {
+		InvalidateStoryListCache(ctx, s.data, userID)
+	}
+	return len(published), dispatchErr
+}
+
+func failScheduledOperation(tx *gorm.DB, op *model.Operation, cause error) error {
+	updates, err := operationFailureUpdates(cause)
+	if err != nil {
+		return err
+	}
+	updates["dispatch_lease"] = nil
+	if err := tx.Model(&model.Operation{}).Where("id = ?", op.ID).Updates(updates).Error; err != nil {
+		return WrapServiceError(ErrCodeOperationUpdateFailed, "更新任务为失败状态失败", err)
+	}
+	return releaseCancelledTarget(tx, op)
+}
+
+func (s *StoryScheduler) releaseClaim(ctx context.Context, op *model.Operation) {
+	err := s.data.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Operation{}).
+			Where("id = ? AND status = ?", op.ID, global.OpQueued).
+			Updates(map[string]interface{}{
+				"status":         global.OpScheduled,
+				"dispatch_lease": nil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 || op.Type != global.OpStoryboard {
+			return nil
+		}
+		return tx.Model(&model.Story{}).
+			Where("id = ? AND status = ?", op.StoryID, global.StoryGen).
+			Update("status", global.StoryDraft).Error
+	})
+	if err != nil {
+		s.logger.Warn("release scheduled operation", zap.String(string(LogKeyOperationID), op.ID.String()), zap.Error(err))
+	}
+}
+
+type storedJobPayload struct {
+	StoryJobPayload
+	Details string `json:"details,omitempty"`
+	Format  string `json:"format,omitempty"`
+}
+
+func operationJobMessage(op *model.Operation) (StoryJobMessage, error) {
+	action, ok := operationActions[op.Type]
+	if !ok {
+		return StoryJobMessage{}, fmt.Errorf("unsupported operation type %q", op.Type)
+	}
+	var stored storedJobPayload
+	if len(op.Payload) > 0 {
+		if err := json.Unmarshal(op.Payload, &stored); err != nil {
+			return StoryJobMessage{}, err
+		}
+	}
+	payload := stored.StoryJobPayload
+	if payload.Action == "" {
+		payload.Action = action
+	}
+	if payload.ShotDetails == "" {
+		payload.ShotDetails = stored.Details
+	}
+	if payload.ExportFormat == "" {
+		payload.ExportFormat = stored.Format
+	}
+	if payload.ShotID == "" && op.Type == global.OpShotRegen {
+		payload.ShotID = op.ShotID.String()
+	}
+	job := StoryJobMessage{
+		OperationID: op.ID.String(),
+		StoryID:     op.StoryID.String(),
+		UserID:      op.UserID.String(),
+		Payload:     payload,
+		CreatedAt:   time.Now(),
+	}
+	if op.BatchID != nil {
+		job.BatchID = op.BatchID.String()
+	}
+	if op.ParentID != nil {
+		job.ParentID = op.ParentID.String()
+	}
+	return job, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"story2video-backend/internal/conf"
+)
+
+const (
+	defaultScriptMinRunes         = 10
+	defaultScriptMaxShots         = 30
+	defaultScriptUnitsPerShot     = 80
+	defaultShotProcessingSeconds  = 25
+	defaultCostPerShot            = 1
+	defaultCostPerVideoMinute     = 2
+	scriptPlanningOverheadSeconds = 30
+)
+
+// defaultScriptMaxRunes is derived from the shot cap: it is the length of
+// the densest (CJK) script that still fits, so the length limit is
+// reachable in every supported language rather than only for English.
+const defaultScriptMaxRunes = defaultScriptMaxShots * defaultScriptUnitsPerShot
+
+type ScriptViolation struct {
+	Code    ErrorCode     `json:"code"`
+	Message string        `json:"message"`
+	Key     MessageKey    `json:"-"`
+	Params  MessageParams `json:"-"`
+}
+
+type ScriptEstimate struct {
+	Language                   string  `json:"language"`
+	CharCount                  int     `json:"char_count"`
+	EstimatedShots             int     `json:"estimated_shots"`
+	EstimatedVideoSeconds      float64 `json:"estimated_video_seconds"`
+	EstimatedProcessingSeconds int     `json:"estimated_processing_seconds"`
+	ProjectedCost              float64 `json:"projected_cost"`
+}
+
+type ScriptCheckResult struct {
+	Valid      bool              `json:"valid"`
+	Violations []ScriptViolation `json:"violations"`
+	Estimate   ScriptEstimate    `json:"estimate"`
+}
+
+type ScriptChecker struct {
+	minRunes           int
+	maxRunes           int
+	maxShots           int
+	unitsPerShot       int
+	allowedLanguages   map[string]struct{}
+	bannedWords        []string
+	processingSeconds  int
+	costPerShot        float64
+	costPerVideoMinute float64
+}
+
+func NewScriptChecker(cfg *conf.Config) *ScriptChecker {
+	c := &ScriptChecker{
+		minRunes:           defaultScriptMinRunes,
+		maxRunes:           defaultScriptMaxRunes,
+		maxShots:           defaultScriptMaxShots,
+		unitsPerShot:       defaultScriptUnitsPerShot,
+		allowedLanguages:   map[string]struct{}{"zh": {}, "en": {}},
+		processingSeconds:  defaultShotProcessingSeconds,
+		costPerShot:        defaultCostPerShot,
+		costPerVideoMinute: defaultCostPerVideoMinute,
+	}
+	if cfg == nil {
+		return c
+	}
+	sc := cfg.Script
+	if sc.MinRunes > 0 {
+		c.minRunes = sc.MinRunes
+	}
+	if sc.UnitsPerShot > 0 {
+		c.unitsPerShot = sc.UnitsPerShot
+	}
+	if sc.MaxShots > 0 {
+		c.maxShots = sc.MaxShots
+	}
+	c.maxRunes = c.maxShots * c.unitsPerShot
+	if sc.MaxRunes > 0 {
+		c.maxRunes = sc.MaxRunes
+	}
+	if len(sc.AllowedLanguages) > 0 {
+		c.allowedLanguages = make(map[string]struct{}, len(sc.AllowedLanguages))
+		for _, lang := range sc.AllowedLanguages {
+			c.allowedLanguages[strings.ToLower(strings.TrimSpace(lang))] = struct{}{}
+		}
+	}
+	for _, word := range sc.BannedWords {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			c.bannedWords = append(c.bannedWords, word)
+		}
+	}
+	if sc.ShotProcessingSeconds > 0 {
+		c.processingSeconds = sc.ShotProcessingSeconds
+	}
+	if sc.CostPerShot > 0 {
+		c.costPerShot = sc.CostPerShot
+	}
+	if sc.CostPerVideoMinute > 0 {
+		c.costPerVideoMinute = sc.CostPerVideoMinute
+	}
+	return c
+}
+
+func (c *ScriptChecker) MaxShots() int {
+	return c.maxShots
+}
+
+// MaxChapterRunes is the longest dense (CJK) text that passes both the
+// length and the shot-count checks.
+func (c *ScriptChecker) MaxChapterRunes() int {
+	if limit := c.maxShots * c.unitsPerShot; limit < c.maxRunes {
+		return limit
+	}
+	return c.maxRunes
+}
+
+func (c *ScriptChecker) Check(content string) *ScriptCheckResult {
+	result := &ScriptCheckResult{Violations: []ScriptViolation{}}
+	add := func(code ErrorCode, key MessageKey, params MessageParams) {
+		result.Violations = append(result.Violations, ScriptViolation{
+			Code:    code,
+			Message: Localize(DefaultLocale, key, params),
+			Key:     key,
+			Params:  params,
+		})
+	}
+
+	text := strings.TrimSpace(content)
+	runes := utf8.RuneCountInString(text)
+	result.Estimate.CharCount = runes
+	if runes == 0 {
+		add(ErrCodeScriptEmpty, MsgScriptEmpty, nil)
+		return result
+	}
+	if runes < c.minRunes {
+		add(ErrCodeScriptTooShort, MsgScriptTooShort, MessageParams{"min": c.minRunes, "count": runes})
+	}
+	if runes > c.maxRunes {
+		add(ErrCodeScriptTooLong, MsgScriptTooLong, MessageParams{"max": c.maxRunes, "count": runes})
+	}
+
+	lang, units := analyzeScript(text)
+	result.Estimate.Language = lang
+	if _, ok := c.allowedLanguages[lang]; !ok && lang != "" {
+		add(ErrCodeScriptLanguage, MsgScriptLanguage, MessageParams{"language": lang})
+	}
+
+	lower := strings.ToLower(text)
+	for _, word := range c.bannedWords {
+		if strings.Contains(lower, word) {
+			add(ErrCodeScriptBannedContent, MsgScriptBannedWord, MessageParams{"word": word})
+			break
+		}
+	}
+
+	shots := int(math.Ceil(float64(units) / float64(c.unitsPerShot)))
+	if shots < 1 {
+		shots = 1
+	}
+	result.Estimate.EstimatedShots = shots
+	if shots > c.maxShots {
+		add(ErrCodeScriptTooManyShots, MsgScriptTooManyShots, MessageParams{"shots": shots, "max": c.maxShots})
+	}
+
+	video := estimateNarrationDuration(text) + time.Duration(shots-1)*subtitleShotPadding
+	result.Estimate.EstimatedVideoSeconds = math.Round(video.Seconds()*10) / 10
+	result.Estimate.EstimatedProcessingSeconds = scriptPlanningOverheadSeconds + shots*c.processingSeconds
+	cost := float64(shots)*c.costPerShot + video.Minutes()*c.costPerVideoMinute
+	result.Estimate.ProjectedCost = math.Round(cost*100) / 100
+
+	result.Valid = len(result.Violations) == 0
+	return result
+}
+
+func (r *ScriptCheckResult) Err() *ServiceError {
+	if r == nil || len(r.Violations) == 0 {
+		return nil
+	}
+	v := r.Violations[0]
+	return NewLocalizedError(v.Code, v.Key, v.Params)
+}
+
+func (r *ScriptCheckResult) Localize(locale string) {
+	if r == nil {
+		return
+	}
+	for idx := range r.Violations {
+		v := &r.Violations[idx]
+		if v.Key != "" {
+			v.Message = Localize(locale, v.Key, v.Params)
+		}
+	}
+}
+
+func analyzeScript(text string) (string, int) {
+	var han, kana, hangul, latin, other, words int
+	inWord := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			if !inWord {
+				words++
+			}
+			inWord = true
+			continue
+		case unicode.IsLetter(r):
+			other++
+		}
+		inWord = false
+	}
+	units := han + kana + hangul + other + words*3/2
+
+	lang := ""
+	switch {
+	case kana > 0 && kana*5 >= han:
+		lang = "ja"
+	case hangul > han && hangul > latin:
+		lang = "ko"
+	case han > 0 && han*3 >= latin:
+		lang = "zh"
+	case latin > 0 && latin >= other:
+		lang = "en"
+	case other > 0:
+		lang = "und"
+	}
+	return lang, units
+}
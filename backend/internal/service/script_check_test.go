@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"story2video-backend/internal/conf"
+)
+
+func TestScriptCheckRejectsTooManyShots(t *testing.T) {
+	paragraph := strings.Repeat("少年推开尘封的木门，看见院子里站着一位白发老人，手里提着一盏昏黄的灯笼。", 4)
+	script := strings.TrimSpace(strings.Repeat(paragraph+"\n\n", 30))
+
+	cfg := &conf.Config{}
+	cfg.Script.MaxRunes = 8000
+	result := NewScriptChecker(cfg).Check(script)
+	if result.Valid {
+		t.Fatalf("script with %d estimated shots passed the check", result.Estimate.EstimatedShots)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Code != ErrCodeScriptTooManyShots {
+		t.Fatalf("violations = %+v, want only %s", result.Violations, ErrCodeScriptTooManyShots)
+	}
+}
+
+func TestScriptCheckAcceptsMaxLengthChineseScript(t *testing.T) {
+	sentence := "少年推开尘封的木门，看见院子里站着一位白发老人，手里提着一盏昏黄的灯笼。"
+	script := strings.TrimSpace(strings.Repeat(sentence, defaultScriptMaxRunes/utf8.RuneCountInString(sentence)))
+
+	shipped, err := conf.Load("../../config/config.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	for name, cfg := range map[string]*conf.Config{"defaults": {}, "config.yaml": shipped} {
+		t.Run(name, func(t *testing.T) {
+			checker := NewScriptChecker(cfg)
+			result := checker.Check(script)
+			if !result.Valid {
+				t.Fatalf("violations = %+v, want none for %d runes", result.Violations, result.Estimate.CharCount)
+			}
+			if result.Estimate.CharCount < checker.MaxChapterRunes()*9/10 {
+				t.Fatalf("script has %d runes, want close to the %d limit", result.Estimate.CharCount, checker.MaxChapterRunes())
+			}
+		})
+	}
+}
+
+func TestScriptCheckAcceptsMaxLengthEnglishScript(t *testing.T) {
+	sentence := "The old lighthouse keeper climbed the stairs and lit the lamp. "
+	script := strings.TrimSpace(strings.Repeat(sentence, defaultScriptMaxRunes/len(sentence)))
+
+	result := NewScriptChecker(&conf.Config{}).Check(script)
+	if !result.Valid {
+		t.Fatalf("violations = %+v, want none for %d runes", result.Violations, result.Estimate.CharCount)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -18,9 +21,15 @@ import (
 
 const ShotSequenceOrderClause = "CASE WHEN sequence ~ '^[0-9]+$' THEN sequence::INT ELSE 2147483647 END ASC, sequence ASC, created_at ASC"
 
+const (
+	shotSequenceNumberExpr = "CASE WHEN sequence ~ '^[0-9]+$' THEN sequence::INT ELSE 2147483647 END"
+	shotCursorSort         = "sequence"
+)
+
 type ShotService struct {
 	data       *data.Data
 	dispatcher *jobDispatcher
+	cursors    *CursorCodec
 }
 
 func NewShotService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ShotService {
@@ -28,6 +37,7 @@ func NewShotService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *ShotSer
 	return &ShotService{
 		data:       d,
 		dispatcher: newJobDispatcher(logger, prod),
+		cursors:    NewCursorCodec(cfg, logger),
 	}
 }
 
@@ -54,6 +64,70 @@ func (s *ShotService) List(ctx context.Context, userID, storyID uuid.UUID) ([]mo
 	return shots, nil
 }
 
+func (s *ShotService) ListPage(ctx context.Context, userID, storyID uuid.UUID, pageSize int, pageToken string) ([]model.Shot, string, error) {
+	filter := listFingerprint(storyID)
+	query := s.data.DB.WithContext(ctx).Where("story_id = ? AND user_id = ?", storyID, userID)
+	if pageToken != "" {
+		cur, err := s.cursors.Decode(pageToken)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := cur.expect(shotCursorSort, false, filter); err != nil {
+			return nil, "", err
+		}
+		if len(cur.Values) < 3 {
+			return nil, "", NewServiceError(ErrCodeInvalidRequest, "page_token 无效")
+		}
+		seqNum, err := strconv.Atoi(cur.Values[0])
+		if err != nil {
+			return nil, "", NewServiceError(ErrCodeInvalidRequest, "page_token 无效")
+		}
+		createdAt, err := cur.timeValue(2)
+		if err != nil {
+			return nil, "", err
+		}
+		query = applyKeyset(query,
+			[]string{shotSequenceNumberExpr, "COALESCE(sequence, '')", "created_at", "id"},
+			false, seqNum, cur.Values[1], createdAt, cur.ID)
+	}
+
+	var shots []model.Shot
+	if err := query.
+		Order(shotSequenceNumberExpr + " ASC, COALESCE(sequence, '') ASC, created_at ASC, id ASC").
+		Limit(pageSize + 1).
+		Find(&shots).Error; err != nil {
+		return nil, "", WrapServiceError(ErrCodeDatabaseActionFailed, "查询镜头列表失败", err)
+	}
+	if len(shots) == 0 && pageToken == "" {
+		if _, err := s.getStory(ctx, userID, storyID); err != nil {
+			return nil, "", err
+		}
+	}
+	if len(shots) <= pageSize {
+		return shots, "", nil
+	}
+	shots = shots[:pageSize]
+	last := shots[len(shots)-1]
+	next := s.cursors.Encode(PageCursor{
+		Sort:   shotCursorSort,
+		Values: []string{strconv.Itoa(shotSequenceNumber(last.Sequence)), last.Sequence, formatCursorTime(last.CreatedAt)},
+		ID:     last.ID,
+		Filter: filter,
+	})
+	return shots, next, nil
+}
+
+func shotSequenceNumber(sequence string) int {
+	if sequence == "" || strings.Trim(sequence, "0123456789") != "" {
+		return math.MaxInt32
+	}
+	n, err := strconv.Atoi(sequence)
+	if err != nil || n > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return n
+}
+
 func (s *ShotService) Get(ctx context.Context, userID, storyID, shotID uuid.UUID) (*model.Shot, error) {
 	var shot model.Shot
 	if err := s.data.DB.WithContext(ctx).
@@ -76,6 +150,9 @@ func (s *ShotService) UpdateScript(ctx context.Context, userID, storyID, shotID
 	if err != nil {
 		return nil, err
 	}
+	if err := ensureStoryNotHeld(s.data.DB.WithContext(ctx), story); err != nil {
+		return nil, err
+	}
 
 	if script == "" {
 		script = shot.Details
@@ -88,10 +165,13 @@ func (s *ShotService) UpdateScript(ctx context.Context, userID, storyID, shotID
 		return nil, NewServiceError(ErrCodeInvalidRequest, "无效的镜头 ID")
 	}
 
-	payloadBytes, err := json.Marshal(map[string]string{
-		"shot_id": shotID.String(),
-		"details": script,
-	})
+	payload := StoryJobPayload{
+		Style:       story.Style,
+		ShotID:      shotID.String(),
+		ShotDetails: script,
+		Action:      "regen_shot",
+	}
+	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "序列化镜头任务参数失败", err)
 	}
@@ -105,15 +185,10 @@ func (s *ShotService) UpdateScript(ctx context.Context, userID, storyID, shotID
 		OperationID: op.ID.String(),
 		StoryID:     storyID.String(),
 		UserID:      userID.String(),
-		Payload: StoryJobPayload{
-			Style:       story.Style,
-			ShotID:      shotID.String(),
-			ShotDetails: script,
-			Action:      "regen_shot",
-		},
-		CreatedAt: op.CreatedAt,
-	}
-	if err := s.dispatcher.Dispatch(job); err != nil {
+		Payload:     payload,
+		CreatedAt:   op.CreatedAt,
+	}
+	if err := s.dispatcher.Dispatch(ctx, job); err != nil {
 		_ = UpdateOperationFailure(ctx, s.data, op.ID, err)
 		if svcErr, ok := AsServiceError(err); ok {
 			return nil, svcErr
@@ -128,10 +203,16 @@ func (s *ShotService) RenderStory(ctx context.Context, userID, storyID uuid.UUID
 	if err != nil {
 		return nil, err
 	}
+	if err := ensureStoryNotHeld(s.data.DB.WithContext(ctx), story); err != nil {
+		return nil, err
+	}
 
-	payloadBytes, err := json.Marshal(map[string]string{
-		"story_id": storyID.String(),
-	})
+	payload := StoryJobPayload{
+		DisplayName: story.Title,
+		Style:       story.Style,
+		Action:      "render_video",
+	}
+	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, WrapServiceError(ErrCodeOperationCreateFailed, "序列化故事渲染参数失败", err)
 	}
@@ -145,15 +226,11 @@ func (s *ShotService) RenderStory(ctx context.Context, userID, storyID uuid.UUID
 		OperationID: op.ID.String(),
 		StoryID:     storyID.String(),
 		UserID:      userID.String(),
-		Payload: StoryJobPayload{
-			DisplayName: story.Title,
-			Style:       story.Style,
-			Action:      "render_video",
-		},
-		CreatedAt: op.CreatedAt,
+		Payload:     payload,
+		CreatedAt:   op.CreatedAt,
 	}
 
-	if err := s.dispatcher.Dispatch(job); err != nil {
+	if err := s.dispatcher.Dispatch(ctx, job); err != nil {
 		_ = UpdateOperationFailure(ctx, s.data, op.ID, err)
 		if svcErr, ok := AsServiceError(err); ok {
 			return nil, svcErr
@@ -187,6 +264,11 @@ func (s *ShotService) Update(ctx context.Context, userID, storyID, shotID uuid.U
 	if len(updates) == 0 {
 		return nil, NewServiceError(ErrCodeInvalidRequest, "没有可更新的字段")
 	}
+	shot, err := s.Get(ctx, userID, storyID, shotID)
+	if err != nil {
+		return nil, err
+	}
+	ClearStaleAudio(shot, updates)
 	if err := s.data.DB.WithContext(ctx).
 		Model(&model.Shot{}).
 		Where("id = ? AND story_id = ? AND user_id = ?", shotID, storyID, userID).
@@ -196,6 +278,25 @@ func (s *ShotService) Update(ctx context.Context, userID, storyID, shotID uuid.U
 	return s.Get(ctx, userID, storyID, shotID)
 }
 
+// ClearStaleAudio drops the synthesized narration audio when updates change
+// the narration or voice it was generated from, so the next render
+// synthesizes the clip again instead of reusing the old one.
+func ClearStaleAudio(shot *model.Shot, updates map[string]interface{}) {
+	if shot.AudioURL == "" {
+		return
+	}
+	for key, current := range map[string]string{"narration": shot.Narration, "voice": shot.Voice} {
+		value, ok := updates[key]
+		if !ok {
+			continue
+		}
+		if text, isText := value.(string); !isText || text != current {
+			updates["audio_url"] = ""
+			return
+		}
+	}
+}
+
 func (s *ShotService) getStory(ctx context.Context, userID, storyID uuid.UUID) (*model.Story, error) {
 	var story model.Story
 	if err := s.data.DB.WithContext(ctx).
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/data"
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+func createShotRegenJob(tx *gorm.DB, story *model.Story, shot *model.Shot) (*model.Operation, StoryJobMessage, error) {
+	payload := StoryJobPayload{
+		Style:       story.Style,
+		ShotID:      shot.ID.String(),
+		ShotDetails: shot.Details,
+		Action:      "regen_shot",
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, StoryJobMessage{}, WrapServiceError(ErrCodeOperationCreateFailed, "序列化镜头任务参数失败", err)
+	}
+	op := model.NewOperation(uuid.New(), story.UserID, story.ID, shot.ID, global.OpShotRegen, datatypes.JSON(payloadBytes))
+	if err := tx.Create(op).Error; err != nil {
+		return nil, StoryJobMessage{}, WrapServiceError(ErrCodeOperationCreateFailed, "创建镜头任务失败", err)
+	}
+	job := StoryJobMessage{
+		OperationID: op.ID.String(),
+		StoryID:     story.ID.String(),
+		UserID:      story.UserID.String(),
+		Payload:     payload,
+		CreatedAt:   op.CreatedAt,
+	}
+	return op, job, nil
+}
+
+func dispatchShotRegenJobs(ctx context.Context, d *data.Data, dispatcher *jobDispatcher, logger *zap.Logger, ops []*model.Operation, jobs []StoryJobMessage, shots []model.Shot) {
+	for idx, job := range jobs {
+		err := dispatcher.Dispatch(ctx, job)
+		if err == nil {
+			continue
+		}
+		_ = UpdateOperationFailure(ctx, d, ops[idx].ID, err)
+		ops[idx].Status = global.OpFail
+		ops[idx].ErrorMsg = err.Error()
+		for i := range shots {
+			if shots[i].ID == ops[idx].ShotID {
+				shots[i].Status = global.ShotFail
+			}
+		}
+		_ = d.DB.WithContext(ctx).
+			Model(&model.Shot{}).
+			Where("id = ?", ops[idx].ShotID).
+			Update("status", global.ShotFail).Error
+		logger.Warn(string(LogMsgKafkaPublishFailed),
+			zap.String(string(LogKeyOperationID), ops[idx].ID.String()),
+			zap.String(string(LogKeyStoryID), job.StoryID),
+			zap.Error(err),
+		)
+	}
+}
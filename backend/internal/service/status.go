@@ -0,0 +1,127 @@
+package service
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+
+	"story2video-backend/internal/global"
+	"story2video-backend/internal/model"
+)
+
+const (
+	ErrorDomain = "story2video"
+
+	statusTypeErrorInfo = "type.googleapis.com/google.rpc.ErrorInfo"
+	statusTypeDebugInfo = "type.googleapis.com/google.rpc.DebugInfo"
+)
+
+type OperationErrorDetails struct {
+	MessageKey MessageKey        `json:"message_key,omitempty"`
+	Params     MessageParams     `json:"params,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Debug      string            `json:"debug,omitempty"`
+}
+
+type StatusOptions struct {
+	IncludeDebug bool
+	Locale       string
+}
+
+type OperationStatus struct {
+	Code    int                      `json:"code"`
+	Status  string                   `json:"status"`
+	Message string                   `json:"message"`
+	Details []map[string]interface{} `json:"details,omitempty"`
+}
+
+func GRPCCode(code ErrorCode) codes.Code {
+	switch code {
+	case ErrCodeStoryNotFound, ErrCodeShotNotFound, ErrCodeOperationNotFound, ErrCodeModerationNotFound, ErrCodeBatchNotFound, ErrCodeMethodNotFound:
+		return codes.NotFound
+	case ErrCodeUnauthenticated:
+		return codes.Unauthenticated
+	case ErrCodePermissionDenied:
+		return codes.PermissionDenied
+	case ErrCodeContentFlagged, ErrCodeExportNotReady, ErrCodeOperationNotCancellable, ErrCodeOperationNotScheduled, ErrCodeOperationNotRetryable:
+		return codes.FailedPrecondition
+	case ErrCodeOperationTimeout:
+		return codes.DeadlineExceeded
+	case ErrCodeJobEnqueueFailed, ErrCodeWorkerExecutionFailed, ErrCodeModerationFailed:
+		return codes.Unavailable
+	case ErrCodeScriptTooLong, ErrCodeScriptTooManyShots, ErrCodePayloadTooLarge:
+		return codes.OutOfRange
+	case ErrCodeOperationCreateFailed, ErrCodeOperationUpdateFailed, ErrCodeKafkaConfigInvalid,
+		ErrCodeResultDataMissing, ErrCodeExportFailed, ErrCodeShotMissingPartial,
+		ErrCodeShotContentMissing, ErrCodeShotAssetMissing, ErrCodeInternal, ErrCodeDatabaseActionFailed:
+		return codes.Internal
+	}
+	if strings.HasPrefix(string(code), "SVC10") {
+		return codes.InvalidArgument
+	}
+	return codes.Unknown
+}
+
+func NewOperationStatus(op *model.Operation, opts StatusOptions) *OperationStatus {
+	if op == nil || (op.ErrorCode == "" && op.ErrorMsg == "" && op.Status != global.OpFail) {
+		return nil
+	}
+	code := ErrorCode(op.ErrorCode)
+	message := op.ErrorMsg
+	retryable := op.ErrorRetryable
+	var details OperationErrorDetails
+	if code == "" {
+		legacy := ParseOperationError(op.ErrorMsg)
+		code, message, retryable = legacy.Code, legacy.Message, legacy.Code.Retryable()
+		details.Debug = op.ErrorMsg
+	} else if len(op.ErrorDetails) > 0 {
+		_ = json.Unmarshal(op.ErrorDetails, &details)
+	}
+	switch {
+	case opts.Locale != "" && opts.Locale != DefaultLocale && details.MessageKey != "":
+		message = Localize(opts.Locale, details.MessageKey, details.Params)
+	case opts.Locale != "" && opts.Locale != DefaultLocale, message == "":
+		message = code.LocalizedMessage(opts.Locale)
+	}
+
+	metadata := map[string]string{"retryable": strconv.FormatBool(retryable)}
+	for k, v := range details.Metadata {
+		if opts.IncludeDebug || IsPublicErrorDetail(k) {
+			metadata[k] = v
+		}
+	}
+	grpcCode := GRPCCode(code)
+	status := &OperationStatus{
+		Code:    int(grpcCode),
+		Status:  canonicalCodeName(grpcCode),
+		Message: message,
+		Details: []map[string]interface{}{{
+			"@type":    statusTypeErrorInfo,
+			"reason":   string(code),
+			"domain":   ErrorDomain,
+			"metadata": metadata,
+		}},
+	}
+	if opts.IncludeDebug && details.Debug != "" {
+		status.Details = append(status.Details, map[string]interface{}{
+			"@type":  statusTypeDebugInfo,
+			"detail": details.Debug,
+		})
+	}
+	return status
+}
+
+func canonicalCodeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(name[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -6,6 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,10 +24,35 @@ import (
 	"story2video-backend/internal/model"
 )
 
+const storyLineageMaxDepth = 32
+
 type StoryService struct {
-	data     *data.Data
-	logger   *zap.Logger
-	cacheTTL time.Duration
+	data             *data.Data
+	logger           *zap.Logger
+	cacheTTL         time.Duration
+	searchOnce       sync.Once
+	searchMode       string
+	textSearchConfig string
+	snippetRunes     int
+	cursors          *CursorCodec
+	assets           *AssetStore
+}
+
+func StoryCompileState(status string) string {
+	switch status {
+	case global.StoryDraft:
+		return "STATE_PENDING"
+	case global.StoryGen:
+		return "STATE_RUNNING"
+	case global.StoryReady:
+		return "STATE_SUCCEEDED"
+	case global.StoryFail:
+		return "STATE_FAILED"
+	case global.StoryFlag:
+		return "STATE_FLAGGED"
+	default:
+		return "STATE_UNSPECIFIED"
+	}
 }
 
 func NewStoryService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *StoryService {
@@ -32,18 +62,38 @@ func NewStoryService(cfg *conf.Config, d *data.Data, logger *zap.Logger) *StoryS
 	} else if cfg.Redis.CacheTTLSeconds > 0 {
 		ttl = time.Duration(cfg.Redis.CacheTTLSeconds) * time.Second
 	}
-	return &StoryService{
-		data:     d,
-		logger:   logger,
-		cacheTTL: ttl,
+	svc := &StoryService{
+		data:             d,
+		logger:           logger,
+		cacheTTL:         ttl,
+		textSearchConfig: defaultTextSearchConfig,
+		snippetRunes:     defaultSearchSnippetRunes,
+		cursors:          NewCursorCodec(cfg, logger),
+		assets:           NewAssetStore(cfg),
 	}
+	if cfg != nil {
+		svc.searchMode = cfg.Search.Mode
+		if cfg.Search.TextSearchConfig != "" {
+			svc.textSearchConfig = cfg.Search.TextSearchConfig
+		}
+		if cfg.Search.SnippetRunes > 0 {
+			svc.snippetRunes = cfg.Search.SnippetRunes
+		}
+	}
+	return svc
 }
 
 type StoryListOptions struct {
 	Keyword    string
 	ExactTitle string
+	Query      string
+	Statuses   []string
+	Styles     []string
+	SortBy     string
+	SortDesc   bool
 	Limit      int
 	Offset     int
+	After      *PageCursor
 	StartTime  *time.Time
 	EndTime    *time.Time
 }
@@ -65,6 +115,14 @@ func (s *StoryService) ListStories(ctx context.Context, userID uuid.UUID, opts S
 		query = query.Where("title ILIKE ?", like)
 	}
 
+	query = s.applyFullTextSearch(ctx, query, opts.Query)
+	if len(opts.Statuses) > 0 {
+		query = query.Where("status IN ?", opts.Statuses)
+	}
+	if len(opts.Styles) > 0 {
+		query = query.Where("style IN ?", opts.Styles)
+	}
+
 	if opts.StartTime != nil {
 		query = query.Where("created_at >= ?", *opts.StartTime)
 	}
@@ -76,9 +134,24 @@ func (s *StoryService) ListStories(ctx context.Context, userID uuid.UUID, opts S
 		return nil, 0, WrapServiceError(ErrCodeDatabaseActionFailed, "统计故事数量失败", err)
 	}
 
-	query = query.Order("created_at desc")
+	sortKey, sortColumn := normalizedStorySort(opts)
+	direction := "asc"
+	if opts.SortDesc {
+		direction = "desc"
+	}
+	if opts.After != nil {
+		value, err := storyCursorValue(opts.After, sortKey)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = applyKeyset(query, []string{sortColumn, "id"}, opts.SortDesc, value, opts.After.ID)
+	}
+	query = query.Order(fmt.Sprintf("%s %s, id %s", sortColumn, direction, direction))
 	if opts.Limit > 0 {
-		query = query.Limit(opts.Limit).Offset(opts.Offset)
+		query = query.Limit(opts.Limit)
+		if opts.After == nil {
+			query = query.Offset(opts.Offset)
+		}
 	}
 
 	if err := query.Find(&stories).Error; err != nil {
@@ -90,7 +163,134 @@ func (s *StoryService) ListStories(ctx context.Context, userID uuid.UUID, opts S
 	return stories, total, nil
 }
 
+func normalizedStorySort(opts StoryListOptions) (string, string) {
+	if column, ok := allowedStorySorts[opts.SortBy]; ok {
+		return opts.SortBy, column
+	}
+	return StorySortCreatedAt, allowedStorySorts[StorySortCreatedAt]
+}
+
+func storyCursorValue(cur *PageCursor, sortKey string) (interface{}, error) {
+	if sortKey == StorySortTitle {
+		return cur.Values[0], nil
+	}
+	return cur.timeValue(0)
+}
+
+func (s *StoryService) ApplyPageToken(opts *StoryListOptions, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	if offset, ok := LegacyPageOffset(token); ok {
+		opts.Offset = offset
+		return true, nil
+	}
+	cur, err := s.cursors.Decode(token)
+	if err != nil {
+		return false, err
+	}
+	sortKey, _ := normalizedStorySort(*opts)
+	if err := cur.expect(sortKey, opts.SortDesc, storyListFingerprint(opts)); err != nil {
+		return false, err
+	}
+	opts.After = cur
+	return false, nil
+}
+
+func (s *StoryService) NextPageToken(stories []model.Story, opts StoryListOptions) string {
+	if len(stories) == 0 {
+		return ""
+	}
+	last := stories[len(stories)-1]
+	sortKey, _ := normalizedStorySort(opts)
+	var value string
+	switch sortKey {
+	case StorySortTitle:
+		value = last.Title
+	case StorySortUpdatedAt:
+		value = formatCursorTime(last.UpdatedAt)
+	default:
+		value = formatCursorTime(last.CreatedAt)
+	}
+	return s.cursors.Encode(PageCursor{
+		Sort:   sortKey,
+		Desc:   opts.SortDesc,
+		Values: []string{value},
+		ID:     last.ID,
+		Filter: storyListFingerprint(&opts),
+	})
+}
+
+func storyListFingerprint(opts *StoryListOptions) string {
+	filter := struct {
+		Keyword    string     `json:"k,omitempty"`
+		ExactTitle string     `json:"t,omitempty"`
+		Query      string     `json:"q,omitempty"`
+		Statuses   []string   `json:"st,omitempty"`
+		Styles     []string   `json:"sy,omitempty"`
+		Start      *time.Time `json:"from,omitempty"`
+		End        *time.Time `json:"to,omitempty"`
+	}{
+		Keyword:    opts.Keyword,
+		ExactTitle: opts.ExactTitle,
+		Query:      strings.TrimSpace(opts.Query),
+		Statuses:   sortedCopy(opts.Statuses),
+		Styles:     sortedCopy(opts.Styles),
+		Start:      opts.StartTime,
+		End:        opts.EndTime,
+	}
+	return listFingerprint(filter)
+}
+
 func (s *StoryService) Get(ctx context.Context, userID uuid.UUID, storyID uuid.UUID) (*model.Story, []model.Shot, error) {
+	story, shots, err := s.loadStoryWithShots(ctx, userID, storyID)
+	if err != nil {
+		return story, nil, err
+	}
+	if svcErr := validateStoryResult(story, shots); svcErr != nil {
+		return nil, nil, svcErr
+	}
+	return story, shots, nil
+}
+
+func (s *StoryService) Subtitles(ctx context.Context, userID uuid.UUID, storyID uuid.UUID, format string) (*SubtitleFile, error) {
+	format, err := NormalizeSubtitleFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	story, shots, err := s.loadStoryWithShots(ctx, userID, storyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureStoryNotHeld(s.data.DB.WithContext(ctx), story); err != nil {
+		return nil, err
+	}
+	return BuildSubtitles(story, shots, format)
+}
+
+func (s *StoryService) AssetFile(ctx context.Context, userID uuid.UUID, admin bool, storyID uuid.UUID, name string) (string, error) {
+	local, ok := s.assets.StoryPath(path.Join(s.assets.URLPrefix(), "stories", storyID.String(), name), storyID)
+	if !ok {
+		return "", NewServiceError(ErrCodeStoryNotFound, "素材不存在")
+	}
+	query := s.data.DB.WithContext(ctx).Unscoped().Model(&model.Story{}).Where("id = ?", storyID)
+	if !admin {
+		query = query.Where("user_id = ?", userID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return "", WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事详情失败", err)
+	}
+	if count == 0 {
+		return "", NewServiceError(ErrCodeStoryNotFound, "素材不存在")
+	}
+	if info, err := os.Stat(local); err != nil || info.IsDir() {
+		return "", NewServiceError(ErrCodeStoryNotFound, "素材不存在")
+	}
+	return local, nil
+}
+
+func (s *StoryService) loadStoryWithShots(ctx context.Context, userID uuid.UUID, storyID uuid.UUID) (*model.Story, []model.Shot, error) {
 	var story model.Story
 	if err := s.data.DB.WithContext(ctx).
 		Where("id = ? AND user_id = ?", storyID, userID).
@@ -107,12 +307,57 @@ func (s *StoryService) Get(ctx context.Context, userID uuid.UUID, storyID uuid.U
 		Find(&shots).Error; err != nil {
 		return &story, nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事下镜头失败", err)
 	}
-	if svcErr := validateStoryResult(&story, shots); svcErr != nil {
-		return nil, nil, svcErr
-	}
 	return &story, shots, nil
 }
 
+func (s *StoryService) Lineage(ctx context.Context, userID uuid.UUID, story *model.Story) (*StoryLineage, error) {
+	lineage := &StoryLineage{
+		Ancestors: []StoryLineageNode{},
+		Forks:     []StoryLineageNode{},
+	}
+	parentID := story.ParentStoryID
+	seen := map[uuid.UUID]struct{}{story.ID: {}}
+	for depth := 0; parentID != nil && depth < storyLineageMaxDepth; depth++ {
+		if _, loop := seen[*parentID]; loop {
+			break
+		}
+		seen[*parentID] = struct{}{}
+		var parent model.Story
+		err := s.data.DB.WithContext(ctx).
+			Where("id = ? AND user_id = ?", *parentID, userID).
+			First(&parent).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事血缘失败", err)
+		}
+		lineage.Ancestors = append(lineage.Ancestors, newStoryLineageNode(&parent))
+		parentID = parent.ParentStoryID
+	}
+
+	var forks []model.Story
+	if err := s.data.DB.WithContext(ctx).
+		Where("parent_story_id = ? AND user_id = ?", story.ID, userID).
+		Order("created_at asc").
+		Find(&forks).Error; err != nil {
+		return nil, WrapServiceError(ErrCodeDatabaseActionFailed, "查询故事副本失败", err)
+	}
+	for i := range forks {
+		lineage.Forks = append(lineage.Forks, newStoryLineageNode(&forks[i]))
+	}
+	return lineage, nil
+}
+
+func newStoryLineageNode(story *model.Story) StoryLineageNode {
+	return StoryLineageNode{
+		StoryID:     story.ID,
+		DisplayName: story.Title,
+		Style:       story.Style,
+		CreateTime:  story.CreatedAt,
+	}
+}
+
 func validateStoryResult(story *model.Story, shots []model.Shot) *ServiceError {
 	if story == nil {
 		return nil
@@ -120,6 +365,10 @@ func validateStoryResult(story *model.Story, shots []model.Shot) *ServiceError {
 	if story.Status != global.StoryReady {
 		return nil
 	}
+	return validateStoryShots(shots, true)
+}
+
+func validateStoryShots(shots []model.Shot, requireAssets bool) *ServiceError {
 	if len(shots) == 0 {
 		return NewServiceError(ErrCodeShotMissingPartial, "故事已完成但没有可用镜头")
 	}
@@ -135,17 +384,17 @@ func validateStoryResult(story *model.Story, shots []model.Shot) *ServiceError {
 		if shot.Details == "" || shot.Narration == "" || shot.Description == "" {
 			missingContent++
 		}
-		if shot.ImageURL == "" {
+		if requireAssets && shot.ImageURL == "" {
 			missingAsset++
 		}
 	}
 	switch {
 	case missingShots > 0:
-		return NewServiceError(ErrCodeShotMissingPartial, fmt.Sprintf("仍有 %d 个镜头未生成完成", missingShots))
+		return NewLocalizedError(ErrCodeShotMissingPartial, MsgShotsPending, MessageParams{"count": missingShots})
 	case missingContent > 0:
-		return NewServiceError(ErrCodeShotContentMissing, fmt.Sprintf("%d 个镜头内容缺失", missingContent))
+		return NewLocalizedError(ErrCodeShotContentMissing, MsgShotsContentMissing, MessageParams{"count": missingContent})
 	case missingAsset > 0:
-		return NewServiceError(ErrCodeShotAssetMissing, fmt.Sprintf("%d 个镜头素材缺失", missingAsset))
+		return NewLocalizedError(ErrCodeShotAssetMissing, MsgShotsAssetMissing, MessageParams{"count": missingAsset})
 	default:
 		return nil
 	}
@@ -165,18 +414,32 @@ func (s *StoryService) storyListCacheKey(userID uuid.UUID, opts StoryListOptions
 		return ""
 	}
 	payload := struct {
-		Keyword    string `json:"keyword,omitempty"`
-		ExactTitle string `json:"exact_title,omitempty"`
-		Limit      int    `json:"limit"`
-		Offset     int    `json:"offset"`
-		Start      string `json:"start,omitempty"`
-		End        string `json:"end,omitempty"`
+		Keyword    string   `json:"keyword,omitempty"`
+		ExactTitle string   `json:"exact_title,omitempty"`
+		Query      string   `json:"query,omitempty"`
+		Statuses   []string `json:"statuses,omitempty"`
+		Styles     []string `json:"styles,omitempty"`
+		SortBy     string   `json:"sort_by,omitempty"`
+		SortDesc   bool     `json:"sort_desc,omitempty"`
+		Limit      int      `json:"limit"`
+		Offset     int      `json:"offset"`
+		After      string   `json:"after,omitempty"`
+		Start      string   `json:"start,omitempty"`
+		End        string   `json:"end,omitempty"`
 	}{
 		Keyword:    opts.Keyword,
 		ExactTitle: opts.ExactTitle,
+		Query:      strings.TrimSpace(opts.Query),
+		Statuses:   sortedCopy(opts.Statuses),
+		Styles:     sortedCopy(opts.Styles),
+		SortBy:     opts.SortBy,
+		SortDesc:   opts.SortDesc,
 		Limit:      opts.Limit,
 		Offset:     opts.Offset,
 	}
+	if opts.After != nil {
+		payload.After = s.cursors.Encode(*opts.After)
+	}
 	if opts.StartTime != nil {
 		payload.Start = opts.StartTime.UTC().Format(time.RFC3339Nano)
 	}
@@ -194,6 +457,15 @@ func (s *StoryService) storyListCacheKey(userID uuid.UUID, opts StoryListOptions
 	return fmt.Sprintf("story:list:%s:%x", userID.String(), sum)
 }
 
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
+
 func (s *StoryService) getStoryListCache(ctx context.Context, key string) ([]model.Story, int64, bool) {
 	if !s.cacheEnabled() || key == "" {
 		return nil, 0, false
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"story2video-backend/internal/model"
+)
+
+const (
+	SearchModeAuto     = "auto"
+	SearchModeZhparser = "zhparser"
+	SearchModeTrigram  = "trgm"
+	SearchModeLike     = "like"
+
+	StorySortCreatedAt = "created_at"
+	StorySortUpdatedAt = "updated_at"
+	StorySortTitle     = "title"
+
+	defaultTextSearchConfig   = "chinese"
+	defaultSearchSnippetRunes = 40
+	searchHighlightOpen       = "<em>"
+	searchHighlightClose      = "</em>"
+)
+
+var allowedStorySorts = map[string]string{
+	StorySortCreatedAt: "created_at",
+	StorySortUpdatedAt: "updated_at",
+	StorySortTitle:     "COALESCE(title, '')",
+}
+
+type StoryHighlight struct {
+	Field   string    `json:"field"`
+	ShotID  uuid.UUID `json:"shot_id,omitempty"`
+	Snippet string    `json:"snippet"`
+}
+
+func NormalizeStorySort(sortBy, order string) (string, bool, error) {
+	if sortBy == "" {
+		sortBy = StorySortCreatedAt
+	}
+	column, ok := allowedStorySorts[sortBy]
+	if !ok {
+		return "", false, NewLocalizedError(ErrCodeInvalidRequest, MsgSortFieldUnsupported, MessageParams{"sort_by": sortBy})
+	}
+	switch strings.ToLower(order) {
+	case "":
+		return column, sortBy != StorySortTitle, nil
+	case "desc":
+		return column, true, nil
+	case "asc":
+		return column, false, nil
+	default:
+		return "", false, NewLocalizedError(ErrCodeInvalidRequest, MsgSortOrderUnsupported, MessageParams{"order": order})
+	}
+}
+
+func (s *StoryService) resolveSearchMode(ctx context.Context) string {
+	s.searchOnce.Do(func() {
+		mode := strings.ToLower(strings.TrimSpace(s.searchMode))
+		if mode != "" && mode != SearchModeAuto {
+			s.searchMode = mode
+			return
+		}
+		s.searchMode = SearchModeLike
+		if s.data == nil || s.data.DB == nil {
+			return
+		}
+		var hasConfig int64
+		if err := s.data.DB.WithContext(ctx).
+			Raw("SELECT COUNT(*) FROM pg_ts_config WHERE cfgname = ?", s.textSearchConfig).
+			Scan(&hasConfig).Error; err == nil && hasConfig > 0 {
+			s.searchMode = SearchModeZhparser
+			return
+		}
+		var hasTrgm int64
+		if err := s.data.DB.WithContext(ctx).
+			Raw("SELECT COUNT(*) FROM pg_extension WHERE extname = 'pg_trgm'").
+			Scan(&hasTrgm).Error; err == nil && hasTrgm > 0 {
+			s.searchMode = SearchModeTrigram
+		}
+		if s.logger != nil {
+			s.logger.Info("story search mode resolved", zap.String("mode", s.searchMode))
+		}
+	})
+	return s.searchMode
+}
+
+func (s *StoryService) applyFullTextSearch(ctx context.Context, query *gorm.DB, q string) *gorm.DB {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return query
+	}
+	switch s.resolveSearchMode(ctx) {
+	case SearchModeZhparser:
+		cfg := s.textSearchConfig
+		return query.Where(
+			"(to_tsvector(?::regconfig, coalesce(title, '') || ' ' || coalesce(content, '')) @@ plainto_tsquery(?::regconfig, ?) "+
+				"OR EXISTS (SELECT 1 FROM shots WHERE shots.story_id = stories.id AND shots.deleted_at IS NULL "+
+				"AND to_tsvector(?::regconfig, coalesce(shots.narration, '')) @@ plainto_tsquery(?::regconfig, ?)))",
+			cfg, cfg, q, cfg, cfg, q,
+		)
+	case SearchModeTrigram:
+		for _, term := range searchTerms(q) {
+			like := "%" + escapeLike(term) + "%"
+			query = query.Where(
+				"(title ILIKE ? OR content ILIKE ? OR ? <% coalesce(title, '') OR ? <% coalesce(content, '') "+
+					"OR EXISTS (SELECT 1 FROM shots WHERE shots.story_id = stories.id AND shots.deleted_at IS NULL "+
+					"AND (shots.narration ILIKE ? OR ? <% coalesce(shots.narration, ''))))",
+				like, like, term, term, like, term,
+			)
+		}
+		return query
+	default:
+		for _, term := range searchTerms(q) {
+			like := "%" + escapeLike(term) + "%"
+			query = query.Where(
+				"(title ILIKE ? OR content ILIKE ? OR EXISTS (SELECT 1 FROM shots WHERE shots.story_id = stories.id "+
+					"AND shots.deleted_at IS NULL AND shots.narration ILIKE ?))",
+				like, like, like,
+			)
+		}
+		return query
+	}
+}
+
+func (s *StoryService) SearchHighlights(ctx context.Context, stories []model.Story, q string) map[uuid.UUID][]StoryHighlight {
+	terms := searchTerms(q)
+	result := make(map[uuid.UUID][]StoryHighlight, len(stories))
+	if len(terms) == 0 || len(stories) == 0 {
+		return result
+	}
+	matcher := highlightPattern(terms)
+
+	ids := make([]uuid.UUID, 0, len(stories))
+	for _, st := range stories {
+		ids = append(ids, st.ID)
+		highlights := []StoryHighlight{}
+		if snippet, ok := buildSnippet(st.Title, matcher, s.snippetRunes); ok {
+			highlights = append(highlights, StoryHighlight{Field: "display_name", Snippet: snippet})
+		}
+		if snippet, ok := buildSnippet(st.Content, matcher, s.snippetRunes); ok {
+			highlights = append(highlights, StoryHighlight{Field: "script_content", Snippet: snippet})
+		}
+		result[st.ID] = highlights
+	}
+
+	var shots []model.Shot
+	if err := s.data.DB.WithContext(ctx).
+		Select("id", "story_id", "sequence", "narration").
+		Where("story_id IN ?", ids).
+		Order(ShotSequenceOrderClause).
+		Find(&shots).Error; err != nil {
+		if s.logger != nil {
+			s.logger.Warn("查询镜头旁白高亮失败", zap.Error(err))
+		}
+		return result
+	}
+	seen := make(map[uuid.UUID]struct{})
+	for _, shot := range shots {
+		if _, done := seen[shot.StoryID]; done {
+			continue
+		}
+		if snippet, ok := buildSnippet(shot.Narration, matcher, s.snippetRunes); ok {
+			seen[shot.StoryID] = struct{}{}
+			result[shot.StoryID] = append(result[shot.StoryID], StoryHighlight{Field: "narration", ShotID: shot.ID, Snippet: snippet})
+		}
+	}
+	return result
+}
+
+func searchTerms(q string) []string {
+	fields := strings.Fields(q)
+	seen := make(map[string]struct{}, len(fields))
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		key := strings.ToLower(f)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		terms = append(terms, f)
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	return terms
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+func highlightPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
+
+func buildSnippet(text string, matcher *regexp.Regexp, window int) (string, bool) {
+	loc := matcher.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+	if window <= 0 {
+		window = defaultSearchSnippetRunes
+	}
+	start, end := loc[0], loc[1]
+	for i := 0; i < window/2 && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+	for i := 0; i < window/2 && end < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+	raw := text[start:end]
+	var b strings.Builder
+	last := 0
+	for _, m := range matcher.FindAllStringIndex(raw, -1) {
+		b.WriteString(html.EscapeString(raw[last:m[0]]))
+		b.WriteString(searchHighlightOpen)
+		b.WriteString(html.EscapeString(raw[m[0]:m[1]]))
+		b.WriteString(searchHighlightClose)
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(raw[last:]))
+	fragment := strings.Join(strings.Fields(b.String()), " ")
+	if start > 0 {
+		fragment = "…" + fragment
+	}
+	if end < len(text) {
+		fragment += "…"
+	}
+	return fragment, true
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"story2video-backend/internal/model"
+)
+
+const (
+	SubtitleFormatSRT = "srt"
+	SubtitleFormatVTT = "vtt"
+)
+
+const (
+	subtitleMaxCueWidth     = 42
+	subtitleMinCueDuration  = 1200 * time.Millisecond
+	subtitleEmptyShotLength = 2 * time.Second
+	subtitleWideRuneCost    = 220 * time.Millisecond
+	subtitleNarrowRuneCost  = 70 * time.Millisecond
+	subtitleShotPadding     = 500 * time.Millisecond
+)
+
+type SubtitleCue struct {
+	Index  int
+	Start  time.Duration
+	End    time.Duration
+	Text   string
+	ShotID string
+}
+
+type SubtitleFile struct {
+	Format      string
+	ContentType string
+	Content     []byte
+}
+
+type storyTimeline struct {
+	Shots []storyTimelineShot `json:"shots"`
+}
+
+type storyTimelineShot struct {
+	ShotID     string `json:"shot_id"`
+	Sequence   string `json:"sequence"`
+	StartMs    *int64 `json:"start_ms,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func NormalizeSubtitleFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", SubtitleFormatSRT:
+		return SubtitleFormatSRT, nil
+	case SubtitleFormatVTT, "webvtt":
+		return SubtitleFormatVTT, nil
+	default:
+		return "", NewLocalizedError(ErrCodeInvalidRequest, MsgSubtitleFormatUnsupported, MessageParams{"format": format})
+	}
+}
+
+func BuildSubtitles(story *model.Story, shots []model.Shot, format string) (*SubtitleFile, error) {
+	format, err := NormalizeSubtitleFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	cues := BuildSubtitleCues(story, shots)
+	file := &SubtitleFile{Format: format}
+	switch format {
+	case SubtitleFormatVTT:
+		file.ContentType = "text/vtt; charset=utf-8"
+		file.Content = RenderWebVTT(cues)
+	default:
+		file.ContentType = "application/x-subrip; charset=utf-8"
+		file.Content = RenderSRT(cues)
+	}
+	return file, nil
+}
+
+func BuildSubtitleCues(story *model.Story, shots []model.Shot) []SubtitleCue {
+	timeline := parseStoryTimeline(story)
+	cues := make([]SubtitleCue, 0, len(shots))
+	var cursor time.Duration
+	for _, shot := range shots {
+		narration := strings.TrimSpace(shot.Narration)
+		start := cursor
+		duration := time.Duration(0)
+		if entry, ok := timeline[shot.ID.String()]; ok {
+			start, duration = entry.start(cursor), entry.duration()
+		} else if entry, ok := timeline[shot.Sequence]; ok && shot.Sequence != "" {
+			start, duration = entry.start(cursor), entry.duration()
+		}
+		if duration <= 0 && shot.AudioDurationMs > 0 {
+			duration = time.Duration(shot.AudioDurationMs) * time.Millisecond
+		}
+		if duration <= 0 {
+			if narration == "" {
+				duration = subtitleEmptyShotLength
+			} else {
+				duration = estimateNarrationDuration(narration)
+			}
+		}
+		cursor = start + duration
+		if narration == "" {
+			continue
+		}
+
+		segments := splitSubtitleText(narration, subtitleMaxCueWidth)
+		totalWidth := 0
+		for _, seg := range segments {
+			totalWidth += textWidth(seg)
+		}
+		segStart := start
+		for idx, seg := range segments {
+			segEnd := cursor
+			if idx < len(segments)-1 && totalWidth > 0 {
+				segEnd = segStart + duration*time.Duration(textWidth(seg))/time.Duration(totalWidth)
+			}
+			cues = append(cues, SubtitleCue{
+				Index:  len(cues) + 1,
+				Start:  segStart,
+				End:    segEnd,
+				Text:   seg,
+				ShotID: shot.ID.String(),
+			})
+			segStart = segEnd
+		}
+	}
+	return cues
+}
+
+func RenderSRT(cues []SubtitleCue) []byte {
+	var buf bytes.Buffer
+	for _, cue := range cues {
+		fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", cue.Index, formatSubtitleTime(cue.Start, ','), formatSubtitleTime(cue.End, ','), cue.Text)
+	}
+	return buf.Bytes()
+}
+
+func RenderWebVTT(cues []SubtitleCue) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&buf, "%d\n%s --> %s\n%s\n\n", cue.Index, formatSubtitleTime(cue.Start, '.'), formatSubtitleTime(cue.End, '.'), cue.Text)
+	}
+	return buf.Bytes()
+}
+
+func formatSubtitleTime(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+func parseStoryTimeline(story *model.Story) map[string]storyTimelineShot {
+	entries := map[string]storyTimelineShot{}
+	if story == nil || len(story.Timeline) == 0 {
+		return entries
+	}
+	var timeline storyTimeline
+	if err := json.Unmarshal(story.Timeline, &timeline); err != nil {
+		return entries
+	}
+	for _, item := range timeline.Shots {
+		if item.DurationMs <= 0 {
+			continue
+		}
+		if item.ShotID != "" {
+			entries[item.ShotID] = item
+		}
+		if item.Sequence != "" {
+			entries[item.Sequence] = item
+		}
+	}
+	return entries
+}
+
+func (e storyTimelineShot) start(fallback time.Duration) time.Duration {
+	if e.StartMs == nil || *e.StartMs < 0 {
+		return fallback
+	}
+	return time.Duration(*e.StartMs) * time.Millisecond
+}
+
+func (e storyTimelineShot) duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+func estimateNarrationDuration(text string) time.Duration {
+	var total time.Duration
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case isWideRune(r):
+			total += subtitleWideRuneCost
+		default:
+			total += subtitleNarrowRuneCost
+		}
+	}
+	total += subtitleShotPadding
+	if total < subtitleMinCueDuration {
+		total = subtitleMinCueDuration
+	}
+	return total
+}
+
+func splitSubtitleText(text string, maxWidth int) []string {
+	var result []string
+	for _, sentence := range splitByRunes(text, isSentenceEnd) {
+		if textWidth(sentence) <= maxWidth {
+			result = append(result, sentence)
+			continue
+		}
+		for _, clause := range mergeShort(splitByRunes(sentence, isClauseBreak), maxWidth) {
+			if textWidth(clause) <= maxWidth {
+				result = append(result, clause)
+				continue
+			}
+			result = append(result, hardWrap(clause, maxWidth)...)
+		}
+	}
+	return result
+}
+
+func splitByRunes(text string, isBreak func(r, next rune) bool) []string {
+	var (
+		parts []string
+		cur   strings.Builder
+	)
+	runes := []rune(text)
+	for i, r := range runes {
+		cur.WriteRune(r)
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		if isBreak(r, next) {
+			if part := strings.TrimSpace(cur.String()); part != "" {
+				parts = append(parts, part)
+			}
+			cur.Reset()
+		}
+	}
+	if part := strings.TrimSpace(cur.String()); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+func mergeShort(parts []string, maxWidth int) []string {
+	merged := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if n := len(merged); n > 0 {
+			joined := joinSubtitle(merged[n-1], part)
+			if textWidth(joined) <= maxWidth {
+				merged[n-1] = joined
+				continue
+			}
+		}
+		merged = append(merged, part)
+	}
+	return merged
+}
+
+func hardWrap(text string, maxWidth int) []string {
+	var (
+		lines     []string
+		cur       []rune
+		width     int
+		lastSpace = -1
+	)
+	for _, r := range text {
+		w := runeWidth(r)
+		if width+w > maxWidth && len(cur) > 0 {
+			if lastSpace > 0 {
+				lines = append(lines, strings.TrimSpace(string(cur[:lastSpace])))
+				cur = append([]rune{}, cur[lastSpace+1:]...)
+			} else {
+				lines = append(lines, strings.TrimSpace(string(cur)))
+				cur = cur[:0]
+			}
+			width = textWidth(string(cur))
+			lastSpace = -1
+		}
+		if r == ' ' {
+			lastSpace = len(cur)
+		}
+		cur = append(cur, r)
+		width += w
+	}
+	if rest := strings.TrimSpace(string(cur)); rest != "" {
+		lines = append(lines, rest)
+	}
+	return lines
+}
+
+func joinSubtitle(a, b string) string {
+	last, _ := utf8.DecodeLastRuneInString(a)
+	if isWideRune(last) || unicode.IsPunct(last) && last > unicode.MaxASCII {
+		return a + b
+	}
+	return a + " " + b
+}
+
+func isSentenceEnd(r, next rune) bool {
+	switch r {
+	case '。', '！', '？', '；', '…', '!', '?', ';':
+		return true
+	case '.':
+		return next == 0 || unicode.IsSpace(next)
+	}
+	return false
+}
+
+func isClauseBreak(r, next rune) bool {
+	switch r {
+	case '，', '、', '：':
+		return true
+	case ',', ':':
+		return next == 0 || unicode.IsSpace(next)
+	}
+	return false
+}
+
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	if isWideRune(r) {
+		return 2
+	}
+	return 1
+}
+
+func isWideRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) ||
+		(r >= 0x3000 && r <= 0x303F) ||
+		(r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,279 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxArchiveEntryBytes = 32 << 20
+	maxEpubSpineItems    = 2000
+)
+
+var ErrArchiveTooLarge = errors.New("archive content exceeds limit")
+
+type archive struct {
+	zr        *zip.Reader
+	remaining int64
+}
+
+func openZip(data []byte, limit int64) (*archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	if limit <= 0 {
+		limit = maxArchiveEntryBytes
+	}
+	return &archive{zr: zr, remaining: limit}, nil
+}
+
+func (a *archive) readEntry(name string) ([]byte, error) {
+	for _, f := range a.zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", name, err)
+		}
+		defer rc.Close()
+		limit := min(a.remaining, maxArchiveEntryBytes)
+		content, err := io.ReadAll(io.LimitReader(rc, limit+1))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		if int64(len(content)) > limit {
+			return nil, fmt.Errorf("%w: %s", ErrArchiveTooLarge, name)
+		}
+		a.remaining -= int64(len(content))
+		return content, nil
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+func parseDocx(data []byte, limit int64) ([]Chapter, error) {
+	ar, err := openZip(data, limit)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ar.readEntry("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		chapters  []Chapter
+		current   = Chapter{}
+		body      strings.Builder
+		para      strings.Builder
+		isHeading bool
+		inText    bool
+	)
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				para.Reset()
+				isHeading = false
+			case "pStyle", "outlineLvl":
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "val" && isDocxHeadingStyle(t.Name.Local, attr.Value) {
+						isHeading = true
+					}
+				}
+			case "t":
+				inText = true
+			case "tab":
+				para.WriteByte('\t')
+			case "br", "cr":
+				para.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				para.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				line := para.String()
+				trimmed := strings.TrimSpace(line)
+				if trimmed != "" && (isHeading || chapterHeadingPattern.MatchString(trimmed) && len([]rune(trimmed)) <= 40) {
+					current.Text = body.String()
+					chapters = append(chapters, current)
+					body.Reset()
+					current = Chapter{Title: trimmed}
+					continue
+				}
+				body.WriteString(line)
+				body.WriteByte('\n')
+			}
+		}
+	}
+	current.Text = body.String()
+	return append(chapters, current), nil
+}
+
+func isDocxHeadingStyle(element, value string) bool {
+	if element == "outlineLvl" {
+		return value == "0"
+	}
+	v := strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	return v == "heading1" || v == "title" || v == "1" || v == "标题1"
+}
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Title    string `xml:"metadata>title"`
+	Manifest []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+func parseEpub(data []byte, limit int64) (string, []Chapter, error) {
+	ar, err := openZip(data, limit)
+	if err != nil {
+		return "", nil, err
+	}
+	raw, err := ar.readEntry("META-INF/container.xml")
+	if err != nil {
+		return "", nil, err
+	}
+	var container epubContainer
+	if err := xml.Unmarshal(raw, &container); err != nil {
+		return "", nil, fmt.Errorf("parse container.xml: %w", err)
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", nil, errors.New("epub rootfile missing")
+	}
+	opfPath := container.Rootfiles[0].FullPath
+	raw, err = ar.readEntry(opfPath)
+	if err != nil {
+		return "", nil, err
+	}
+	var pkg epubPackage
+	if err := xml.Unmarshal(raw, &pkg); err != nil {
+		return "", nil, fmt.Errorf("parse %s: %w", opfPath, err)
+	}
+
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		if strings.Contains(item.MediaType, "html") {
+			hrefs[item.ID] = path.Join(path.Dir(opfPath), item.Href)
+		}
+	}
+	if len(pkg.Spine) > maxEpubSpineItems {
+		return "", nil, fmt.Errorf("%w: spine has %d items", ErrArchiveTooLarge, len(pkg.Spine))
+	}
+	var chapters []Chapter
+	seen := make(map[string]struct{}, len(pkg.Spine))
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		if _, dup := seen[href]; dup {
+			continue
+		}
+		seen[href] = struct{}{}
+		content, err := ar.readEntry(href)
+		if err != nil {
+			return "", nil, err
+		}
+		title, text := extractHTMLText(content)
+		chapters = append(chapters, Chapter{Title: title, Text: text})
+	}
+	return strings.TrimSpace(pkg.Title), chapters, nil
+}
+
+func extractHTMLText(content []byte) (string, string) {
+	var (
+		title   string
+		heading strings.Builder
+		body    strings.Builder
+		skip    int
+		inHead  int
+	)
+	z := html.NewTokenizer(bytes.NewReader(content))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if title == "" {
+				title = strings.TrimSpace(heading.String())
+			}
+			return title, body.String()
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				skip++
+			case "h1", "h2":
+				if title == "" {
+					inHead++
+				}
+			case "br":
+				body.WriteByte('\n')
+			}
+		case html.SelfClosingTagToken:
+			if name, _ := z.TagName(); string(name) == "br" {
+				body.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script", "style", "head":
+				if skip > 0 {
+					skip--
+				}
+			case "h1", "h2":
+				if inHead > 0 {
+					inHead--
+					title = strings.TrimSpace(heading.String())
+					continue
+				}
+				body.WriteByte('\n')
+			case "p", "div", "li", "h3", "h4", "h5", "h6", "blockquote":
+				body.WriteByte('\n')
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := strings.ReplaceAll(string(z.Text()), "\n", " ")
+			if inHead > 0 {
+				heading.WriteString(text)
+				continue
+			}
+			body.WriteString(text)
+		}
+	}
+}
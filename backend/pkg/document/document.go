@@ -0,0 +1,279 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const (
+	FormatText     = "txt"
+	FormatMarkdown = "md"
+	FormatDocx     = "docx"
+	FormatEpub     = "epub"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+type Chapter struct {
+	Title string
+	Text  string
+}
+
+type Document struct {
+	Title    string
+	Format   string
+	Chapters []Chapter
+}
+
+type Options struct {
+	MaxChapterRunes   int
+	MaxExtractedBytes int64
+}
+
+var (
+	chapterHeadingPattern = regexp.MustCompile(`^\s*(第[0-9零〇一二两三四五六七八九十百千万]+[章回节卷集部篇幕]|(?i:chapter|part)\s+[0-9ivxlcdm]+\b|序章|楔子|尾声|番外)`)
+	markdownHeading       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownImage         = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	markdownLink          = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	markdownEmphasis      = regexp.MustCompile("(\\*\\*|__|\\*|`)")
+	blankLines            = regexp.MustCompile(`\n{3,}`)
+)
+
+func DetectFormat(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".txt", ".text":
+		return FormatText
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".docx":
+		return FormatDocx
+	case ".epub":
+		return FormatEpub
+	default:
+		return ""
+	}
+}
+
+func Parse(filename string, data []byte, opts Options) (*Document, error) {
+	format := DetectFormat(filename)
+	doc := &Document{
+		Title:  strings.TrimSuffix(path.Base(filename), path.Ext(filename)),
+		Format: format,
+	}
+	var err error
+	switch format {
+	case FormatText:
+		var text string
+		if text, err = DecodeText(data); err == nil {
+			doc.Chapters = splitPlainText(text)
+		}
+	case FormatMarkdown:
+		var text string
+		if text, err = DecodeText(data); err == nil {
+			doc.Chapters = splitMarkdown(text)
+		}
+	case FormatDocx:
+		doc.Chapters, err = parseDocx(data, opts.MaxExtractedBytes)
+	case FormatEpub:
+		var title string
+		title, doc.Chapters, err = parseEpub(data, opts.MaxExtractedBytes)
+		if title != "" {
+			doc.Title = title
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path.Ext(filename))
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.Chapters = finalizeChapters(doc.Chapters, opts.MaxChapterRunes)
+	return doc, nil
+}
+
+func DecodeText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		decoded, _, err := transform.Bytes(unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewDecoder(), data)
+		if err != nil {
+			return "", fmt.Errorf("decode utf-16: %w", err)
+		}
+		data = decoded
+	case !utf8.Valid(data):
+		decoded, _, err := transform.Bytes(simplifiedchinese.GB18030.NewDecoder(), data)
+		if err != nil {
+			return "", fmt.Errorf("decode gbk: %w", err)
+		}
+		data = decoded
+	}
+	return normalizeNewlines(string(data)), nil
+}
+
+func normalizeNewlines(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.ReplaceAll(text, "　", " ")
+}
+
+func splitPlainText(text string) []Chapter {
+	var (
+		chapters []Chapter
+		current  = Chapter{}
+		body     strings.Builder
+	)
+	flush := func() {
+		current.Text = body.String()
+		chapters = append(chapters, current)
+		body.Reset()
+	}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && utf8.RuneCountInString(trimmed) <= 40 && chapterHeadingPattern.MatchString(trimmed) {
+			flush()
+			current = Chapter{Title: trimmed}
+			continue
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	flush()
+	return chapters
+}
+
+func splitMarkdown(text string) []Chapter {
+	lines := strings.Split(text, "\n")
+	counts := make([]int, 7)
+	for _, line := range lines {
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			counts[len(m[1])]++
+		}
+	}
+	level := 0
+	for l := 1; l <= 6; l++ {
+		if counts[l] >= 2 {
+			level = l
+			break
+		}
+	}
+	var (
+		chapters []Chapter
+		current  = Chapter{}
+		body     strings.Builder
+		inFence  bool
+	)
+	flush := func() {
+		current.Text = body.String()
+		chapters = append(chapters, current)
+		body.Reset()
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			if m := markdownHeading.FindStringSubmatch(line); m != nil {
+				if len(m[1]) == level {
+					flush()
+					current = Chapter{Title: stripMarkdown(m[2])}
+					continue
+				}
+				line = m[2]
+			}
+			line = stripMarkdown(line)
+		}
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	flush()
+	if level > 0 {
+		return chapters
+	}
+	return splitPlainText(chapters[0].Text)
+}
+
+func stripMarkdown(line string) string {
+	line = markdownImage.ReplaceAllString(line, "$1")
+	line = markdownLink.ReplaceAllString(line, "$1")
+	line = markdownEmphasis.ReplaceAllString(line, "")
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, prefix := range []string{"> ", "- ", "* ", "+ "} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return strings.TrimPrefix(trimmed, prefix)
+		}
+	}
+	return line
+}
+
+func finalizeChapters(chapters []Chapter, maxRunes int) []Chapter {
+	result := make([]Chapter, 0, len(chapters))
+	for _, ch := range chapters {
+		ch.Title = strings.TrimSpace(ch.Title)
+		ch.Text = strings.TrimSpace(blankLines.ReplaceAllString(ch.Text, "\n\n"))
+		if ch.Text == "" {
+			continue
+		}
+		parts := splitByLength(ch.Text, maxRunes)
+		for idx, part := range parts {
+			title := ch.Title
+			if len(parts) > 1 {
+				if title == "" {
+					title = fmt.Sprintf("(%d)", idx+1)
+				} else {
+					title = fmt.Sprintf("%s (%d)", title, idx+1)
+				}
+			}
+			result = append(result, Chapter{Title: title, Text: part})
+		}
+	}
+	return result
+}
+
+func splitByLength(text string, maxRunes int) []string {
+	if maxRunes <= 0 || utf8.RuneCountInString(text) <= maxRunes {
+		return []string{text}
+	}
+	var (
+		parts []string
+		cur   strings.Builder
+		count int
+	)
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			parts = append(parts, s)
+		}
+		cur.Reset()
+		count = 0
+	}
+	for _, para := range strings.Split(text, "\n") {
+		n := utf8.RuneCountInString(para)
+		if count > 0 && count+n > maxRunes {
+			flush()
+		}
+		if n > maxRunes {
+			runes := []rune(para)
+			offset := 0
+			for ; n-offset > maxRunes; offset += maxRunes {
+				cur.WriteString(string(runes[offset : offset+maxRunes]))
+				flush()
+			}
+			para = string(runes[offset:])
+			n -= offset
+		}
+		cur.WriteString(para)
+		cur.WriteByte('\n')
+		count += n + 1
+	}
+	flush()
+	return parts
+}
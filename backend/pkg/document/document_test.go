@@ -0,0 +1,42 @@
+package document
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseLongSingleLine(t *testing.T) {
+	const maxRunes = 8000
+	line := strings.Repeat("很长的一行文字没有换行", 400000)
+	start := time.Now()
+	doc, err := Parse("long.txt", []byte(line), Options{MaxChapterRunes: maxRunes})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("parse took %s", elapsed)
+	}
+	total := 0
+	for _, chapter := range doc.Chapters {
+		n := utf8.RuneCountInString(chapter.Text)
+		if n > maxRunes {
+			t.Fatalf("chapter %q has %d runes, want at most %d", chapter.Title, n, maxRunes)
+		}
+		total += n
+	}
+	if want := utf8.RuneCountInString(line); total != want {
+		t.Fatalf("chapters hold %d runes, want %d", total, want)
+	}
+}
+
+func BenchmarkParseLongSingleLine(b *testing.B) {
+	data := []byte(strings.Repeat("很长的一行文字没有换行", 400000))
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		if _, err := Parse("long.txt", data, Options{MaxChapterRunes: 8000}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version = "3.0.3"
+
+	contentTypeJSON      = "application/json"
+	contentTypeMultipart = "multipart/form-data"
+	securitySchemeUser   = "userId"
+)
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type OperationObject struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+}
+
+type PathItem map[string]*OperationObject
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Security   []map[string][]string `json:"security,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+
+	gen        *generator
+	errorModel interface{}
+	operations map[string]*OperationObject
+}
+
+type Binary struct {
+	ContentTypes []string
+}
+
+type Operation struct {
+	ID        string
+	Summary   string
+	Tags      []string
+	Public    bool
+	Query     interface{}
+	Body      interface{}
+	Form      interface{}
+	Responses map[int]interface{}
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+func NewDocument(title, version string, errorModel interface{}) *Document {
+	gen := newGenerator()
+	return &Document{
+		OpenAPI:  Version,
+		Info:     Info{Title: title, Version: version},
+		Security: []map[string][]string{{securitySchemeUser: {}}},
+		Paths:    make(map[string]PathItem),
+		Components: Components{
+			Schemas: gen.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				securitySchemeUser: {Type: "apiKey", In: "header", Name: "X-User-ID"},
+			},
+		},
+		gen:        gen,
+		errorModel: errorModel,
+		operations: make(map[string]*OperationObject),
+	}
+}
+
+func (d *Document) Add(method, path string, op Operation) {
+	if _, exists := d.operations[op.ID]; exists {
+		panic(fmt.Sprintf("openapi: duplicate operation id %q", op.ID))
+	}
+	path = SpecPath(path)
+	obj := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Responses:   make(map[string]*Response),
+	}
+	if op.Public {
+		obj.Security = &[]map[string][]string{}
+	}
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		param := &Schema{Type: "string"}
+		if strings.HasSuffix(match[1], "ID") {
+			param.Format = "uuid"
+		}
+		obj.Parameters = append(obj.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: param})
+	}
+	obj.Parameters = append(obj.Parameters, d.queryParameters(op.Query)...)
+	switch {
+	case op.Body != nil:
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentTypeJSON: {Schema: d.gen.schemaOf(op.Body, modeRequest)}},
+		}
+	case op.Form != nil:
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{contentTypeMultipart: {Schema: d.gen.object(reflect.TypeOf(op.Form), modeRequest, "form")}},
+		}
+	}
+	for status, body := range op.Responses {
+		obj.Responses[strconv.Itoa(status)] = d.response(http.StatusText(status), body)
+	}
+	if d.errorModel != nil {
+		obj.Responses["default"] = d.response("Error", d.errorModel)
+	}
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = obj
+	d.operations[op.ID] = obj
+}
+
+func (d *Document) Operation(id string) (*OperationObject, bool) {
+	obj, ok := d.operations[id]
+	return obj, ok
+}
+
+func (d *Document) response(description string, body interface{}) *Response {
+	resp := &Response{Description: description}
+	switch b := body.(type) {
+	case nil:
+	case Binary:
+		resp.Content = make(map[string]MediaType, len(b.ContentTypes))
+		for _, contentType := range b.ContentTypes {
+			resp.Content[contentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	default:
+		resp.Content = map[string]MediaType{contentTypeJSON: {Schema: d.gen.schemaOf(body, modeResponse)}}
+	}
+	return resp
+}
+
+func (d *Document) queryParameters(query interface{}) []Parameter {
+	if query == nil {
+		return nil
+	}
+	t := reflect.TypeOf(query)
+	params := make([]Parameter, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, skip := fieldName(field, "form")
+		if skip || name == "" {
+			continue
+		}
+		schema := d.gen.schema(field.Type, modeRequest, "form")
+		if values := oneOfValues(field); len(values) > 0 {
+			schema.Enum = values
+		}
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: fieldRequired(field, modeRequest, false),
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+func SpecPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*") {
+			continue
+		}
+		name, suffix, found := strings.Cut(seg[1:], ":")
+		seg = "{" + name + "}"
+		if found {
+			seg += ":" + suffix
+		}
+		segments[i] = seg
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ContextOperationKey = "openapi_operation"
+	ContextRouteKey     = "openapi_route"
+)
+
+type Routes struct {
+	doc   *Document
+	group *gin.RouterGroup
+}
+
+func NewRoutes(d *Document, group *gin.RouterGroup) *Routes {
+	return &Routes{doc: d, group: group}
+}
+
+func (r *Routes) Handle(method, path string, op Operation, handlers ...gin.HandlerFunc) {
+	route := joinPath(r.group.BasePath(), path)
+	r.doc.Add(method, route, op)
+	r.group.Handle(method, path, append([]gin.HandlerFunc{markOperation(op.ID, route)}, handlers...)...)
+}
+
+func (r *Routes) Method(method, path string, op Operation, fn gin.HandlerFunc) gin.HandlerFunc {
+	route := joinPath(r.group.BasePath(), path)
+	r.doc.Add(method, route, op)
+	mark := markOperation(op.ID, route)
+	return func(c *gin.Context) {
+		mark(c)
+		fn(c)
+	}
+}
+
+func (d *Document) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, d)
+	}
+}
+
+func (d *Document) Undocumented(routes gin.RoutesInfo, prefix string) []string {
+	var missing []string
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix) {
+			continue
+		}
+		item, ok := d.Paths[SpecPath(route.Path)]
+		if _, documented := item[strings.ToLower(route.Method)]; ok && documented {
+			continue
+		}
+		if d.customMethodsDocumented(route) {
+			continue
+		}
+		missing = append(missing, route.Method+" "+route.Path)
+	}
+	return missing
+}
+
+func (d *Document) customMethodsDocumented(route gin.RouteInfo) bool {
+	path := SpecPath(route.Path)
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 || !strings.HasPrefix(path[idx+1:], "{") {
+		return false
+	}
+	parent := path[:idx+1]
+	for specPath, item := range d.Paths {
+		if _, ok := item[strings.ToLower(route.Method)]; !ok {
+			continue
+		}
+		rest, ok := strings.CutPrefix(specPath, parent)
+		if ok && strings.Contains(rest, ":") && !strings.Contains(rest, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+type ContractReporter func(c *gin.Context, err error)
+
+func ContractCheck(d *Document, strict bool, report ContractReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &contractWriter{ResponseWriter: c.Writer, strict: strict}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		opID := c.GetString(ContextOperationKey)
+		var err error
+		if opID != "" && w.captured && !w.overflow {
+			err = d.ValidateResponse(opID, w.Status(), w.Header().Get("Content-Type"), w.buf.Bytes())
+		}
+		if err != nil {
+			report(c, err)
+			if strict {
+				return
+			}
+		}
+		if w.captured && strict {
+			_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		}
+	}
+}
+
+const maxContractCapture = 4 << 20
+
+type contractWriter struct {
+	gin.ResponseWriter
+	strict   bool
+	decided  bool
+	captured bool
+	overflow bool
+	buf      bytes.Buffer
+}
+
+func (w *contractWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.captured = strings.Contains(w.Header().Get("Content-Type"), "json")
+	}
+	if !w.captured {
+		return w.ResponseWriter.Write(p)
+	}
+	if w.strict {
+		return w.buf.Write(p)
+	}
+	if w.buf.Len()+len(p) <= maxContractCapture {
+		w.buf.Write(p)
+	} else {
+		w.overflow = true
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *contractWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func markOperation(id, route string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ContextOperationKey, id)
+		c.Set(ContextRouteKey, route)
+	}
+}
+
+func joinPath(base, path string) string {
+	if path == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
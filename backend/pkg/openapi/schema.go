@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"encoding/json"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+type schemaMode int
+
+const (
+	modeRequest schemaMode = iota
+	modeResponse
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	fileHeaderType = reflect.TypeOf(multipart.FileHeader{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (g *generator) schemaOf(v interface{}, mode schemaMode) *Schema {
+	if v == nil {
+		return nil
+	}
+	return g.schema(reflect.TypeOf(v), mode, "json")
+}
+
+func (g *generator) schema(t reflect.Type, mode schemaMode, tag string) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	s := g.inline(t, mode, tag)
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+func (g *generator) inline(t reflect.Type, mode schemaMode, tag string) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	case fileHeaderType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), mode, tag), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem(), mode, tag), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, mode, tag)
+		}
+		return &Schema{Ref: "#/components/schemas/" + g.component(t, mode, tag)}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) component(t reflect.Type, mode schemaMode, tag string) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := exportName(t.Name())
+	if _, taken := g.schemas[name]; taken {
+		name = exportName(pathBase(t.PkgPath())) + name
+	}
+	g.names[t] = name
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.object(t, mode, tag)
+	return name
+}
+
+func (g *generator) object(t reflect.Type, mode schemaMode, tag string) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	g.collectFields(s, t, mode, tag)
+	return s
+}
+
+func (g *generator) collectFields(s *Schema, t reflect.Type, mode schemaMode, tag string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := fieldName(field, tag)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.collectFields(s, ft, mode, tag)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		prop := g.schema(field.Type, mode, tag)
+		if values := oneOfValues(field); len(values) > 0 && prop.Ref == "" {
+			prop.Enum = values
+		}
+		s.Properties[name] = prop
+		if fieldRequired(field, mode, omitEmpty) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func fieldName(field reflect.StructField, tag string) (string, bool, bool) {
+	raw, ok := field.Tag.Lookup(tag)
+	if !ok {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(raw, ",")
+	if name == "-" && opts == "" {
+		return "", false, true
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+func fieldRequired(field reflect.StructField, mode schemaMode, omitEmpty bool) bool {
+	if mode == modeRequest {
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+		return false
+	}
+	if !omitEmpty {
+		return true
+	}
+	switch field.Type.Kind() {
+	case reflect.Struct, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+func oneOfValues(field reflect.StructField) []string {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return strings.Fields(values)
+		}
+	}
+	return nil
+}
+
+func exportName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func pathBase(pkgPath string) string {
+	if idx := strings.LastIndex(pkgPath, "/"); idx >= 0 {
+		return pkgPath[idx+1:]
+	}
+	return pkgPath
+}
@@ -0,0 +1,174 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ContractError struct {
+	OperationID string
+	Status      int
+	Problems    []string
+}
+
+func (e *ContractError) Error() string {
+	return fmt.Sprintf("openapi: %s responded %d off-contract: %s", e.OperationID, e.Status, strings.Join(e.Problems, "; "))
+}
+
+func (d *Document) ValidateResponse(operationID string, status int, contentType string, body []byte) error {
+	obj, ok := d.operations[operationID]
+	if !ok {
+		return &ContractError{OperationID: operationID, Status: status, Problems: []string{"operation is not documented"}}
+	}
+	resp, ok := obj.Responses[strconv.Itoa(status)]
+	if !ok && status >= 400 {
+		resp, ok = obj.Responses["default"]
+	}
+	if !ok {
+		return &ContractError{OperationID: operationID, Status: status, Problems: []string{"status is not documented"}}
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	media, ok := resp.Content[mediaType]
+	if !ok {
+		if len(resp.Content) == 0 && len(body) == 0 {
+			return nil
+		}
+		return &ContractError{OperationID: operationID, Status: status, Problems: []string{fmt.Sprintf("content type %q is not documented", mediaType)}}
+	}
+	if mediaType != contentTypeJSON {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return &ContractError{OperationID: operationID, Status: status, Problems: []string{"body is not valid JSON"}}
+	}
+	v := &validator{schemas: d.Components.Schemas}
+	v.validate("$", media.Schema, value)
+	if len(v.problems) > 0 {
+		sort.Strings(v.problems)
+		return &ContractError{OperationID: operationID, Status: status, Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	schemas  map[string]*Schema
+	problems []string
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = v.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func (v *validator) validate(path string, s *Schema, value interface{}) {
+	s = v.resolve(s)
+	if s == nil || s.Type == "" {
+		return
+	}
+	if value == nil {
+		if !s.Nullable {
+			v.fail(path, "must not be null")
+		}
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(path, "expected object")
+			return
+		}
+		v.validateObject(path, s, obj)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.fail(path, "expected array")
+			return
+		}
+		for idx, item := range items {
+			v.validate(fmt.Sprintf("%s[%d]", path, idx), s.Items, item)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(path, "expected string")
+			return
+		}
+		v.validateString(path, s, str)
+	case "integer":
+		num, ok := value.(json.Number)
+		if _, err := num.Int64(); !ok || err != nil {
+			v.fail(path, "expected integer")
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			v.fail(path, "expected number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "expected boolean")
+		}
+	}
+}
+
+func (v *validator) validateObject(path string, s *Schema, obj map[string]interface{}) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			v.fail(path+"."+name, "missing required property")
+		}
+	}
+	for name, value := range obj {
+		if prop, ok := s.Properties[name]; ok {
+			v.validate(path+"."+name, prop, value)
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				v.fail(path+"."+name, "undocumented property")
+			}
+		case *Schema:
+			v.validate(path+"."+name, extra, value)
+		}
+	}
+}
+
+func (v *validator) validateString(path string, s *Schema, str string) {
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			v.fail(path, "expected RFC 3339 date-time")
+		}
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			v.fail(path, "expected uuid")
+		}
+	}
+	if len(s.Enum) == 0 {
+		return
+	}
+	for _, allowed := range s.Enum {
+		if str == allowed {
+			return
+		}
+	}
+	v.fail(path, "%q is not one of %s", str, strings.Join(s.Enum, ", "))
+}
@@ -0,0 +1,270 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Document struct {
+	pages  []*page
+	images []*imageObject
+}
+
+type page struct {
+	content bytes.Buffer
+	images  map[string]int
+}
+
+type imageObject struct {
+	name       string
+	width      int
+	height     int
+	colorSpace string
+	filter     string
+	data       []byte
+}
+
+type Image struct {
+	ref    *imageObject
+	Width  int
+	Height int
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &page{images: map[string]int{}})
+}
+
+func (d *Document) current() *page {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *Document) Text(x, y, size float64, text string) {
+	if text == "" {
+		return
+	}
+	p := d.current()
+	fmt.Fprintf(&p.content, "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, PageHeight-y, encodeUTF16(text))
+}
+
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	p := d.current()
+	fmt.Fprintf(&p.content, "0.6 G 0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+func (d *Document) LoadImage(data []byte) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image config: %w", err)
+	}
+	obj := &imageObject{
+		name:   fmt.Sprintf("Im%d", len(d.images)+1),
+		width:  cfg.Width,
+		height: cfg.Height,
+	}
+	if format == "jpeg" {
+		if cs, ok := jpegColorSpace(data); ok {
+			obj.colorSpace = cs
+			obj.filter = "DCTDecode"
+			obj.data = data
+		}
+	}
+	if obj.data == nil {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode image: %w", err)
+		}
+		rgba := image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Over)
+		raw := make([]byte, 0, cfg.Width*cfg.Height*3)
+		for i := 0; i < len(rgba.Pix); i += 4 {
+			raw = append(raw, rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2])
+		}
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(raw); err != nil {
+			return nil, fmt.Errorf("compress image: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("compress image: %w", err)
+		}
+		obj.colorSpace = "DeviceRGB"
+		obj.filter = "FlateDecode"
+		obj.data = buf.Bytes()
+	}
+	d.images = append(d.images, obj)
+	return &Image{ref: obj, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+func (d *Document) DrawImage(img *Image, x, y, w, h float64) {
+	if img == nil || img.ref == nil {
+		return
+	}
+	p := d.current()
+	p.images[img.ref.name] = 1
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", w, h, x, PageHeight-y-h, img.ref.name)
+}
+
+func TextWidth(text string, size float64) float64 {
+	width := 0.0
+	for _, r := range text {
+		if r < 0x80 {
+			width += 0.5
+		} else {
+			width += 1
+		}
+	}
+	return width * size
+}
+
+func WrapText(text string, size, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		var (
+			line  []rune
+			width float64
+		)
+		for _, r := range paragraph {
+			w := TextWidth(string(r), size)
+			if width+w > maxWidth && len(line) > 0 {
+				cut := len(line)
+				if r != ' ' {
+					for i := len(line) - 1; i > 0; i-- {
+						if line[i] == ' ' {
+							cut = i
+							break
+						}
+						if line[i] >= 0x80 {
+							break
+						}
+					}
+				}
+				lines = append(lines, strings.TrimSpace(string(line[:cut])))
+				line = append([]rune{}, line[cut:]...)
+				width = TextWidth(string(line), size)
+			}
+			line = append(line, r)
+			width += w
+		}
+		lines = append(lines, strings.TrimSpace(string(line)))
+	}
+	return lines
+}
+
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	writeObj := func(body string) int {
+		offsets = append(offsets, buf.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", id, body)
+		return id
+	}
+	writeStream := func(dict string, data []byte) int {
+		offsets = append(offsets, buf.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+		return id
+	}
+
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	pagesIdx := len(offsets)
+	offsets = append(offsets, 0)
+
+	descID := writeObj("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	cidID := writeObj(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> /FontDescriptor %d 0 R /DW 1000 /W [1 95 500] >>", descID))
+	fontID := writeObj(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light-UniGB-UCS2-H /Encoding /UniGB-UCS2-H /DescendantFonts [%d 0 R] >>", cidID))
+
+	imageIDs := make(map[string]int, len(d.images))
+	for _, img := range d.images {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s", img.width, img.height, img.colorSpace, img.filter)
+		if img.colorSpace == "DeviceCMYK" {
+			dict += " /Decode [1 0 1 0 1 0 1 0]"
+		}
+		imageIDs[img.name] = writeStream(dict, img.data)
+	}
+
+	pageIDs := make([]int, 0, len(d.pages))
+	for _, p := range d.pages {
+		contentID := writeStream("", p.content.Bytes())
+		var xobjects strings.Builder
+		for name := range p.images {
+			fmt.Fprintf(&xobjects, " /%s %d 0 R", name, imageIDs[name])
+		}
+		resources := fmt.Sprintf("/Font << /F1 %d 0 R >>", fontID)
+		if xobjects.Len() > 0 {
+			resources += " /XObject <<" + xobjects.String() + " >>"
+		}
+		pageIDs = append(pageIDs, writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>", PageWidth, PageHeight, resources, contentID)))
+	}
+
+	kids := make([]string, len(pageIDs))
+	for i, id := range pageIDs {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	offsets[pagesIdx] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", pagesIdx+1, strings.Join(kids, " "), len(pageIDs))
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+func encodeUTF16(text string) string {
+	units := utf16.Encode([]rune(text))
+	raw := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		raw = append(raw, byte(u>>8), byte(u))
+	}
+	return strings.ToUpper(hex.EncodeToString(raw))
+}
+
+func jpegColorSpace(data []byte) (string, bool) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		return "DeviceGray", true
+	case color.YCbCrModel, color.RGBAModel:
+		return "DeviceRGB", true
+	case color.CMYKModel:
+		return "DeviceCMYK", true
+	}
+	return "", false
+}
@@ -13,11 +13,13 @@ from app_api.models.schemas import (
     CreateStoryboardRequest, CreateStoryboardResponse,
     RegenerateShotRequest, RegenerateShotResponse,
     RenderVideoRequest, RenderVideoResponse,
+    ShotImageRequest, ShotImageResponse,
+    ShotAudioRequest, ShotAudioResponse,
     OperationStatus, Shot
 )
 from app_api.services.llm import generate_storyboard_shots, optimize_i2v_response, run_t2i_api
 from app_api.services.i2v import run_i2v
-from app_api.services.ffmpeg_merge import concat_clips
+from app_api.services.ffmpeg_merge import concat_clips, burn_subtitles
 from app_api.services.tts_v2 import generate_tts_audio
 import shutil
 from app_api.services.oss import upload_to_oss
@@ -30,14 +32,13 @@ router = APIRouter(prefix="/api/v1")
 
 
 _render_lock: Lock = Lock()
+_shots_lock: Lock = Lock()
 _current_processing: bool = False
 _render_queue = deque()
 _MAX_QUEUE_LEN: int = 100
 
 
-@router.post("/storyboard/create", response_model=CreateStoryboardResponse)
-def create_storyboard(req: CreateStoryboardRequest, background_tasks: BackgroundTasks):
-    logger.info(f"CreateStoryboardTask 开始: op={req.operation_id}, story={req.story_id}")
+def _plan_storyboard_shots(req: CreateStoryboardRequest) -> List[Shot]:
     upsert_story(req.user_id, req.story_id, req.display_name, req.style, req.script_content)
     try:
         shots_raw = generate_storyboard_shots("style:" + req.style + "风格 ;" + req.script_content)
@@ -59,13 +60,62 @@ def create_storyboard(req: CreateStoryboardRequest, background_tasks: Background
             style=s.get('style'),
         )
         processed_shots.append(shot)
+    return processed_shots
+
+
+def _story_dirs(user_id: str, story_id: str):
     # 目录结构：OUTPUT_DIR/user_id/story_id/{json,T2I,I2V}
-    base_dir = OUTPUT_DIR / req.user_id / req.story_id
+    base_dir = OUTPUT_DIR / user_id / story_id
     json_dir = base_dir / "json"
     t2i_dir = base_dir / "T2I"
     i2v_dir = base_dir / "I2V"
     for d in (json_dir, t2i_dir, i2v_dir):
         d.mkdir(parents=True, exist_ok=True)
+    return json_dir, t2i_dir, i2v_dir
+
+
+def _save_planned_shots(req: CreateStoryboardRequest, json_dir: Path, processed_shots: List[Shot]) -> None:
+    # 保存 shots 初始结构到“数据库”
+    save_story_shots(req.user_id, req.story_id, [shot.dict() for shot in processed_shots])
+    import json as _json
+    (json_dir / "shots.json").write_text(_json.dumps({"story_id": req.story_id, "shots": [shot.dict() for shot in processed_shots]}, ensure_ascii=False, indent=2), encoding="utf-8")
+    try:
+        raw_path = OUTPUT_DIR / "dashscope_raw.txt"
+        if raw_path.exists():
+            (json_dir / "dashscope_raw.txt").write_text(raw_path.read_text(encoding="utf-8"), encoding="utf-8")
+    except Exception:
+        pass
+
+
+def _update_stored_shot(user_id: str, story_id: str, shot_id: str, sequence: int, fields: dict) -> None:
+    # 分阶段接口会并发更新同一故事的分镜列表，需串行读写
+    with _shots_lock:
+        shots_list = get_story_shots(user_id, story_id)
+        for s in shots_list:
+            if s.get('id') == shot_id or (sequence and int(s.get('sequence') or 0) == sequence):
+                s.update(fields)
+                upsert_shot(user_id, story_id, s.get('id', shot_id), s)
+                break
+        if shots_list:
+            save_story_shots(user_id, story_id, shots_list)
+
+
+def _apply_render_shots(shots_list: list, render_shots: list) -> None:
+    # 旁白可能在后端被编辑或重新生成，以请求携带的旁白和音频为准；音频为空时渲染阶段按新旁白重新合成
+    for rs in render_shots:
+        for s in shots_list:
+            if s.get('id') == rs.shot_id or (rs.sequence and int(s.get('sequence') or 0) == rs.sequence):
+                if rs.narration is not None:
+                    s['narration'] = rs.narration
+                s['audio_url'] = rs.audio_url or None
+                break
+
+
+@router.post("/storyboard/create", response_model=CreateStoryboardResponse)
+def create_storyboard(req: CreateStoryboardRequest, background_tasks: BackgroundTasks):
+    logger.info(f"CreateStoryboardTask 开始: op={req.operation_id}, story={req.story_id}")
+    processed_shots = _plan_storyboard_shots(req)
+    json_dir, t2i_dir, _ = _story_dirs(req.user_id, req.story_id)
 
     # 在生成分镜后，同步执行文生图（生成关键帧）并生成 image_url
     num_gpu_workers = 2
@@ -95,22 +145,62 @@ def create_storyboard(req: CreateStoryboardRequest, background_tasks: Background
         else:
             logger.warning(f"Shot {shot.sequence} 关键帧文件不存在: {keyframe}")
 
-    # 保存 shots 初始结构到“数据库”
-    save_story_shots(req.user_id, req.story_id, [shot.dict() for shot in processed_shots])
-    import json as _json
-    (json_dir / "shots.json").write_text(_json.dumps({"story_id": req.story_id, "shots": [shot.dict() for shot in processed_shots]}, ensure_ascii=False, indent=2), encoding="utf-8")
-    try:
-        raw_path = OUTPUT_DIR / "dashscope_raw.txt"
-        if raw_path.exists():
-            (json_dir / "dashscope_raw.txt").write_text(raw_path.read_text(encoding="utf-8"), encoding="utf-8")
-    except Exception:
-        pass
+    _save_planned_shots(req, json_dir, processed_shots)
 
     # 仅生成分镜并落库，按接口规范立即标记为 Success
     update_operation(req.user_id, req.operation_id, "Success")
     return CreateStoryboardResponse(operation=OperationStatus(operation_id=req.operation_id, status="Success"), shots=processed_shots)
 
 
+@router.post("/storyboard/shots", response_model=CreateStoryboardResponse)
+def generate_storyboard(req: CreateStoryboardRequest):
+    logger.info(f"GenerateShots 开始: op={req.operation_id}, story={req.story_id}")
+    processed_shots = _plan_storyboard_shots(req)
+    json_dir, _, _ = _story_dirs(req.user_id, req.story_id)
+    # 仅生成分镜脚本，关键帧与配音由后续阶段逐镜头生成
+    _save_planned_shots(req, json_dir, processed_shots)
+    update_operation(req.user_id, req.operation_id, "Success")
+    return CreateStoryboardResponse(operation=OperationStatus(operation_id=req.operation_id, status="Success"), shots=processed_shots)
+
+
+@router.post("/shot/image", response_model=ShotImageResponse)
+def generate_shot_image(req: ShotImageRequest):
+    logger.info(f"GenerateShotImage 开始: op={req.operation_id}, story={req.story_id}, shot={req.shot_id}")
+    _, t2i_dir, _ = _story_dirs(req.user_id, req.story_id)
+    # 与整体创建流程保持相同的关键帧命名，合成阶段按序号读取
+    keyframe = t2i_dir / f"shot_{req.sequence:02d}_keyframe.png" if req.sequence else t2i_dir / f"{req.shot_id}_keyframe.png"
+    subject = f"画面的主体是{req.subject}:" if req.subject else ""
+    style = f"style:{req.style}风格 ;" if req.style else ""
+    text_prompt = f"{style}{subject} {req.prompt or ''}"
+    run_t2i_api(text_prompt, keyframe)
+    if not keyframe.exists():
+        update_operation(req.user_id, req.operation_id, "Failed", detail="关键帧生成失败")
+        from fastapi import HTTPException
+        raise HTTPException(status_code=502, detail="文生图失败，请稍后重试")
+    object_key = f"story/{req.user_id}/{req.story_id}/t2i/{req.shot_id}/keyframe.png"
+    url = upload_to_oss(object_key, keyframe)
+    image_url = url or f"/static/{req.user_id}/{req.story_id}/T2I/{keyframe.name}"
+    _update_stored_shot(req.user_id, req.story_id, req.shot_id, req.sequence, {'image_url': image_url})
+    update_operation(req.user_id, req.operation_id, "Success")
+    return ShotImageResponse(operation=OperationStatus(operation_id=req.operation_id, status="Success"), image_url=image_url)
+
+
+@router.post("/shot/audio", response_model=ShotAudioResponse)
+def synthesize_shot_audio(req: ShotAudioRequest):
+    logger.info(f"SynthesizeShotAudio 开始: op={req.operation_id}, story={req.story_id}, shot={req.shot_id}")
+    audio_url = ""
+    audio_duration = 0.0
+    if req.text.strip():
+        audio_url, audio_duration = generate_tts_audio(req.text, req.user_id, req.story_id, req.shot_id)
+        if not audio_url:
+            update_operation(req.user_id, req.operation_id, "Failed", detail="TTS 音频生成失败")
+            from fastapi import HTTPException
+            raise HTTPException(status_code=502, detail="TTS 音频生成失败，请稍后重试")
+    _update_stored_shot(req.user_id, req.story_id, req.shot_id, req.sequence, {'audio_url': audio_url or None})
+    update_operation(req.user_id, req.operation_id, "Success")
+    return ShotAudioResponse(operation=OperationStatus(operation_id=req.operation_id, status="Success"), audio_url=audio_url, audio_duration=audio_duration)
+
+
 @router.post("/shot/regenerate", response_model=RegenerateShotResponse)
 def regenerate_shot(req: RegenerateShotRequest, background_tasks: BackgroundTasks):
     logger.info(f"RegenerateShot 开始: op={req.operation_id}, user={req.user_id}, story={req.story_id}, shot={req.shot_id}")
@@ -212,6 +302,8 @@ def render_video(req: RenderVideoRequest, background_tasks: BackgroundTasks):
 
     def worker_concat():
         shots_list = get_story_shots(user_id, story_id)
+        if req.shots is not None:
+            _apply_render_shots(shots_list, req.shots)
         if shots_list:
             # 优化图生视频响应
             logger.info(f"开始优化图生视频响应，包含{len(shots_list)}个分镜")
@@ -266,8 +358,10 @@ def render_video(req: RenderVideoRequest, background_tasks: BackgroundTasks):
                 narration = s.get('narration', '')
                 shot_id = s.get('id', f"shot_{s.get('sequence', 0):02d}")
                 
-                if narration and narration.strip():
-                    audio_url = generate_tts_audio(narration, user_id, story_id, shot_id)
+                if s.get('audio_url'):
+                    logger.info(f"Shot {shot_id}: 复用已生成的 TTS 音频 {s['audio_url']}")
+                elif narration and narration.strip():
+                    audio_url, _ = generate_tts_audio(narration, user_id, story_id, shot_id)
                     s['audio_url'] = audio_url
                     if audio_url:
                         logger.info(f"Shot {shot_id}: TTS 音频已生成 {audio_url}")
@@ -414,6 +508,15 @@ def render_video(req: RenderVideoRequest, background_tasks: BackgroundTasks):
                     f.write(f"file '{p.resolve()}'\n")
             concat_clips(list_file, final_out)
             logger.info(f"视频合并完成: {final_out}")
+            if req.subtitles_srt and req.subtitles_srt.strip() and final_out.exists():
+                srt_path = i2v_dir / "subtitles.srt"
+                srt_path.write_text(req.subtitles_srt, encoding="utf-8")
+                subtitled_out = i2v_dir / "final_subtitled.mp4"
+                if burn_subtitles(final_out, srt_path, subtitled_out):
+                    shutil.move(str(subtitled_out), str(final_out))
+                    logger.info(f"字幕已烧录: {final_out}")
+                else:
+                    logger.warning("字幕烧录失败，使用无字幕视频")
         else:
             logger.warning("没有找到任何分镜视频用于合并")
         
@@ -55,13 +55,49 @@ class RegenerateShotResponse(BaseModel):
     operation: OperationStatus
     shot: Shot
 
+class RenderShotAudio(BaseModel):
+    shot_id: str
+    sequence: int = 0
+    narration: Optional[str] = None
+    audio_url: Optional[str] = None
+
 class RenderVideoRequest(BaseModel):
     operation_id: str
     story_id: str
     user_id: str
     multi: int = Field(2, description="视频增强多帧参数，默认 2")
     scale: int = Field(2, description="视频增强超分倍数，默认 2")
+    subtitles_srt: Optional[str] = Field(None, description="由后端按旁白生成的 SRT 字幕，渲染时烧录进最终视频")
+    shots: Optional[List[RenderShotAudio]] = Field(None, description="后端记录的各分镜旁白与已合成音频，渲染时以此为准，不复用本地缓存的音频")
 
 class RenderVideoResponse(BaseModel):
     operation: OperationStatus
-    video_url: str
\ No newline at end of file
+    video_url: str
+
+class ShotImageRequest(BaseModel):
+    operation_id: str
+    story_id: str
+    user_id: str
+    shot_id: str
+    sequence: int = 0
+    subject: Optional[str] = None
+    prompt: Optional[str] = None
+    style: Optional[str] = None
+
+class ShotImageResponse(BaseModel):
+    operation: OperationStatus
+    image_url: str
+
+class ShotAudioRequest(BaseModel):
+    operation_id: str
+    story_id: str
+    user_id: str
+    shot_id: str
+    sequence: int = 0
+    text: str
+    voice: Optional[str] = None
+
+class ShotAudioResponse(BaseModel):
+    operation: OperationStatus
+    audio_url: str
+    audio_duration: float = 0
@@ -23,6 +23,26 @@ def merge_clip(video_path: Path, audio_path: Path, output_path: Path) -> bool:
         return False
 
 
+def burn_subtitles(video_path: Path, srt_path: Path, output_path: Path) -> bool:
+    """将 SRT 字幕烧录进视频画面，音频流保持不变"""
+    if not video_path.exists() or not srt_path.exists():
+        return False
+    # subtitles 滤镜参数中的反斜杠、冒号与单引号需要转义
+    escaped = str(srt_path.resolve()).replace('\\', '\\\\').replace(':', '\\:').replace("'", "\\'")
+    try:
+        (
+            ffmpeg
+            .input(str(video_path))
+            .output(str(output_path), vf=f"subtitles='{escaped}'", vcodec='libx264', acodec='copy')
+            .overwrite_output()
+            .run(quiet=True)
+        )
+        return True
+    except Exception as e:
+        logger.error(f"字幕烧录失败: {e}")
+        return False
+
+
 def concat_clips(list_file: Path, final_out: Path) -> bool:
     try:
         ffmpeg.input(str(list_file), f='concat', safe=0).output(str(final_out), c='copy').overwrite_output().run(quiet=True)
@@ -1,12 +1,13 @@
 # -*- coding: utf-8 -*-
 from pathlib import Path
+from typing import Tuple
 import os
 from app_api.core.logging import logger
 from app_api.core.config import DASHSCOPE_API_KEY, OUTPUT_DIR
 from app_api.services.oss import upload_to_oss
 
 
-def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) -> str:
+def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) -> Tuple[str, float]:
     """
     使用 CosyVoice 生成语音文件并上传到 OSS
     Args:
@@ -16,15 +17,15 @@ def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) ->
         shot_id: 分镜ID
     
     Returns:
-        str: OSS 上的音频文件 URL，失败返回空字符串
+        Tuple[str, float]: OSS 上的音频文件 URL 与补齐后的音频时长（秒），失败返回 ("", 0.0)
     """
     if not text or not text.strip():
         logger.warning(f"TTS 文本为空，跳过生成 {user_id}/{story_id}/{shot_id}")
-        return ""
+        return "", 0.0
     
     if not DASHSCOPE_API_KEY:
         logger.error("DASHSCOPE_API_KEY 未配置，无法生成 TTS")
-        return ""
+        return "", 0.0
     
     try:
         import dashscope
@@ -57,21 +58,21 @@ def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) ->
         except Exception as api_error:
             logger.error(f"CosyVoice API 调用异常: {api_error}")
             logger.error(f"请检查：1) API Key 是否有效 2) 是否有 CosyVoice 权限 3) 是否超出配额")
-            return ""
+            return "", 0.0
         
         # 验证返回的音频数据
         if not audio:
             logger.error(f"TTS API 返回空数据: text='{text[:30]}...'")
             logger.error("可能原因: 1) API调用失败 2) 文本无法合成 3) 服务暂时不可用")
-            return ""
+            return "", 0.0
         
         if not isinstance(audio, bytes):
             logger.error(f"TTS API 返回数据类型错误: {type(audio)}, text='{text[:30]}...'")
-            return ""
+            return "", 0.0
         
         if len(audio) < 100:  # 有效的 MP3 文件应该至少有几百字节
             logger.error(f"TTS API 返回数据过小 ({len(audio)} bytes): text='{text[:30]}...'")
-            return ""
+            return "", 0.0
         
         logger.info(f"TTS API 返回音频数据: {len(audio)} bytes")
         
@@ -84,7 +85,7 @@ def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) ->
         except Exception as e:
             logger.error(f"解析 TTS 音频数据失败: {e}")
             logger.error(f"音频数据前 100 字节 (hex): {audio[:100].hex()}")
-            return ""
+            return "", 0.0
         
         duration_ms = len(audio_segment)
         duration_sec = duration_ms / 1000.0
@@ -100,6 +101,7 @@ def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) ->
         
         # 保存到本地
         audio_segment.export(local_path, format="mp3")
+        final_duration_sec = len(audio_segment) / 1000.0
         
         try:
             request_id = speech_synthesizer.get_last_request_id()
@@ -114,13 +116,13 @@ def generate_tts_audio(text: str, user_id: str, story_id: str, shot_id: str) ->
         
         if audio_url:
             logger.info(f"TTS 音频上传成功: {audio_url}")
-            return audio_url
+            return audio_url, final_duration_sec
         else:
             logger.warning(f"TTS 音频上传失败，返回本地路径")
-            return f"/static/{user_id}/{story_id}/tts/{filename}"
+            return f"/static/{user_id}/{story_id}/tts/{filename}", final_duration_sec
             
     except Exception as e:
         logger.error(f"TTS 音频生成失败: {e}")
         import traceback
         logger.error(f"详细错误: {traceback.format_exc()}")
-        return ""
+        return "", 0.0